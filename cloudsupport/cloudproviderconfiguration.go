@@ -45,6 +45,7 @@ func GetCloudProvider(nodeList *corev1.NodeList) string {
 // GetDescriptiveInfoFromCloudProvider returns the cluster description from the cloud provider wrapped in IMetadata obj
 func GetDescriptiveInfoFromCloudProvider(cluster string, cloudProvider string) (workloadinterface.IMetadata, error) {
 	var clusterInfo *cloudsupportv1.CloudProviderDescribe
+	k8sinterface.GetLogger().Debug("describing the cluster with the cloud provider", "cluster", cluster, "cloudProvider", cloudProvider)
 
 	switch cloudProvider {
 	case cloudsupportv1.EKS:
@@ -95,6 +96,7 @@ func GetDescriptiveInfoFromCloudProvider(cluster string, cloudProvider string) (
 // GetDescribeRepositoriesFromCloudProvider returns image repository descriptions from the cloud provider wrapped in IMetadata obj
 func GetDescribeRepositoriesFromCloudProvider(cluster string, cloudProvider string) (workloadinterface.IMetadata, error) {
 	var clusterInfo *cloudsupportv1.CloudProviderDescribeRepositories
+	k8sinterface.GetLogger().Debug("describing the image repositories with the cloud provider", "cluster", cluster, "cloudProvider", cloudProvider)
 
 	switch cloudProvider {
 	case cloudsupportv1.EKS:
@@ -123,6 +125,7 @@ func GetDescribeRepositoriesFromCloudProvider(cluster string, cloudProvider stri
 // GetListEntitiesForPoliciesFromCloudProvider returns EntitiesForpolicies from the cloud provider wrapped in IMetadata obj
 func GetListEntitiesForPoliciesFromCloudProvider(cluster string, cloudProvider string) (workloadinterface.IMetadata, error) {
 	var listEntitiesForPolicies *cloudsupportv1.CloudProviderListEntitiesForPolicies
+	k8sinterface.GetLogger().Debug("listing the entities of the policies with the cloud provider", "cluster", cluster, "cloudProvider", cloudProvider)
 
 	switch cloudProvider {
 	case cloudsupportv1.EKS:
@@ -162,6 +165,7 @@ func GetListEntitiesForPoliciesFromCloudProvider(cluster string, cloudProvider s
 // GetPolicyVersionFromCloudProvider returns PolicyVersion from the cloud provider wrapped in IMetadata obj
 func GetPolicyVersionFromCloudProvider(cluster string, cloudProvider string) (workloadinterface.IMetadata, error) {
 	var policyVersion *cloudsupportv1.CloudProviderPolicyVersion
+	k8sinterface.GetLogger().Debug("getting the policy versions with the cloud provider", "cluster", cluster, "cloudProvider", cloudProvider)
 
 	switch cloudProvider {
 	case cloudsupportv1.EKS:
@@ -17,6 +17,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/ecr"
 	"github.com/docker/docker/api/types/registry"
 	cloudsupportv1 "github.com/kubescape/k8s-interface/cloudsupport/v1"
+	"github.com/kubescape/k8s-interface/k8sinterface"
 )
 
 // For GCR there are some permissions one need to assign in order to allow ARMO to pull images:
@@ -104,6 +105,7 @@ func getAzureAADAccessToken() (string, error) {
 	req.Header.Add("Metadata", "true")
 
 	// Call managed services for Azure resources token endpoint
+	k8sinterface.GetLogger().Debug("fetching an Azure access token from the instance metadata service", "clientID", msi_parameters.Get("client_id"))
 	resp, err := httpClient.Do(req)
 	if err != nil {
 		return "", fmt.Errorf("calling token endpoint : %v", err)
@@ -213,6 +215,7 @@ func GetCloudVendorRegistryCredentials(imageTag string) (map[string]registry.Aut
 	secrets := map[string]registry.AuthConfig{}
 	var errRes error
 	if CheckIsACRImage(imageTag) {
+		k8sinterface.GetLogger().Debug("fetching registry credentials from the cloud provider", "imageTag", imageTag, "registry", "ACR")
 		userName, password, err := GetLoginDetailsForAzurCR(imageTag)
 		if err != nil {
 			errRes = fmt.Errorf("failed to GetLoginDetailsForACR(%s): %v", imageTag, err)
@@ -225,6 +228,7 @@ func GetCloudVendorRegistryCredentials(imageTag string) (map[string]registry.Aut
 	}
 
 	if CheckIsECRImage(imageTag) {
+		k8sinterface.GetLogger().Debug("fetching registry credentials from the cloud provider", "imageTag", imageTag, "registry", "ECR")
 		userName, password, err := GetLoginDetailsForECR(imageTag)
 		if err != nil {
 			errRes = fmt.Errorf("failed to GetLoginDetailsForECR(%s): %v", imageTag, err)
@@ -237,6 +241,7 @@ func GetCloudVendorRegistryCredentials(imageTag string) (map[string]registry.Aut
 	}
 
 	if CheckIsGCRImage(imageTag) {
+		k8sinterface.GetLogger().Debug("fetching registry credentials from the cloud provider", "imageTag", imageTag, "registry", "GCR")
 		userName, password, err := GetLoginDetailsForGCR(imageTag)
 		if err != nil {
 			errRes = fmt.Errorf("failed to GetLoginDetailsForGCR(%s): %v", imageTag, err)
@@ -4,9 +4,6 @@ import (
 	"context"
 	"fmt"
 
-	logger "github.com/kubescape/go-logger"
-	"github.com/kubescape/go-logger/helpers"
-
 	"github.com/armosec/utils-k8s-go/secrethandling"
 	"github.com/docker/docker/api/types/registry"
 	"github.com/kubescape/k8s-interface/k8sinterface"
@@ -48,12 +45,12 @@ func getImagePullSecret(k8sAPI *k8sinterface.KubernetesApi, secrets []string, na
 	for i := range secrets {
 		res, err := k8sAPI.KubernetesClient.CoreV1().Secrets(namespace).Get(context.Background(), secrets[i], metav1.GetOptions{})
 		if err != nil {
-			logger.L().Error("unable to get secret", helpers.String("secret name", secrets[i]), helpers.Error(err))
+			k8sAPI.GetLogger().Error("unable to get secret", err, "secret name", secrets[i])
 			continue
 		}
 		sec, err := secrethandling.ParseSecret(res, secrets[i])
 		if err != nil {
-			logger.L().Error("failed to pars secret", helpers.String("secret name", secrets[i]), helpers.Error(err))
+			k8sAPI.GetLogger().Error("failed to pars secret", err, "secret name", secrets[i])
 			continue
 		}
 		secretsAuthConfig[secrets[i]] = *sec
@@ -80,7 +77,7 @@ func GetImageRegistryCredentials(imageTag string, pod *corev1.Pod) (map[string]r
 	if imageTag != "" {
 		cloudVendorSecrets, err := GetCloudVendorRegistryCredentials(imageTag)
 		if err != nil {
-			logger.L().Debug("failed to GetCloudVendorRegistryCredentials", helpers.String("imageTag", imageTag), helpers.Error(err))
+			k8sAPI.GetLogger().Debug("failed to GetCloudVendorRegistryCredentials", "imageTag", imageTag, "error", err)
 		} else if len(cloudVendorSecrets) > 0 {
 			for secName := range cloudVendorSecrets {
 				secrets[secName] = cloudVendorSecrets[secName]
@@ -92,7 +89,7 @@ func GetImageRegistryCredentials(imageTag string, pod *corev1.Pod) (map[string]r
 
 			cloudVendorSecrets, err := GetCloudVendorRegistryCredentials(imageTag)
 			if err != nil {
-				logger.L().Debug("failed to GetCloudVendorRegistryCredentials", helpers.String("imageTag", imageTag), helpers.Error(err))
+				k8sAPI.GetLogger().Debug("failed to GetCloudVendorRegistryCredentials", "imageTag", imageTag, "error", err)
 			} else if len(cloudVendorSecrets) > 0 {
 				for secName := range cloudVendorSecrets {
 					secrets[secName] = cloudVendorSecrets[secName]
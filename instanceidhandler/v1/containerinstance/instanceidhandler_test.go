@@ -151,7 +151,7 @@ func TestInstanceIDToDisplayName(t *testing.T) {
 			got, err := tc.input.GetSlug(false)
 
 			assert.Equal(t, tc.want, got)
-			assert.ErrorIs(t, tc.wantErr, err)
+			assert.ErrorIs(t, err, tc.wantErr)
 		})
 	}
 }
@@ -0,0 +1,167 @@
+package k8sinterface
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// AccessCheck is an action whose permission CanIAll checks, e.g. listing Deployments in a namespace
+type AccessCheck struct {
+	Verb        string
+	Resource    schema.GroupVersionResource
+	Subresource string
+	// Namespace is the namespace of the action, empty for cluster-scoped resources or for all namespaces
+	Namespace string
+	// Name is the name of the object of the action, empty for all of them
+	Name string
+}
+
+// String returns the action the way kubectl auth can-i takes it, e.g. "list deployments.apps in namespace 'default'"
+func (check AccessCheck) String() string {
+	resource := check.Resource.GroupResource().String()
+	if check.Subresource != "" {
+		resource += "/" + check.Subresource
+	}
+	if check.Name != "" {
+		resource += "/" + check.Name
+	}
+	if check.Namespace == "" {
+		return fmt.Sprintf("%s %s", check.Verb, resource)
+	}
+	return fmt.Sprintf("%s %s in namespace '%s'", check.Verb, resource, check.Namespace)
+}
+
+// AccessCheckResult is whether the action of a check is allowed, and why if the API server tells
+type AccessCheckResult struct {
+	AccessCheck
+	Allowed bool
+	Reason  string
+}
+
+// AccessCheckResults are the results of CanIAll, in the order of its checks
+type AccessCheckResults []AccessCheckResult
+
+// Denied returns the results of the actions which are not allowed
+func (results AccessCheckResults) Denied() AccessCheckResults {
+	denied := AccessCheckResults{}
+	for i := range results {
+		if !results[i].Allowed {
+			denied = append(denied, results[i])
+		}
+	}
+	return denied
+}
+
+// Report lists the actions which are not allowed, one per line, empty if all of them are
+func (results AccessCheckResults) Report() string {
+	denied := results.Denied()
+	if len(denied) == 0 {
+		return ""
+	}
+	var report strings.Builder
+	report.WriteString("missing permissions:")
+	for i := range denied {
+		report.WriteString("\n  - cannot " + denied[i].AccessCheck.String())
+		if denied[i].Reason != "" {
+			report.WriteString(", reason: " + denied[i].Reason)
+		}
+	}
+	return report.String()
+}
+
+// CanI returns whether the user of the KubernetesApi can take a given action, with a SelfSubjectAccessReview, and why if the API server tells.
+// The namespace is empty for cluster-scoped resources or for all namespaces, the name is empty for all the objects of the resource
+func (k8sAPI *KubernetesApi) CanI(ctx context.Context, verb string, groupVersionResource *schema.GroupVersionResource, namespace, name string) (bool, string, error) {
+	return k8sAPI.accessReview(ctx, AccessCheck{Verb: verb, Resource: *groupVersionResource, Namespace: namespace, Name: name})
+}
+
+// CanIAll checks whether the user of the KubernetesApi can take given actions, e.g. to list the missing permissions before scanning
+//
+// The rules of the user in the namespaces of the checks are fetched with a SelfSubjectRulesReview per namespace. The actions these rules
+// do not allow, as well as the cluster-wide ones, are checked with a SelfSubjectAccessReview each, since authorizers other than RBAC,
+// e.g. webhooks, may not list their rules
+func (k8sAPI *KubernetesApi) CanIAll(ctx context.Context, checks []AccessCheck) (AccessCheckResults, error) {
+	rules := map[string][]authorizationv1.ResourceRule{}
+	results := make(AccessCheckResults, len(checks))
+	for i := range checks {
+		results[i].AccessCheck = checks[i]
+
+		if checks[i].Namespace != "" {
+			namespaceRules, ok := rules[checks[i].Namespace]
+			if !ok {
+				review, err := k8sAPI.KubernetesClient.AuthorizationV1().SelfSubjectRulesReviews().Create(ctx, &authorizationv1.SelfSubjectRulesReview{
+					Spec: authorizationv1.SelfSubjectRulesReviewSpec{Namespace: checks[i].Namespace},
+				}, metav1.CreateOptions{})
+				if err != nil {
+					return nil, fmt.Errorf("failed to review access rules, namespace: '%s', reason: %w", checks[i].Namespace, err)
+				}
+				namespaceRules = review.Status.ResourceRules
+				rules[checks[i].Namespace] = namespaceRules
+			}
+			if slices.ContainsFunc(namespaceRules, func(rule authorizationv1.ResourceRule) bool { return ruleAllows(rule, &checks[i]) }) {
+				results[i].Allowed = true
+				continue
+			}
+		}
+
+		allowed, reason, err := k8sAPI.accessReview(ctx, checks[i])
+		if err != nil {
+			return nil, err
+		}
+		results[i].Allowed, results[i].Reason = allowed, reason
+	}
+	return results, nil
+}
+
+func (k8sAPI *KubernetesApi) accessReview(ctx context.Context, check AccessCheck) (bool, string, error) {
+	review, err := k8sAPI.KubernetesClient.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace:   check.Namespace,
+				Verb:        check.Verb,
+				Group:       check.Resource.Group,
+				Version:     check.Resource.Version,
+				Resource:    check.Resource.Resource,
+				Subresource: check.Subresource,
+				Name:        check.Name,
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return false, "", fmt.Errorf("failed to review access, action: '%s', reason: %w", check.String(), err)
+	}
+	reason := review.Status.Reason
+	if reason == "" {
+		reason = review.Status.EvaluationError
+	}
+	return review.Status.Allowed, reason, nil
+}
+
+// ruleAllows returns true if a given rule allows the action of a given check, matching wildcards the way RBAC does
+func ruleAllows(rule authorizationv1.ResourceRule, check *AccessCheck) bool {
+	if !slices.Contains(rule.Verbs, "*") && !slices.Contains(rule.Verbs, check.Verb) {
+		return false
+	}
+	if !slices.Contains(rule.APIGroups, "*") && !slices.Contains(rule.APIGroups, check.Resource.Group) {
+		return false
+	}
+	if len(rule.ResourceNames) != 0 && !slices.Contains(rule.ResourceNames, check.Name) {
+		return false
+	}
+	resource := check.Resource.Resource
+	if check.Subresource != "" {
+		resource += "/" + check.Subresource
+	}
+	return slices.ContainsFunc(rule.Resources, func(ruleResource string) bool {
+		if ruleResource == "*" || ruleResource == resource {
+			return true
+		}
+		return check.Subresource != "" && (ruleResource == check.Resource.Resource+"/*" || ruleResource == "*/"+check.Subresource)
+	})
+}
@@ -0,0 +1,150 @@
+package k8sinterface
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kubernetesfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var (
+	podsResource        = schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	deploymentsResource = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	nodesResource       = schema.GroupVersionResource{Version: "v1", Resource: "nodes"}
+)
+
+// newAccessReviewClient returns a clientset reviewing access with given rules in the default namespace, and allowing listing nodes only,
+// counting the access reviews
+func newAccessReviewClient(rules []authorizationv1.ResourceRule) (*kubernetesfake.Clientset, *int) {
+	client := kubernetesfake.NewSimpleClientset()
+	accessReviews := 0
+	client.PrependReactor("create", "selfsubjectrulesreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectRulesReview)
+		if review.Spec.Namespace == "default" {
+			review.Status.ResourceRules = rules
+		}
+		return true, review, nil
+	})
+	client.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		accessReviews++
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		attributes := review.Spec.ResourceAttributes
+		if attributes.Resource == "nodes" && attributes.Verb == "list" {
+			review.Status.Allowed = true
+		} else {
+			review.Status.Reason = "RBAC: access denied"
+		}
+		return true, review, nil
+	})
+	return client, &accessReviews
+}
+
+func TestCanI(t *testing.T) {
+	k8sAPI := NewKubernetesApiMock()
+	client, _ := newAccessReviewClient(nil)
+	k8sAPI.KubernetesClient = client
+
+	allowed, reason, err := k8sAPI.CanI(context.Background(), "list", &nodesResource, "", "")
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+	assert.Empty(t, reason)
+
+	allowed, reason, err = k8sAPI.CanI(context.Background(), "delete", &nodesResource, "", "node-1")
+	assert.NoError(t, err)
+	assert.False(t, allowed)
+	assert.Equal(t, "RBAC: access denied", reason)
+
+	client.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("connection refused")
+	})
+	_, _, err = k8sAPI.CanI(context.Background(), "list", &nodesResource, "", "")
+	assert.Error(t, err)
+}
+
+func TestCanIAll(t *testing.T) {
+	k8sAPI := NewKubernetesApiMock()
+	client, accessReviews := newAccessReviewClient([]authorizationv1.ResourceRule{
+		{Verbs: []string{"get", "list", "watch"}, APIGroups: []string{""}, Resources: []string{"pods", "pods/log"}},
+		{Verbs: []string{"*"}, APIGroups: []string{"apps"}, Resources: []string{"deployments"}, ResourceNames: []string{"nginx"}},
+	})
+	k8sAPI.KubernetesClient = client
+
+	results, err := k8sAPI.CanIAll(context.Background(), []AccessCheck{
+		{Verb: "list", Resource: podsResource, Namespace: "default"},
+		{Verb: "get", Resource: podsResource, Subresource: "log", Namespace: "default", Name: "nginx"},
+		{Verb: "delete", Resource: podsResource, Namespace: "default"},
+		{Verb: "patch", Resource: deploymentsResource, Namespace: "default", Name: "nginx"},
+		{Verb: "patch", Resource: deploymentsResource, Namespace: "default", Name: "redis"},
+		{Verb: "list", Resource: podsResource, Namespace: "kube-system"},
+		{Verb: "list", Resource: nodesResource},
+	})
+	assert.NoError(t, err)
+
+	allowed := make([]bool, len(results))
+	for i := range results {
+		allowed[i] = results[i].Allowed
+	}
+	assert.Equal(t, []bool{true, true, false, true, false, false, true}, allowed)
+	// the actions the rules do not allow and the cluster-wide ones are reviewed one by one
+	assert.Equal(t, 4, *accessReviews)
+
+	assert.Equal(t, `missing permissions:
+  - cannot delete pods in namespace 'default', reason: RBAC: access denied
+  - cannot patch deployments.apps/redis in namespace 'default', reason: RBAC: access denied
+  - cannot list pods in namespace 'kube-system', reason: RBAC: access denied`, results.Report())
+	assert.Empty(t, results[:2].Report())
+}
+
+func TestRuleAllows(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  authorizationv1.ResourceRule
+		check AccessCheck
+		want  bool
+	}{
+		{
+			name:  "Wildcards",
+			rule:  authorizationv1.ResourceRule{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}},
+			check: AccessCheck{Verb: "delete", Resource: deploymentsResource, Subresource: "scale"},
+			want:  true,
+		},
+		{
+			name:  "Other group",
+			rule:  authorizationv1.ResourceRule{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"deployments"}},
+			check: AccessCheck{Verb: "get", Resource: deploymentsResource},
+		},
+		{
+			name:  "Subresource not granted by its resource",
+			rule:  authorizationv1.ResourceRule{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"pods"}},
+			check: AccessCheck{Verb: "get", Resource: podsResource, Subresource: "log"},
+		},
+		{
+			name:  "Subresources of a resource",
+			rule:  authorizationv1.ResourceRule{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"pods/*"}},
+			check: AccessCheck{Verb: "get", Resource: podsResource, Subresource: "log"},
+			want:  true,
+		},
+		{
+			name:  "Subresource of all resources",
+			rule:  authorizationv1.ResourceRule{Verbs: []string{"update"}, APIGroups: []string{"apps"}, Resources: []string{"*/scale"}},
+			check: AccessCheck{Verb: "update", Resource: deploymentsResource, Subresource: "scale"},
+			want:  true,
+		},
+		{
+			name:  "Resource names not granting all objects",
+			rule:  authorizationv1.ResourceRule{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"pods"}, ResourceNames: []string{"nginx"}},
+			check: AccessCheck{Verb: "list", Resource: podsResource},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ruleAllows(tt.rule, &tt.check))
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package k8sinterface
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+)
+
+// APIResource is a resource served by the API server, in the preferred version of its group
+type APIResource struct {
+	schema.GroupVersionResource
+	Kind       string
+	Namespaced bool
+	Verbs      []string
+	Categories []string
+	ShortNames []string
+}
+
+// APIResourceFilter selects the resources ListAPIResources returns, unset fields select all of them
+type APIResourceFilter struct {
+	// Verbs selects the resources supporting all the given verbs, e.g. "list" and "watch"
+	Verbs []string
+	// Categories selects the resources in any of the given categories, e.g. "all"
+	Categories []string
+	// Namespaced selects the namespaced resources if true, the cluster-scoped ones if false
+	Namespaced *bool
+}
+
+func (filter APIResourceFilter) matches(resource *APIResource) bool {
+	for _, verb := range filter.Verbs {
+		if !slices.Contains(resource.Verbs, verb) {
+			return false
+		}
+	}
+	if len(filter.Categories) != 0 && !slices.ContainsFunc(filter.Categories, func(category string) bool { return slices.Contains(resource.Categories, category) }) {
+		return false
+	}
+	return filter.Namespaced == nil || *filter.Namespaced == resource.Namespaced
+}
+
+// ListAPIResources returns the resources served by the API server selected by a given filter, sorted by group and resource.
+// Subresources are left out
+//
+// Groups whose resources cannot be discovered, e.g. of an unavailable aggregated API server, are left out with a warning
+func (k8sAPI *KubernetesApi) ListAPIResources(filter APIResourceFilter) ([]APIResource, error) {
+	resourceLists, err := discovery.ServerPreferredResources(k8sAPI.DiscoveryClient)
+	if err != nil {
+		if !discovery.IsGroupDiscoveryFailedError(err) {
+			return nil, fmt.Errorf("failed to discover API resources, reason: %s", err.Error())
+		}
+		k8sAPI.GetLogger().Warning("failed to discover the API resources of some groups", "error", err)
+	}
+
+	resources := []APIResource{}
+	for _, resourceList := range resourceLists {
+		if resourceList == nil {
+			continue
+		}
+		gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, apiResource := range resourceList.APIResources {
+			if strings.Contains(apiResource.Name, "/") {
+				continue
+			}
+			resource := APIResource{
+				GroupVersionResource: gv.WithResource(apiResource.Name),
+				Kind:                 apiResource.Kind,
+				Namespaced:           apiResource.Namespaced,
+				Verbs:                apiResource.Verbs,
+				Categories:           apiResource.Categories,
+				ShortNames:           apiResource.ShortNames,
+			}
+			if filter.matches(&resource) {
+				resources = append(resources, resource)
+			}
+		}
+	}
+
+	sort.Slice(resources, func(i, j int) bool {
+		if resources[i].Group != resources[j].Group {
+			return resources[i].Group < resources[j].Group
+		}
+		return resources[i].Resource < resources[j].Resource
+	})
+	return resources, nil
+}
@@ -0,0 +1,68 @@
+package k8sinterface
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/exp/slices"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestListAPIResources(t *testing.T) {
+	k8sAPI := NewKubernetesApiMock()
+	namespaced, clusterScoped := true, false
+
+	tests := []struct {
+		name     string
+		filter   APIResourceFilter
+		contains []schema.GroupVersionResource
+		excludes []schema.GroupVersionResource
+	}{
+		{
+			name:     "No filter",
+			contains: []schema.GroupVersionResource{{Version: "v1", Resource: "pods"}, {Version: "v1", Resource: "bindings"}, {Group: "authentication.k8s.io", Version: "v1", Resource: "tokenreviews"}},
+		},
+		{
+			name:     "Listable and watchable cluster-scoped resources",
+			filter:   APIResourceFilter{Verbs: []string{"list", "watch"}, Namespaced: &clusterScoped},
+			contains: []schema.GroupVersionResource{{Version: "v1", Resource: "nodes"}, {Version: "v1", Resource: "namespaces"}, {Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"}},
+			excludes: []schema.GroupVersionResource{{Version: "v1", Resource: "pods"}, {Version: "v1", Resource: "componentstatuses"}, {Group: "authentication.k8s.io", Version: "v1", Resource: "tokenreviews"}},
+		},
+		{
+			name:     "Namespaced resources in the all category",
+			filter:   APIResourceFilter{Categories: []string{"all"}, Namespaced: &namespaced},
+			contains: []schema.GroupVersionResource{{Version: "v1", Resource: "pods"}, {Version: "v1", Resource: "services"}, {Group: "apps", Version: "v1", Resource: "deployments"}},
+			excludes: []schema.GroupVersionResource{{Version: "v1", Resource: "configmaps"}, {Version: "v1", Resource: "nodes"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resources, err := k8sAPI.ListAPIResources(tt.filter)
+			assert.NoError(t, err)
+			assert.True(t, sort.SliceIsSorted(resources, func(i, j int) bool {
+				if resources[i].Group != resources[j].Group {
+					return resources[i].Group < resources[j].Group
+				}
+				return resources[i].Resource < resources[j].Resource
+			}))
+
+			gvrs := make([]schema.GroupVersionResource, len(resources))
+			for i := range resources {
+				gvrs[i] = resources[i].GroupVersionResource
+				for _, verb := range tt.filter.Verbs {
+					assert.Contains(t, resources[i].Verbs, verb)
+				}
+				if tt.filter.Namespaced != nil {
+					assert.Equal(t, *tt.filter.Namespaced, resources[i].Namespaced)
+				}
+			}
+			for _, gvr := range tt.contains {
+				assert.Contains(t, gvrs, gvr)
+			}
+			for _, gvr := range tt.excludes {
+				assert.False(t, slices.Contains(gvrs, gvr), gvr.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package k8sinterface
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kubescape/k8s-interface/workloadinterface"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ApplyConflict is a field that another field manager owns with a different value
+type ApplyConflict struct {
+	// Field is the path of the conflicting field, e.g. ".spec.replicas"
+	Field string
+	// Message names the manager owning the field, e.g. `conflict with "kubectl" using apps/v1`
+	Message string
+}
+
+// ApplyConflictError is returned by ApplyResource when the applied object sets fields owned by other field managers
+//
+// Apply again with force to take ownership of the conflicting fields, or drop them from the applied object
+type ApplyConflictError struct {
+	Resource  string
+	Namespace string
+	Name      string
+	Conflicts []ApplyConflict
+	Err       error
+}
+
+func (e *ApplyConflictError) Error() string {
+	fields := make([]string, len(e.Conflicts))
+	for i := range e.Conflicts {
+		fields[i] = e.Conflicts[i].Field
+	}
+	return fmt.Sprintf("failed to APPLY resource, resource: '%s', namespace: '%s', name: '%s', conflicting fields: [%s], reason: %s", e.Resource, e.Namespace, e.Name, strings.Join(fields, ", "), e.Err.Error())
+}
+
+func (e *ApplyConflictError) Unwrap() error {
+	return e.Err
+}
+
+// ApplyResource applies a given object to a given resource with server-side apply, as a given field manager, and returns the object as persisted
+//
+// The object needs an apiVersion, a kind and a name. If it sets fields owned by other managers, it returns an *ApplyConflictError,
+// unless force is set, in which case the field manager takes ownership of them
+func (k8sAPI *KubernetesApi) ApplyResource(ctx context.Context, groupVersionResource *schema.GroupVersionResource, workload IWorkload, fieldManager string, force bool) (IWorkload, error) {
+	if fieldManager == "" {
+		return nil, fmt.Errorf("failed to APPLY resource, reason: field manager is required")
+	}
+	obj, err := workload.ToUnstructured()
+	if err != nil {
+		return nil, err
+	}
+	if obj.GetName() == "" {
+		return nil, fmt.Errorf("failed to APPLY resource, workload: '%s', reason: name is required", workload.ToString())
+	}
+	// an apply request must not carry managed fields, the server computes them
+	obj.SetManagedFields(nil)
+
+	w, err := k8sAPI.ResourceInterface(groupVersionResource, obj.GetNamespace()).Apply(ctx, obj.GetName(), obj, metav1.ApplyOptions{
+		FieldManager: fieldManager,
+		Force:        force,
+		DryRun:       dryRunOptions(ctx),
+	})
+	if err != nil {
+		if apierrors.IsConflict(err) {
+			return nil, &ApplyConflictError{
+				Resource:  groupVersionResource.String(),
+				Namespace: obj.GetNamespace(),
+				Name:      obj.GetName(),
+				Conflicts: applyConflicts(err),
+				Err:       err,
+			}
+		}
+		return nil, fmt.Errorf("failed to APPLY resource, workload: '%s', reason: %s", workload.ToString(), err.Error())
+	}
+	return workloadinterface.NewWorkloadObj(w.Object), nil
+}
+
+// applyConflicts returns the field manager conflicts listed in the causes of an API error
+func applyConflicts(err error) []ApplyConflict {
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) || status.Status().Details == nil {
+		return nil
+	}
+	var conflicts []ApplyConflict
+	for _, cause := range status.Status().Details.Causes {
+		if cause.Type != metav1.CauseTypeFieldManagerConflict {
+			continue
+		}
+		conflicts = append(conflicts, ApplyConflict{Field: cause.Field, Message: cause.Message})
+	}
+	return conflicts
+}
@@ -0,0 +1,106 @@
+package k8sinterface
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kubescape/k8s-interface/workloadinterface"
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestApplyResource(t *testing.T) {
+	deploymentsGVR := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	deployment := func(name string) IWorkload {
+		return workloadinterface.NewWorkloadObj(map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": "default",
+			},
+			"spec": map[string]interface{}{
+				"replicas": int64(3),
+			},
+		})
+	}
+	conflict := apierrors.NewApplyConflict([]metav1.StatusCause{
+		{
+			Type:    metav1.CauseTypeFieldManagerConflict,
+			Message: `conflict with "kubectl" using apps/v1`,
+			Field:   ".spec.replicas",
+		},
+	}, `Apply failed with 1 conflict: conflict with "kubectl" using apps/v1: .spec.replicas`)
+
+	tests := []struct {
+		name          string
+		workload      IWorkload
+		fieldManager  string
+		err           error
+		wantErr       bool
+		wantConflicts []ApplyConflict
+	}{
+		{
+			name:         "Field manager is required",
+			workload:     deployment("nginx"),
+			fieldManager: "",
+			wantErr:      true,
+		},
+		{
+			name:         "Name is required",
+			workload:     deployment(""),
+			fieldManager: "kubescape",
+			wantErr:      true,
+		},
+		{
+			name:         "Conflicts are returned as an ApplyConflictError",
+			workload:     deployment("nginx"),
+			fieldManager: "kubescape",
+			err:          conflict,
+			wantErr:      true,
+			wantConflicts: []ApplyConflict{
+				{Field: ".spec.replicas", Message: `conflict with "kubectl" using apps/v1`},
+			},
+		},
+		{
+			name:         "Other errors are not conflicts",
+			workload:     deployment("nginx"),
+			fieldManager: "kubescape",
+			err:          apierrors.NewForbidden(deploymentsGVR.GroupResource(), "nginx", errors.New("denied")),
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k8sAPI := NewKubernetesApiMock()
+			client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+			client.PrependReactor("patch", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				return true, nil, tt.err
+			})
+			k8sAPI.DynamicClient = client
+
+			_, err := k8sAPI.ApplyResource(context.Background(), &deploymentsGVR, tt.workload, tt.fieldManager, false)
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Error(t, err)
+
+			var conflictErr *ApplyConflictError
+			if tt.wantConflicts == nil {
+				assert.False(t, errors.As(err, &conflictErr))
+				return
+			}
+			assert.True(t, errors.As(err, &conflictErr))
+			assert.Equal(t, tt.wantConflicts, conflictErr.Conflicts)
+			assert.True(t, apierrors.IsConflict(err))
+			assert.Contains(t, err.Error(), ".spec.replicas")
+		})
+	}
+}
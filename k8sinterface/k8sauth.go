@@ -0,0 +1,55 @@
+package k8sinterface
+
+import (
+	"io"
+	"net/http"
+
+	restclient "k8s.io/client-go/rest"
+)
+
+// newHTTPClient returns the HTTP client shared by the clients of a KubernetesApi, authenticating as set by a given rest config
+//
+// The credentials of exec plugins (e.g. aws eks get-token, gke-gcloud-auth-plugin, kubelogin) and auth providers are refreshed by client-go
+// once they expire, or once the API server rejects them. In the latter case the rejected request is retried with the refreshed credentials
+// instead of failing, so that long sessions outlive the credentials they started with
+func newHTTPClient(restConfig *restclient.Config) (*http.Client, error) {
+	httpClient, err := restclient.HTTPClientFor(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	if restConfig.ExecProvider != nil || restConfig.AuthProvider != nil {
+		httpClient.Transport = &authRefreshRoundTripper{next: httpClient.Transport}
+	}
+	return httpClient, nil
+}
+
+// authRefreshRoundTripper retries once the requests of a wrapped round tripper rejected with 401 Unauthorized, on which client-go refreshes
+// the credentials of exec plugins and auth providers. Requests rejected as unauthenticated were not processed, so any of them can be retried
+type authRefreshRoundTripper struct {
+	next http.RoundTripper
+}
+
+func (rt *authRefreshRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	// cloned beforehand, the exec plugins set the credentials on the request in place and would not replace stale ones
+	retry := req.Clone(req.Context())
+	resp, err := rt.next.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	// the body of a request that cannot be replayed cannot be retried
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return resp, nil
+	}
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+		retry.Body = body
+	}
+	// drain the body so that the connection can be reused
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return rt.next.RoundTrip(retry)
+}
@@ -0,0 +1,191 @@
+package k8sinterface
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	restclient "k8s.io/client-go/rest"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// unauthorizedRoundTripper rejects the first given number of requests with 401 Unauthorized, recording the bodies of all the requests
+type unauthorizedRoundTripper struct {
+	unauthorized int
+	bodies       []string
+}
+
+func (rt *unauthorizedRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	body := ""
+	if req.Body != nil {
+		content, _ := io.ReadAll(req.Body)
+		body = string(content)
+	}
+	rt.bodies = append(rt.bodies, body)
+	status := http.StatusOK
+	if len(rt.bodies) <= rt.unauthorized {
+		status = http.StatusUnauthorized
+	}
+	return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader("")), Request: req}, nil
+}
+
+func TestAuthRefreshRoundTripper(t *testing.T) {
+	tests := []struct {
+		name         string
+		unauthorized int
+		request      func() *http.Request
+		wantStatus   int
+		wantBodies   []string
+	}{
+		{
+			name:       "Authorized",
+			request:    func() *http.Request { req, _ := http.NewRequest(http.MethodGet, "http://127.0.0.1", nil); return req },
+			wantStatus: http.StatusOK,
+			wantBodies: []string{""},
+		},
+		{
+			name:         "Retried once with refreshed credentials",
+			unauthorized: 1,
+			request:      func() *http.Request { req, _ := http.NewRequest(http.MethodGet, "http://127.0.0.1", nil); return req },
+			wantStatus:   http.StatusOK,
+			wantBodies:   []string{"", ""},
+		},
+		{
+			name:         "Not retried twice",
+			unauthorized: 2,
+			request:      func() *http.Request { req, _ := http.NewRequest(http.MethodGet, "http://127.0.0.1", nil); return req },
+			wantStatus:   http.StatusUnauthorized,
+			wantBodies:   []string{"", ""},
+		},
+		{
+			name:         "Body replayed",
+			unauthorized: 1,
+			request: func() *http.Request {
+				req, _ := http.NewRequest(http.MethodPost, "http://127.0.0.1", strings.NewReader(`{"kind":"Pod"}`))
+				return req
+			},
+			wantStatus: http.StatusOK,
+			wantBodies: []string{`{"kind":"Pod"}`, `{"kind":"Pod"}`},
+		},
+		{
+			name:         "Body which cannot be replayed",
+			unauthorized: 1,
+			request: func() *http.Request {
+				req, _ := http.NewRequest(http.MethodPost, "http://127.0.0.1", io.NopCloser(bytes.NewBufferString(`{"kind":"Pod"}`)))
+				return req
+			},
+			wantStatus: http.StatusUnauthorized,
+			wantBodies: []string{`{"kind":"Pod"}`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &unauthorizedRoundTripper{unauthorized: tt.unauthorized}
+			resp, err := (&authRefreshRoundTripper{next: next}).RoundTrip(tt.request())
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			assert.Equal(t, tt.wantBodies, next.bodies)
+		})
+	}
+}
+
+func TestExecCredentialRefresh(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the exec plugin is a shell script")
+	}
+	defer tearDown()
+
+	// an exec plugin issuing a new token on every call
+	dir := t.TempDir()
+	plugin := filepath.Join(dir, "get-token")
+	script := fmt.Sprintf(`#!/bin/sh
+n=$(cat %[1]s 2>/dev/null || echo 0)
+n=$((n+1))
+echo $n > %[1]s
+echo '{"apiVersion":"client.authentication.k8s.io/v1","kind":"ExecCredential","status":{"token":"token-'$n'"}}'
+`, filepath.Join(dir, "counter"))
+	assert.NoError(t, os.WriteFile(plugin, []byte(script), 0o755))
+
+	// an API server accepting the second token only, as if the first one expired
+	var lock sync.Mutex
+	tokens := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		tokens = append(tokens, r.Header.Get("Authorization"))
+		lock.Unlock()
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"kind":"PodList","apiVersion":"v1","items":[]}`))
+	}))
+	defer server.Close()
+
+	restConfig := &restclient.Config{
+		Host: server.URL,
+		ExecProvider: &clientcmdapi.ExecConfig{
+			Command:         plugin,
+			APIVersion:      "client.authentication.k8s.io/v1",
+			InteractiveMode: clientcmdapi.NeverExecInteractiveMode,
+		},
+	}
+	k8sAPI, err := NewKubernetesApiForConfig(restConfig, ClientConfig{})
+	assert.NoError(t, err)
+	_, err = k8sAPI.KubernetesClient.CoreV1().Pods("").List(context.Background(), metav1.ListOptions{})
+	assert.NoError(t, err)
+
+	lock.Lock()
+	defer lock.Unlock()
+	assert.Equal(t, []string{"Bearer token-1", "Bearer token-2"}, tokens)
+}
+
+func TestWrapTransport(t *testing.T) {
+	defer tearDown()
+
+	var lock sync.Mutex
+	auth := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		auth = r.Header.Get("Authorization")
+		lock.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"kind":"PodList","apiVersion":"v1","items":[]}`))
+	}))
+	defer server.Close()
+
+	clientConfig := ClientConfig{
+		WrapTransport: func(rt http.RoundTripper) http.RoundTripper {
+			return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				req = req.Clone(req.Context())
+				req.Header.Set("Authorization", "Signed request")
+				return rt.RoundTrip(req)
+			})
+		},
+	}
+	k8sAPI, err := NewKubernetesApiForConfig(&restclient.Config{Host: server.URL}, clientConfig)
+	assert.NoError(t, err)
+	_, err = k8sAPI.KubernetesClient.CoreV1().Pods("").List(context.Background(), metav1.ListOptions{})
+	assert.NoError(t, err)
+
+	lock.Lock()
+	defer lock.Unlock()
+	assert.Equal(t, "Signed request", auth)
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
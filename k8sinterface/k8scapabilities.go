@@ -0,0 +1,139 @@
+package k8sinterface
+
+import (
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+)
+
+// Feature is a capability of the API server HasFeature detects
+type Feature string
+
+const (
+	// FeatureServerSideApply is server-side apply, generally available since Kubernetes 1.22
+	FeatureServerSideApply Feature = "ServerSideApply"
+	// FeatureWatchBookmarks is watch bookmark events, generally available since Kubernetes 1.17
+	FeatureWatchBookmarks Feature = "WatchBookmarks"
+	// FeatureEphemeralContainers is the ephemeralcontainers subresource of Pods, used to debug running Pods
+	FeatureEphemeralContainers Feature = "EphemeralContainers"
+	// FeatureEviction is the eviction subresource of Pods, which evicts Pods respecting their disruption budgets
+	FeatureEviction Feature = "Eviction"
+)
+
+// features detects the features, by the minimal server version they are available in or by the subresource they are served with
+var features = map[Feature]func(k8sAPI *KubernetesApi) (bool, error){
+	FeatureServerSideApply:     minimalServerVersion("1.22.0"),
+	FeatureWatchBookmarks:      minimalServerVersion("1.17.0"),
+	FeatureEphemeralContainers: podSubresource("ephemeralcontainers"),
+	FeatureEviction:            podSubresource("eviction"),
+}
+
+// serverCapabilities caches the server version and the resources of the group versions detected so far
+type serverCapabilities struct {
+	version       *utilversion.Version
+	resourceLists map[string]*metav1.APIResourceList
+}
+
+// GetServerVersion returns the version of the API server, e.g. 1.29.0 for v1.29.0-eks-5e0fdde, fetched once per KubernetesApi
+func (k8sAPI *KubernetesApi) GetServerVersion() (*utilversion.Version, error) {
+	k8sAPI.capabilitiesLock.Lock()
+	defer k8sAPI.capabilitiesLock.Unlock()
+	capabilities := k8sAPI.serverCapabilities()
+	k8sAPI.metrics.cacheLookedUp("server_version", capabilities.version != nil)
+	if capabilities.version == nil {
+		info, err := k8sAPI.DiscoveryClient.ServerVersion()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get server version, reason: %w", err)
+		}
+		serverVersion, err := utilversion.ParseSemantic(info.GitVersion)
+		if err != nil {
+			// some distributions do not follow semantic versioning, e.g. with a "+" in the build metadata
+			if serverVersion, err = utilversion.ParseGeneric(info.GitVersion); err != nil {
+				return nil, fmt.Errorf("failed to parse server version '%s', reason: %s", info.GitVersion, err.Error())
+			}
+		}
+		capabilities.version = serverVersion
+	}
+	return capabilities.version, nil
+}
+
+// SupportsAPI returns true if the API server serves a given kind in a given group version, e.g. batch/v1 CronJob.
+// The resources of every group version are discovered once per KubernetesApi
+func (k8sAPI *KubernetesApi) SupportsAPI(gvk schema.GroupVersionKind) (bool, error) {
+	resourceList, err := k8sAPI.serverResources(gvk.GroupVersion())
+	if err != nil {
+		return false, err
+	}
+	for _, apiResource := range resourceList.APIResources {
+		if apiResource.Kind == gvk.Kind && !strings.Contains(apiResource.Name, "/") {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// HasFeature returns true if the API server has a given feature, detected once per KubernetesApi
+func (k8sAPI *KubernetesApi) HasFeature(feature Feature) (bool, error) {
+	detect, ok := features[feature]
+	if !ok {
+		return false, fmt.Errorf("unknown feature '%s'", feature)
+	}
+	return detect(k8sAPI)
+}
+
+func minimalServerVersion(minimal string) func(k8sAPI *KubernetesApi) (bool, error) {
+	minimalVersion := utilversion.MustParseGeneric(minimal)
+	return func(k8sAPI *KubernetesApi) (bool, error) {
+		serverVersion, err := k8sAPI.GetServerVersion()
+		if err != nil {
+			return false, err
+		}
+		return serverVersion.AtLeast(minimalVersion), nil
+	}
+}
+
+func podSubresource(subresource string) func(k8sAPI *KubernetesApi) (bool, error) {
+	return func(k8sAPI *KubernetesApi) (bool, error) {
+		return k8sAPI.hasSubresource(schema.GroupVersionResource{Version: "v1", Resource: "pods"}, subresource)
+	}
+}
+
+// serverResources returns the resources of a given group version, discovered once per KubernetesApi, none if the group version is not served
+func (k8sAPI *KubernetesApi) serverResources(groupVersion schema.GroupVersion) (*metav1.APIResourceList, error) {
+	k8sAPI.capabilitiesLock.Lock()
+	defer k8sAPI.capabilitiesLock.Unlock()
+	capabilities := k8sAPI.serverCapabilities()
+	resourceList, ok := capabilities.resourceLists[groupVersion.String()]
+	k8sAPI.metrics.cacheLookedUp("server_resources", ok)
+	if ok {
+		return resourceList, nil
+	}
+	resourceList, err := k8sAPI.DiscoveryClient.ServerResourcesForGroupVersion(groupVersion.String())
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to discover API resources, groupVersion: '%s', reason: %w", groupVersion.String(), err)
+		}
+		resourceList = &metav1.APIResourceList{GroupVersion: groupVersion.String()}
+	}
+	capabilities.resourceLists[groupVersion.String()] = resourceList
+	return resourceList, nil
+}
+
+// serverCapabilities returns the capabilities detected so far, the caller holds the capabilities lock
+func (k8sAPI *KubernetesApi) serverCapabilities() *serverCapabilities {
+	if k8sAPI.capabilities == nil {
+		k8sAPI.capabilities = &serverCapabilities{resourceLists: map[string]*metav1.APIResourceList{}}
+	}
+	return k8sAPI.capabilities
+}
+
+// resetServerCapabilities makes the capabilities be detected again on next use
+func (k8sAPI *KubernetesApi) resetServerCapabilities() {
+	k8sAPI.capabilitiesLock.Lock()
+	defer k8sAPI.capabilitiesLock.Unlock()
+	k8sAPI.capabilities = nil
+}
@@ -0,0 +1,165 @@
+package k8sinterface
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+	discoveryfake "k8s.io/client-go/discovery/fake"
+	kubetesting "k8s.io/client-go/testing"
+)
+
+func newCapabilitiesDiscovery(gitVersion string) *discoveryfake.FakeDiscovery {
+	return &discoveryfake.FakeDiscovery{
+		Fake: &kubetesting.Fake{Resources: []*metav1.APIResourceList{
+			{
+				GroupVersion: "v1",
+				APIResources: []metav1.APIResource{
+					{Name: "pods", Kind: "Pod", Namespaced: true},
+					{Name: "pods/ephemeralcontainers", Kind: "Pod", Namespaced: true},
+				},
+			},
+			{
+				GroupVersion: "batch/v1",
+				APIResources: []metav1.APIResource{{Name: "jobs", Kind: "Job", Namespaced: true}, {Name: "jobs/status", Kind: "Job", Namespaced: true}},
+			},
+		}},
+		FakedServerVersion: &version.Info{GitVersion: gitVersion},
+	}
+}
+
+func TestGetServerVersion(t *testing.T) {
+	tests := []struct {
+		name       string
+		gitVersion string
+		want       string
+		wantErr    bool
+	}{
+		{
+			name:       "Release",
+			gitVersion: "v1.29.0",
+			want:       "1.29.0",
+		},
+		{
+			name:       "Managed cluster",
+			gitVersion: "v1.27.8-eks-8cb36c9",
+			want:       "1.27.8-eks-8cb36c9",
+		},
+		{
+			name:       "Not semantic versioning",
+			gitVersion: "v1.26.4+k3s1+build$1",
+			want:       "1.26.4",
+		},
+		{
+			name:       "Invalid",
+			gitVersion: "unknown",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k8sAPI := NewKubernetesApiMock()
+			fakeDiscovery := newCapabilitiesDiscovery(tt.gitVersion)
+			k8sAPI.DiscoveryClient = fakeDiscovery
+
+			serverVersion, err := k8sAPI.GetServerVersion()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, serverVersion.String())
+
+			// fetched once
+			_, err = k8sAPI.GetServerVersion()
+			assert.NoError(t, err)
+			assert.Len(t, fakeDiscovery.Actions(), 1)
+		})
+	}
+}
+
+func TestSupportsAPI(t *testing.T) {
+	k8sAPI := NewKubernetesApiMock()
+	fakeDiscovery := newCapabilitiesDiscovery("v1.29.0")
+	k8sAPI.DiscoveryClient = fakeDiscovery
+
+	tests := []struct {
+		gvk  schema.GroupVersionKind
+		want bool
+	}{
+		{gvk: schema.GroupVersionKind{Group: "batch", Version: "v1", Kind: "Job"}, want: true},
+		{gvk: schema.GroupVersionKind{Group: "batch", Version: "v1", Kind: "CronJob"}, want: false},
+		{gvk: schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.gvk.String(), func(t *testing.T) {
+			got, err := k8sAPI.SupportsAPI(tt.gvk)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+	// the resources of every group version are discovered once
+	assert.Len(t, fakeDiscovery.Actions(), 2)
+
+	k8sAPI.resetServerCapabilities()
+	_, err := k8sAPI.SupportsAPI(schema.GroupVersionKind{Group: "batch", Version: "v1", Kind: "Job"})
+	assert.NoError(t, err)
+	assert.Len(t, fakeDiscovery.Actions(), 3)
+}
+
+func TestHasFeature(t *testing.T) {
+	tests := []struct {
+		name       string
+		gitVersion string
+		feature    Feature
+		want       bool
+		wantErr    bool
+	}{
+		{
+			name:       "Server-side apply",
+			gitVersion: "v1.22.0",
+			feature:    FeatureServerSideApply,
+			want:       true,
+		},
+		{
+			name:       "Server-side apply on an old server",
+			gitVersion: "v1.21.14",
+			feature:    FeatureServerSideApply,
+		},
+		{
+			name:       "Watch bookmarks",
+			gitVersion: "v1.29.0",
+			feature:    FeatureWatchBookmarks,
+			want:       true,
+		},
+		{
+			name:       "Ephemeral containers",
+			gitVersion: "v1.29.0",
+			feature:    FeatureEphemeralContainers,
+			want:       true,
+		},
+		{
+			name:       "Eviction",
+			gitVersion: "v1.29.0",
+			feature:    FeatureEviction,
+		},
+		{
+			name:       "Unknown feature",
+			gitVersion: "v1.29.0",
+			feature:    "TimeTravel",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k8sAPI := NewKubernetesApiMock()
+			k8sAPI.DiscoveryClient = newCapabilitiesDiscovery(tt.gitVersion)
+
+			got, err := k8sAPI.HasFeature(tt.feature)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package k8sinterface
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/apimachinery/pkg/runtime"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/transport"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+const (
+	KS_K8S_CLIENT_QPS_ENV_VAR   = "KS_K8S_CLIENT_QPS"
+	KS_K8S_CLIENT_BURST_ENV_VAR = "KS_K8S_CLIENT_BURST"
+)
+
+// ClientConfig configures the client-side throttling of the clients of a KubernetesApi
+//
+// Zero fields are unset: they fall back to the package-level config set with SetClientConfig,
+// then to the KS_K8S_CLIENT_QPS and KS_K8S_CLIENT_BURST environment variables, then to the client-go defaults
+type ClientConfig struct {
+	// QPS is the maximum sustained number of queries per second to the API server
+	QPS float32
+	// Burst is the maximum number of queries in a burst above QPS
+	Burst int
+	// RateLimiter throttles the queries to the API server, in which case QPS and Burst are ignored
+	RateLimiter flowcontrol.RateLimiter
+	// Retry enables retries of the idempotent requests that fail transiently, disabled if nil
+	Retry *RetryConfig
+	// DiscoveryCache enables caching the discovered API resources, disabled if nil
+	DiscoveryCache *DiscoveryCacheConfig
+	// Protobuf makes the kubernetes client talk protobuf, cheaper to serialize and smaller than JSON, with the built-in API groups.
+	// The dynamic client keeps talking JSON, the only encoding custom resources are served in. Unset if nil, so that false overrides a fallback enabling it
+	Protobuf *bool
+	// Impersonate makes the clients act as another user, not impersonating if nil
+	Impersonate *restclient.ImpersonationConfig
+	// WrapTransport wraps the transport of the clients, e.g. to authenticate requests in a bespoke way. It runs after the client-go authentication
+	WrapTransport transport.WrapperFunc
+	// Proxy returns the proxy of a request, e.g. http.ProxyURL of an http, https or socks5 URL to reach the API server through a bastion.
+	// If nil, the proxy-url of the kubeconfig cluster is used, or else the HTTPS_PROXY and NO_PROXY environment variables
+	Proxy func(*http.Request) (*url.URL, error)
+	// Dial dials the connections to the API server, e.g. through an SSH tunnel
+	Dial func(ctx context.Context, network, address string) (net.Conn, error)
+	// TracerProvider provides the tracer of the spans of the requests to the API server. If nil, the global one set with otel.SetTracerProvider is used
+	TracerProvider trace.TracerProvider
+	// Logger receives the diagnostics of the clients, e.g. the retries of requests. If nil, the package-level one set with SetLogger is used
+	Logger Logger
+	// MetricsRegisterer registers the Prometheus collectors of the requests to the API server, e.g. prometheus.DefaultRegisterer, no metrics are collected if nil
+	MetricsRegisterer prometheus.Registerer
+}
+
+// WithImpersonation returns a copy of the client config making the clients act as a given user, member of given groups and with given extra attributes,
+// e.g. to evaluate what another identity can see or do. The identity the clients authenticate with must be allowed to impersonate it
+func (config ClientConfig) WithImpersonation(user string, groups []string, extra map[string][]string) ClientConfig {
+	config.Impersonate = &restclient.ImpersonationConfig{
+		UserName: user,
+		Groups:   groups,
+		Extra:    extra,
+	}
+	return config
+}
+
+var clientConfig = ClientConfig{}
+
+// SetClientConfig sets the package-level client config, used by the KubernetesApi instances created afterwards
+func SetClientConfig(config ClientConfig) {
+	clientConfig = config
+}
+
+// GetClientConfig returns the package-level client config
+func GetClientConfig() ClientConfig {
+	return clientConfig
+}
+
+// resolveClientConfig fills the unset fields of a given client config from the package-level config, then from the environment
+func resolveClientConfig(config ClientConfig) ClientConfig {
+	return config.withFallback(clientConfig).withFallback(clientConfigFromEnv())
+}
+
+// withFallback returns the client config with its unset fields filled from a given client config
+func (config ClientConfig) withFallback(fallback ClientConfig) ClientConfig {
+	if config.QPS == 0 {
+		config.QPS = fallback.QPS
+	}
+	if config.Burst == 0 {
+		config.Burst = fallback.Burst
+	}
+	if config.RateLimiter == nil {
+		config.RateLimiter = fallback.RateLimiter
+	}
+	if config.Retry == nil {
+		config.Retry = fallback.Retry
+	}
+	if config.DiscoveryCache == nil {
+		config.DiscoveryCache = fallback.DiscoveryCache
+	}
+	if config.Protobuf == nil {
+		config.Protobuf = fallback.Protobuf
+	}
+	if config.Impersonate == nil {
+		config.Impersonate = fallback.Impersonate
+	}
+	if config.WrapTransport == nil {
+		config.WrapTransport = fallback.WrapTransport
+	}
+	if config.Proxy == nil {
+		config.Proxy = fallback.Proxy
+	}
+	if config.Dial == nil {
+		config.Dial = fallback.Dial
+	}
+	if config.TracerProvider == nil {
+		config.TracerProvider = fallback.TracerProvider
+	}
+	if config.Logger == nil {
+		config.Logger = fallback.Logger
+	}
+	if config.MetricsRegisterer == nil {
+		config.MetricsRegisterer = fallback.MetricsRegisterer
+	}
+	return config
+}
+
+// clientConfigFromEnv returns the client config set in the environment, ignoring invalid values
+func clientConfigFromEnv() ClientConfig {
+	config := ClientConfig{}
+	if val, present := os.LookupEnv(KS_K8S_CLIENT_QPS_ENV_VAR); present {
+		if qps, err := strconv.ParseFloat(val, 32); err != nil || qps <= 0 {
+			GetLogger().Warning("ignoring invalid client QPS", KS_K8S_CLIENT_QPS_ENV_VAR, val)
+		} else {
+			config.QPS = float32(qps)
+		}
+	}
+	if val, present := os.LookupEnv(KS_K8S_CLIENT_BURST_ENV_VAR); present {
+		if burst, err := strconv.Atoi(val); err != nil || burst <= 0 {
+			GetLogger().Warning("ignoring invalid client burst", KS_K8S_CLIENT_BURST_ENV_VAR, val)
+		} else {
+			config.Burst = burst
+		}
+	}
+	return config
+}
+
+// applyTo returns a copy of a given rest config throttled, and retrying, impersonating, proxied and with a wrapped transport if enabled, by the client config,
+// tracing the requests. Unset fields keep the values of the rest config
+func (config ClientConfig) applyTo(restConfig *restclient.Config) *restclient.Config {
+	if restConfig == nil {
+		return nil
+	}
+	restConfig = restclient.CopyConfig(restConfig)
+	if config.QPS != 0 {
+		restConfig.QPS = config.QPS
+	}
+	if config.Burst != 0 {
+		restConfig.Burst = config.Burst
+	}
+	if config.RateLimiter != nil {
+		restConfig.RateLimiter = config.RateLimiter
+	}
+	// the kubernetes client rejects a QPS without a burst, which then falls back to the client-go default
+	if restConfig.RateLimiter == nil && restConfig.QPS > 0 && restConfig.Burst <= 0 {
+		restConfig.Burst = restclient.DefaultBurst
+	}
+	if config.Impersonate != nil {
+		restConfig.Impersonate = *config.Impersonate
+	}
+	if config.Retry != nil {
+		retryConfig, retryLogger := *config.Retry, config.Logger
+		restConfig.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+			return newRetryRoundTripper(retryConfig, retryLogger, rt)
+		})
+	}
+	if config.WrapTransport != nil {
+		restConfig.Wrap(config.WrapTransport)
+	}
+	if config.Proxy != nil {
+		restConfig.Proxy = config.Proxy
+	}
+	if config.Dial != nil {
+		restConfig.Dial = config.Dial
+	}
+	// the requests are traced as a whole, retries included
+	tracerProvider := config.TracerProvider
+	restConfig.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return newTracingRoundTripper(tracerProvider, rt)
+	})
+	return restConfig
+}
+
+// talksProtobuf returns whether the kubernetes client talks protobuf
+func (config ClientConfig) talksProtobuf() bool {
+	return config.Protobuf != nil && *config.Protobuf
+}
+
+// withProtobuf returns a copy of a given rest config talking protobuf, falling back to JSON for the types which are not served in protobuf
+func withProtobuf(restConfig *restclient.Config) *restclient.Config {
+	restConfig = restclient.CopyConfig(restConfig)
+	restConfig.ContentType = runtime.ContentTypeProtobuf
+	restConfig.AcceptContentTypes = runtime.ContentTypeProtobuf + "," + runtime.ContentTypeJSON
+	return restConfig
+}
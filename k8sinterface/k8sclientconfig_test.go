@@ -0,0 +1,291 @@
+package k8sinterface
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+func TestResolveClientConfig(t *testing.T) {
+	defer tearDown()
+
+	rateLimiter := flowcontrol.NewFakeAlwaysRateLimiter()
+	protobuf, json := true, false
+	tests := []struct {
+		name     string
+		config   ClientConfig
+		global   ClientConfig
+		envQPS   string
+		envBurst string
+		want     ClientConfig
+	}{
+		{
+			name: "Nothing set",
+			want: ClientConfig{},
+		},
+		{
+			name:     "Environment variables",
+			envQPS:   "50",
+			envBurst: "100",
+			want:     ClientConfig{QPS: 50, Burst: 100},
+		},
+		{
+			name:     "Invalid environment variables are ignored",
+			envQPS:   "fast",
+			envBurst: "-1",
+			want:     ClientConfig{},
+		},
+		{
+			name:     "Package-level config overrides the environment",
+			global:   ClientConfig{QPS: 20},
+			envQPS:   "50",
+			envBurst: "100",
+			want:     ClientConfig{QPS: 20, Burst: 100},
+		},
+		{
+			name:   "Instance config overrides the package-level config",
+			config: ClientConfig{Burst: 40, RateLimiter: rateLimiter},
+			global: ClientConfig{QPS: 20, Burst: 30},
+			want:   ClientConfig{QPS: 20, Burst: 40, RateLimiter: rateLimiter},
+		},
+		{
+			name:   "Instance config enables protobuf",
+			config: ClientConfig{Protobuf: &protobuf},
+			want:   ClientConfig{Protobuf: &protobuf},
+		},
+		{
+			name:   "Instance config disables protobuf",
+			config: ClientConfig{Protobuf: &json},
+			global: ClientConfig{Protobuf: &protobuf},
+			want:   ClientConfig{Protobuf: &json},
+		},
+		{
+			name:   "Package-level config enables protobuf",
+			global: ClientConfig{Protobuf: &protobuf},
+			want:   ClientConfig{Protobuf: &protobuf},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.envQPS != "" {
+				t.Setenv(KS_K8S_CLIENT_QPS_ENV_VAR, tt.envQPS)
+			}
+			if tt.envBurst != "" {
+				t.Setenv(KS_K8S_CLIENT_BURST_ENV_VAR, tt.envBurst)
+			}
+			SetClientConfig(tt.global)
+			assert.Equal(t, tt.want, resolveClientConfig(tt.config))
+		})
+	}
+}
+
+func TestClientConfigApplyTo(t *testing.T) {
+	restConfig := &restclient.Config{Host: "https://127.0.0.1:6443", QPS: 5, Burst: 10}
+
+	throttled := ClientConfig{QPS: 50}.applyTo(restConfig)
+	assert.Equal(t, float32(50), throttled.QPS)
+	assert.Equal(t, 10, throttled.Burst)
+	assert.Equal(t, restConfig.Host, throttled.Host)
+	// the given rest config is left untouched
+	assert.Equal(t, float32(5), restConfig.QPS)
+	// the requests are only traced
+	if assert.NotNil(t, throttled.WrapTransport) {
+		traced, ok := throttled.WrapTransport(http.DefaultTransport).(*tracingRoundTripper)
+		if assert.True(t, ok) {
+			assert.Equal(t, http.DefaultTransport, traced.next)
+		}
+	}
+
+	retrying := ClientConfig{Retry: &RetryConfig{}}.applyTo(restConfig)
+	assert.NotNil(t, retrying.WrapTransport)
+	assert.Nil(t, restConfig.WrapTransport)
+
+	// a QPS without a burst gets the client-go default burst, the kubernetes client rejects it otherwise
+	burstless := ClientConfig{QPS: 50}.applyTo(&restclient.Config{Host: "https://127.0.0.1:6443"})
+	assert.Equal(t, restclient.DefaultBurst, burstless.Burst)
+	assert.Equal(t, 0, ClientConfig{QPS: 50}.applyTo(&restclient.Config{RateLimiter: flowcontrol.NewFakeAlwaysRateLimiter()}).Burst)
+
+	assert.Nil(t, ClientConfig{QPS: 50}.applyTo(nil))
+}
+
+func TestProxyAndDial(t *testing.T) {
+	defer tearDown()
+
+	// an API server which can only be reached through a proxy or a custom dialer
+	var lock sync.Mutex
+	hosts := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		hosts = append(hosts, r.Host)
+		lock.Unlock()
+		w.Header().Set("Content-Type", runtime.ContentTypeJSON)
+		w.Write([]byte(`{"kind":"PodList","apiVersion":"v1","items":[]}`))
+	}))
+	defer server.Close()
+	serverURL, _ := url.Parse(server.URL)
+
+	dialed := 0
+	for name, clientConfig := range map[string]ClientConfig{
+		"proxy": {Proxy: http.ProxyURL(serverURL)},
+		"dial": {Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			dialed++
+			return (&net.Dialer{}).DialContext(ctx, network, serverURL.Host)
+		}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			k8sAPI, err := NewKubernetesApiForConfig(&restclient.Config{Host: "http://apiserver.invalid"}, clientConfig)
+			assert.NoError(t, err)
+			_, err = k8sAPI.KubernetesClient.CoreV1().Pods("").List(context.Background(), metav1.ListOptions{})
+			assert.NoError(t, err)
+		})
+	}
+
+	assert.Equal(t, 1, dialed)
+	lock.Lock()
+	defer lock.Unlock()
+	assert.Equal(t, []string{"apiserver.invalid", "apiserver.invalid"}, hosts)
+}
+
+func TestWithImpersonation(t *testing.T) {
+	defer tearDown()
+
+	var lock sync.Mutex
+	headers := http.Header{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		headers = r.Header.Clone()
+		lock.Unlock()
+		w.Header().Set("Content-Type", runtime.ContentTypeJSON)
+		w.Write([]byte(`{"kind":"PodList","apiVersion":"v1","items":[]}`))
+	}))
+	defer server.Close()
+
+	clientConfig := ClientConfig{QPS: 50}.WithImpersonation("alice", []string{"developers", "system:authenticated"}, map[string][]string{"scopes": {"view"}})
+	assert.Equal(t, float32(50), clientConfig.QPS)
+	assert.Equal(t, "alice", clientConfig.Impersonate.UserName)
+
+	// the package-level config impersonates unless the instance config does
+	SetClientConfig(ClientConfig{}.WithImpersonation("bob", nil, nil))
+	assert.Equal(t, "bob", resolveClientConfig(ClientConfig{}).Impersonate.UserName)
+	assert.Equal(t, "alice", resolveClientConfig(clientConfig).Impersonate.UserName)
+
+	k8sAPI, err := NewKubernetesApiForConfig(&restclient.Config{Host: server.URL}, clientConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", k8sAPI.K8SConfig.Impersonate.UserName)
+	_, err = k8sAPI.KubernetesClient.CoreV1().Pods("").List(context.Background(), metav1.ListOptions{})
+	assert.NoError(t, err)
+
+	lock.Lock()
+	defer lock.Unlock()
+	assert.Equal(t, "alice", headers.Get("Impersonate-User"))
+	assert.Equal(t, []string{"developers", "system:authenticated"}, headers.Values("Impersonate-Group"))
+	assert.Equal(t, "view", headers.Get("Impersonate-Extra-Scopes"))
+}
+
+func TestWithProtobuf(t *testing.T) {
+	restConfig := &restclient.Config{Host: "https://127.0.0.1:6443"}
+	protobufConfig := withProtobuf(restConfig)
+	assert.Equal(t, runtime.ContentTypeProtobuf, protobufConfig.ContentType)
+	assert.Equal(t, "application/vnd.kubernetes.protobuf,application/json", protobufConfig.AcceptContentTypes)
+	assert.Empty(t, restConfig.ContentType)
+	assert.Empty(t, restConfig.AcceptContentTypes)
+}
+
+// newPodListServer returns an API server listing a given number of pods in the content type asked for, recording the content types served
+func newPodListServer(pods int) (*httptest.Server, func() []string) {
+	podList := &corev1.PodList{}
+	for i := 0; i < pods; i++ {
+		podList.Items = append(podList.Items, corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("nginx-%d", i),
+				Namespace: "default",
+				Labels:    map[string]string{"app": "nginx", "pod-template-hash": "5d8f7c6b9"},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "nginx", Image: "nginx:1.25", Ports: []corev1.ContainerPort{{ContainerPort: 80}}}},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.0.0.1"},
+		})
+	}
+	encoded := map[string][]byte{}
+	for _, mediaType := range []string{runtime.ContentTypeJSON, runtime.ContentTypeProtobuf} {
+		info, _ := runtime.SerializerInfoForMediaType(scheme.Codecs.SupportedMediaTypes(), mediaType)
+		encoded[mediaType], _ = runtime.Encode(scheme.Codecs.EncoderForVersion(info.Serializer, corev1.SchemeGroupVersion), podList)
+	}
+
+	var lock sync.Mutex
+	served := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType := runtime.ContentTypeJSON
+		if strings.HasPrefix(r.Header.Get("Accept"), runtime.ContentTypeProtobuf) {
+			contentType = runtime.ContentTypeProtobuf
+		}
+		lock.Lock()
+		served = append(served, contentType)
+		lock.Unlock()
+		w.Header().Set("Content-Type", contentType)
+		w.Write(encoded[contentType])
+	}))
+	return server, func() []string {
+		lock.Lock()
+		defer lock.Unlock()
+		return append([]string{}, served...)
+	}
+}
+
+func TestProtobufNegotiation(t *testing.T) {
+	defer tearDown()
+
+	server, served := newPodListServer(3)
+	defer server.Close()
+
+	for _, protobuf := range []bool{false, true} {
+		k8sAPI, err := NewKubernetesApiForConfig(&restclient.Config{Host: server.URL}, ClientConfig{Protobuf: &protobuf})
+		assert.NoError(t, err)
+		pods, err := k8sAPI.KubernetesClient.CoreV1().Pods("").List(context.Background(), metav1.ListOptions{})
+		assert.NoError(t, err)
+		assert.Len(t, pods.Items, 3)
+		assert.Equal(t, "nginx-2", pods.Items[2].Name)
+	}
+	assert.Equal(t, []string{runtime.ContentTypeJSON, runtime.ContentTypeProtobuf}, served())
+}
+
+func BenchmarkListAllPods(b *testing.B) {
+	server, _ := newPodListServer(5000)
+	defer server.Close()
+
+	for _, bm := range []struct {
+		name     string
+		protobuf bool
+	}{
+		{name: "json"},
+		{name: "protobuf", protobuf: true},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			k8sAPI, err := NewKubernetesApiForConfig(&restclient.Config{Host: server.URL, QPS: -1}, ClientConfig{Protobuf: &bm.protobuf})
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := k8sAPI.KubernetesClient.CoreV1().Pods("").List(context.Background(), metav1.ListOptions{}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package k8sinterface
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// ClusterWorkload is a workload returned by ClusterManager.FanOut, tagged with the cluster it was found in
+type ClusterWorkload struct {
+	Cluster  string
+	Workload IWorkload
+}
+
+// ClusterManager hands out a KubernetesApi per cluster, connecting to a cluster the first time its KubernetesApi is requested
+//
+// Clusters are named after their kubeconfig context. All the clusters share the resource mapping of the package,
+// so resources missing from it are treated as cluster scoped in every cluster
+type ClusterManager struct {
+	clientConfig ClientConfig
+	// newKubernetesApi connects to a cluster, replaced in tests
+	newKubernetesApi func(restConfig *restclient.Config, clientConfig ClientConfig) (*KubernetesApi, error)
+
+	mu       sync.Mutex
+	clusters map[string]*managedCluster
+}
+
+// managedCluster is a cluster of a ClusterManager, connected lazily
+type managedCluster struct {
+	config clientcmd.ClientConfig
+
+	mu sync.Mutex
+	// clientConfig overrides the client config of the manager for the cluster
+	clientConfig ClientConfig
+	k8sAPI       *KubernetesApi
+}
+
+// NewClusterManager returns a ClusterManager for given contexts of a kubeconfig, or for all of them if none are given,
+// whose clients are throttled by a given client config
+func NewClusterManager(kubeconfig *clientcmdapi.Config, contextNames []string, clientConfig ClientConfig) (*ClusterManager, error) {
+	m := newClusterManager(clientConfig)
+	if len(contextNames) == 0 {
+		for contextName := range kubeconfig.Contexts {
+			contextNames = append(contextNames, contextName)
+		}
+	}
+	for _, contextName := range contextNames {
+		if err := m.addCluster(kubeconfig, contextName); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// NewClusterManagerFromDir returns a ClusterManager for all the contexts of the kubeconfig files in a given directory,
+// whose clients are throttled by a given client config. Context names must be unique across files
+func NewClusterManagerFromDir(dir string, clientConfig ClientConfig) (*ClusterManager, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read kubeconfig directory: '%s', reason: %s", dir, err.Error())
+	}
+
+	m := newClusterManager(clientConfig)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		filename := filepath.Join(dir, entry.Name())
+		kubeconfig, err := clientcmd.LoadFromFile(filename)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load kubeconfig: '%s', reason: %s", filename, err.Error())
+		}
+		for contextName := range kubeconfig.Contexts {
+			if err := m.addCluster(kubeconfig, contextName); err != nil {
+				return nil, fmt.Errorf("failed to load kubeconfig: '%s', reason: %s", filename, err.Error())
+			}
+		}
+	}
+	return m, nil
+}
+
+func newClusterManager(clientConfig ClientConfig) *ClusterManager {
+	return &ClusterManager{
+		clientConfig:     clientConfig,
+		newKubernetesApi: NewKubernetesApiForConfig,
+		clusters:         map[string]*managedCluster{},
+	}
+}
+
+func (m *ClusterManager) addCluster(kubeconfig *clientcmdapi.Config, contextName string) error {
+	if _, exist := kubeconfig.Contexts[contextName]; !exist {
+		return fmt.Errorf("context '%s' not found in kubeconfig", contextName)
+	}
+	if _, exist := m.clusters[contextName]; exist {
+		return fmt.Errorf("context '%s' is defined more than once", contextName)
+	}
+	m.clusters[contextName] = &managedCluster{
+		config: clientcmd.NewNonInteractiveClientConfig(*kubeconfig, contextName, &clientcmd.ConfigOverrides{}, nil),
+	}
+	return nil
+}
+
+// Clusters returns the names of the clusters of the manager, sorted
+func (m *ClusterManager) Clusters() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	names := make([]string, 0, len(m.clusters))
+	for name := range m.clusters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Get returns the KubernetesApi of a given cluster, connecting to the cluster on first use
+func (m *ClusterManager) Get(clusterName string) (*KubernetesApi, error) {
+	m.mu.Lock()
+	cluster, exist := m.clusters[clusterName]
+	m.mu.Unlock()
+	if !exist {
+		return nil, fmt.Errorf("cluster '%s' not found", clusterName)
+	}
+
+	cluster.mu.Lock()
+	defer cluster.mu.Unlock()
+	if cluster.k8sAPI != nil {
+		return cluster.k8sAPI, nil
+	}
+	restConfig, err := cluster.config.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubernetes config of cluster '%s', reason: %s", clusterName, err.Error())
+	}
+	k8sAPI, err := m.newKubernetesApi(restConfig, cluster.clientConfig.withFallback(m.clientConfig))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to cluster '%s', reason: %s", clusterName, err.Error())
+	}
+	cluster.k8sAPI = k8sAPI
+	return k8sAPI, nil
+}
+
+// SetClusterClientConfig overrides the client config of a given cluster, e.g. to reach it through its own proxy or tunnel,
+// with the unset fields falling back to the client config of the manager. A connected cluster is connected again on next use
+//
+// Proxies set as proxy-url in the kubeconfig cluster are used without an override
+func (m *ClusterManager) SetClusterClientConfig(clusterName string, clientConfig ClientConfig) error {
+	m.mu.Lock()
+	cluster, exist := m.clusters[clusterName]
+	m.mu.Unlock()
+	if !exist {
+		return fmt.Errorf("cluster '%s' not found", clusterName)
+	}
+
+	cluster.mu.Lock()
+	defer cluster.mu.Unlock()
+	cluster.clientConfig = clientConfig
+	cluster.k8sAPI = nil
+	return nil
+}
+
+// Probe checks that a given cluster is reachable and its API server is ready
+func (m *ClusterManager) Probe(ctx context.Context, clusterName string) error {
+	k8sAPI, err := m.Get(clusterName)
+	if err != nil {
+		return err
+	}
+	if restClient := k8sAPI.DiscoveryClient.RESTClient(); restClient != nil {
+		err = restClient.Get().AbsPath("/readyz").Do(ctx).Error()
+	} else {
+		_, err = k8sAPI.DiscoveryClient.ServerVersion()
+	}
+	if err != nil {
+		return fmt.Errorf("cluster '%s' is not ready, reason: %s", clusterName, err.Error())
+	}
+	return nil
+}
+
+// ProbeAll probes all the clusters concurrently, and returns the error of every cluster that is not ready, by cluster name
+func (m *ClusterManager) ProbeAll(ctx context.Context) map[string]error {
+	clusterNames := m.Clusters()
+	errs := make([]error, len(clusterNames))
+
+	var wg sync.WaitGroup
+	for i := range clusterNames {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = m.Probe(ctx, clusterNames[i])
+		}(i)
+	}
+	wg.Wait()
+
+	failures := map[string]error{}
+	for i := range clusterNames {
+		if errs[i] != nil {
+			failures[clusterNames[i]] = errs[i]
+		}
+	}
+	return failures
+}
+
+// FanOut runs a given query against all the clusters concurrently, and merges the workloads they return, tagged by cluster name
+// and ordered by cluster name
+//
+// Clusters that fail do not stop the others: the workloads of the clusters that succeed are returned along with an error joining the failures
+func (m *ClusterManager) FanOut(ctx context.Context, query func(ctx context.Context, k8sAPI *KubernetesApi) ([]IWorkload, error)) ([]ClusterWorkload, error) {
+	clusterNames := m.Clusters()
+	results := make([][]IWorkload, len(clusterNames))
+	errs := make([]error, len(clusterNames))
+
+	var wg sync.WaitGroup
+	for i := range clusterNames {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			k8sAPI, err := m.Get(clusterNames[i])
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			workloads, err := query(ctx, k8sAPI)
+			if err != nil {
+				errs[i] = fmt.Errorf("cluster '%s': %w", clusterNames[i], err)
+				return
+			}
+			results[i] = workloads
+		}(i)
+	}
+	wg.Wait()
+
+	var workloads []ClusterWorkload
+	for i := range clusterNames {
+		for _, workload := range results[i] {
+			workloads = append(workloads, ClusterWorkload{Cluster: clusterNames[i], Workload: workload})
+		}
+	}
+	return workloads, errors.Join(errs...)
+}
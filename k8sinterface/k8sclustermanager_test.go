@@ -0,0 +1,165 @@
+package k8sinterface
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/kubescape/k8s-interface/workloadinterface"
+	"github.com/stretchr/testify/assert"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func newTestKubeconfig(contextNames ...string) *clientcmdapi.Config {
+	kubeconfig := clientcmdapi.NewConfig()
+	for _, name := range contextNames {
+		kubeconfig.Clusters[name] = &clientcmdapi.Cluster{Server: "https://" + name + ".example.com"}
+		kubeconfig.AuthInfos[name] = &clientcmdapi.AuthInfo{Token: "token"}
+		kubeconfig.Contexts[name] = &clientcmdapi.Context{Cluster: name, AuthInfo: name}
+	}
+	return kubeconfig
+}
+
+// mockClusterManager makes a ClusterManager connect to mock clusters, recording the servers it connects to
+func mockClusterManager(m *ClusterManager) *[]string {
+	var mu sync.Mutex
+	servers := &[]string{}
+	m.newKubernetesApi = func(restConfig *restclient.Config, _ ClientConfig) (*KubernetesApi, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		*servers = append(*servers, restConfig.Host)
+		k8sAPI := NewKubernetesApiMock()
+		k8sAPI.K8SConfig = restConfig
+		return k8sAPI, nil
+	}
+	return servers
+}
+
+func TestNewClusterManager(t *testing.T) {
+	kubeconfig := newTestKubeconfig("dev", "prod")
+
+	m, err := NewClusterManager(kubeconfig, nil, ClientConfig{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"dev", "prod"}, m.Clusters())
+
+	m, err = NewClusterManager(kubeconfig, []string{"prod"}, ClientConfig{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"prod"}, m.Clusters())
+
+	_, err = NewClusterManager(kubeconfig, []string{"staging"}, ClientConfig{})
+	assert.Error(t, err)
+}
+
+func TestNewClusterManagerFromDir(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, clientcmd.WriteToFile(*newTestKubeconfig("dev"), filepath.Join(dir, "dev.yaml")))
+	assert.NoError(t, clientcmd.WriteToFile(*newTestKubeconfig("prod", "staging"), filepath.Join(dir, "prod.yaml")))
+
+	m, err := NewClusterManagerFromDir(dir, ClientConfig{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"dev", "prod", "staging"}, m.Clusters())
+
+	assert.NoError(t, clientcmd.WriteToFile(*newTestKubeconfig("dev"), filepath.Join(dir, "dev-copy.yaml")))
+	_, err = NewClusterManagerFromDir(dir, ClientConfig{})
+	assert.Error(t, err)
+
+	_, err = NewClusterManagerFromDir(filepath.Join(dir, "missing"), ClientConfig{})
+	assert.Error(t, err)
+}
+
+func TestClusterManagerGet(t *testing.T) {
+	m, err := NewClusterManager(newTestKubeconfig("dev", "prod"), nil, ClientConfig{})
+	assert.NoError(t, err)
+	servers := mockClusterManager(m)
+
+	// clusters are connected lazily, once
+	assert.Empty(t, *servers)
+	dev, err := m.Get("dev")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://dev.example.com", dev.K8SConfig.Host)
+	again, err := m.Get("dev")
+	assert.NoError(t, err)
+	assert.Same(t, dev, again)
+	assert.Equal(t, []string{"https://dev.example.com"}, *servers)
+
+	_, err = m.Get("staging")
+	assert.Error(t, err)
+
+	assert.Empty(t, m.ProbeAll(context.Background()))
+}
+
+func TestClusterManagerSetClusterClientConfig(t *testing.T) {
+	m, err := NewClusterManager(newTestKubeconfig("dev", "prod"), nil, ClientConfig{QPS: 50, Burst: 100})
+	assert.NoError(t, err)
+	mockClusterManager(m)
+	clientConfigs := map[string]ClientConfig{}
+	newKubernetesApi := m.newKubernetesApi
+	m.newKubernetesApi = func(restConfig *restclient.Config, clientConfig ClientConfig) (*KubernetesApi, error) {
+		clientConfigs[restConfig.Host] = clientConfig
+		return newKubernetesApi(restConfig, clientConfig)
+	}
+
+	dev, err := m.Get("dev")
+	assert.NoError(t, err)
+
+	// prod is reached through a bastion
+	proxyURL, _ := url.Parse("socks5://bastion.example.com:1080")
+	assert.NoError(t, m.SetClusterClientConfig("prod", ClientConfig{Burst: 20, Proxy: http.ProxyURL(proxyURL)}))
+	assert.NoError(t, m.SetClusterClientConfig("dev", ClientConfig{QPS: 10}))
+	assert.Error(t, m.SetClusterClientConfig("staging", ClientConfig{}))
+
+	_, err = m.Get("prod")
+	assert.NoError(t, err)
+	prod := clientConfigs["https://prod.example.com"]
+	assert.Equal(t, float32(50), prod.QPS)
+	assert.Equal(t, 20, prod.Burst)
+	if assert.NotNil(t, prod.Proxy) {
+		proxy, err := prod.Proxy(&http.Request{URL: &url.URL{Scheme: "https", Host: "prod.example.com"}})
+		assert.NoError(t, err)
+		assert.Equal(t, proxyURL, proxy)
+	}
+
+	// dev is connected again with its override
+	again, err := m.Get("dev")
+	assert.NoError(t, err)
+	assert.NotSame(t, dev, again)
+	assert.Equal(t, float32(10), clientConfigs["https://dev.example.com"].QPS)
+	assert.Equal(t, 100, clientConfigs["https://dev.example.com"].Burst)
+	assert.Nil(t, clientConfigs["https://dev.example.com"].Proxy)
+}
+
+func TestClusterManagerFanOut(t *testing.T) {
+	m, err := NewClusterManager(newTestKubeconfig("dev", "prod", "staging"), nil, ClientConfig{})
+	assert.NoError(t, err)
+	mockClusterManager(m)
+
+	failure := errors.New("forbidden")
+	workloads, err := m.FanOut(context.Background(), func(ctx context.Context, k8sAPI *KubernetesApi) ([]IWorkload, error) {
+		switch k8sAPI.K8SConfig.Host {
+		case "https://staging.example.com":
+			return nil, failure
+		case "https://prod.example.com":
+			return []IWorkload{
+				workloadinterface.NewWorkloadObj(map[string]interface{}{"kind": "Pod", "metadata": map[string]interface{}{"name": "nginx"}}),
+				workloadinterface.NewWorkloadObj(map[string]interface{}{"kind": "Pod", "metadata": map[string]interface{}{"name": "redis"}}),
+			}, nil
+		}
+		return []IWorkload{
+			workloadinterface.NewWorkloadObj(map[string]interface{}{"kind": "Pod", "metadata": map[string]interface{}{"name": "nginx"}}),
+		}, nil
+	})
+	assert.ErrorIs(t, err, failure)
+	assert.ErrorContains(t, err, "staging")
+
+	tagged := make([]string, len(workloads))
+	for i := range workloads {
+		tagged[i] = workloads[i].Cluster + "/" + workloads[i].Workload.GetName()
+	}
+	assert.Equal(t, []string{"dev/nginx", "prod/nginx", "prod/redis"}, tagged)
+}
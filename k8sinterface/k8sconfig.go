@@ -3,7 +3,9 @@ package k8sinterface
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"strings"
+	"sync"
 
 	"k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 
@@ -13,6 +15,8 @@ import (
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/scale"
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 
@@ -38,41 +42,99 @@ type KubernetesApi struct {
 	DiscoveryClient     discovery.DiscoveryInterface
 	Context             context.Context
 	K8SConfig           *restclient.Config
+
+	// clientConfig is the client config the clients were created with, reused when switching contexts
+	clientConfig ClientConfig
+	// contextName is the kubeconfig context the clients are connected with, empty for the package-level context
+	contextName string
+	// mapper maps kinds and resources with the discovery client, created on first use
+	mapper     *restmapper.DeferredDiscoveryRESTMapper
+	mapperLock sync.Mutex
+	// httpClient is the HTTP client shared by the clients, nil for fakes
+	httpClient *http.Client
+	// scaleClient reads and updates the scale subresources, created on first use
+	scaleClient scale.ScalesGetter
+	scaleLock   sync.Mutex
+	// capabilities caches the server version and API resources detected by GetServerVersion, SupportsAPI and HasFeature
+	capabilities     *serverCapabilities
+	capabilitiesLock sync.Mutex
+	// metrics are the collectors of the requests to the API server, nil if no metrics registerer is set in the client config
+	metrics *clientMetrics
+	// logger is the logger of the client config, nil to use the package-level one
+	logger Logger
 }
 
 // NewKubernetesApi -
 func NewKubernetesApi() *KubernetesApi {
-	var kubernetesClient *kubernetes.Clientset
-	var err error
+	return NewKubernetesApiWithConfig(ClientConfig{})
+}
 
+// NewKubernetesApiWithConfig works like NewKubernetesApi, with the unset fields of the given client config
+// falling back to the package-level client config and the environment
+func NewKubernetesApiWithConfig(clientConfig ClientConfig) *KubernetesApi {
 	if !IsConnectedToCluster() {
 		logger.L().Fatal("failed to load kubernetes config: no configuration has been provided, try setting KUBECONFIG environment variable")
 	}
 
-	k8sConfig := GetK8sConfig()
+	k8sAPI, err := NewKubernetesApiForConfig(GetK8sConfig(), clientConfig)
+	if err != nil {
+		logger.L().Fatal("failed to initialize a new KubernetesApi", helpers.Error(err))
+	}
+
+	restclient.SetDefaultWarningHandler(restclient.NoWarnings{})
+	InitializeMapResources(k8sAPI.DiscoveryClient)
+
+	return k8sAPI
+}
 
-	kubernetesClient, err = kubernetes.NewForConfig(k8sConfig)
+// NewKubernetesApiForConfig returns a KubernetesApi connected with a given rest config instead of the package-level one,
+// throttled by a given client config. Unlike NewKubernetesApi, it does not initialize the resource mapping of the package
+func NewKubernetesApiForConfig(restConfig *restclient.Config, clientConfig ClientConfig) (*KubernetesApi, error) {
+	if restConfig == nil {
+		return nil, fmt.Errorf("failed to load kubernetes config: no configuration has been provided")
+	}
+	resolvedConfig := resolveClientConfig(clientConfig)
+	metrics, err := newClientMetrics(resolvedConfig.MetricsRegisterer)
 	if err != nil {
-		logger.L().Fatal("failed to initialize a new kubernetes client", helpers.Error(err))
+		return nil, fmt.Errorf("failed to register the client metrics: %w", err)
 	}
+	k8sConfig := metrics.instrument(resolvedConfig.applyTo(restConfig))
+	loggerOrDefault(resolvedConfig.Logger).Debug("connecting to the API server", "host", k8sConfig.Host, "qps", k8sConfig.QPS, "burst", k8sConfig.Burst,
+		"protobuf", resolvedConfig.talksProtobuf(), "discoveryCache", resolvedConfig.DiscoveryCache != nil, "impersonate", k8sConfig.Impersonate.UserName)
 
-	dynamicClient, err := dynamic.NewForConfig(k8sConfig)
+	httpClient, err := newHTTPClient(k8sConfig)
 	if err != nil {
-		logger.L().Fatal("failed to initialize a new dynamic client", helpers.Error(err))
+		return nil, fmt.Errorf("failed to initialize a new HTTP client: %w", err)
 	}
 
-	discoveryClient, err := discovery.NewDiscoveryClientForConfig(k8sConfig)
+	kubernetesConfig := k8sConfig
+	if resolvedConfig.talksProtobuf() {
+		kubernetesConfig = withProtobuf(k8sConfig)
+	}
+	kubernetesClient, err := kubernetes.NewForConfigAndClient(metrics.throttled(kubernetesConfig), httpClient)
 	if err != nil {
-		logger.L().Fatal("failed to initialize a new discovery client", helpers.Error(err))
+		return nil, fmt.Errorf("failed to initialize a new kubernetes client: %w", err)
 	}
 
-	apiExtensionsClient, err := clientset.NewForConfig(k8sConfig)
+	dynamicClient, err := dynamic.NewForConfigAndClient(metrics.throttled(k8sConfig), httpClient)
 	if err != nil {
-		logger.L().Fatal("failed to initialize a new discovery client", helpers.Error(err))
+		return nil, fmt.Errorf("failed to initialize a new dynamic client: %w", err)
 	}
 
-	restclient.SetDefaultWarningHandler(restclient.NoWarnings{})
-	InitializeMapResources(discoveryClient)
+	var discoveryClient discovery.DiscoveryInterface
+	if resolvedConfig.DiscoveryCache != nil {
+		discoveryClient, err = newCachedDiscoveryClient(metrics.throttled(k8sConfig), *resolvedConfig.DiscoveryCache, metrics)
+	} else {
+		discoveryClient, err = discovery.NewDiscoveryClientForConfigAndClient(metrics.throttled(k8sConfig), httpClient)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize a new discovery client: %w", err)
+	}
+
+	apiExtensionsClient, err := clientset.NewForConfigAndClient(metrics.throttled(k8sConfig), httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize a new api extensions client: %w", err)
+	}
 
 	return &KubernetesApi{
 		ApiExtensionsClient: apiExtensionsClient,
@@ -81,8 +143,22 @@ func NewKubernetesApi() *KubernetesApi {
 		DiscoveryClient:     discoveryClient,
 		Context:             context.Background(),
 		K8SConfig:           k8sConfig,
-	}
+		httpClient:          httpClient,
+		clientConfig:        clientConfig,
+		metrics:             metrics,
+		logger:              resolvedConfig.Logger,
+	}, nil
+}
+
+// IKubernetesApi is the surface of the clients of a KubernetesApi, connected to a cluster or in-memory fakes returned by NewKubernetesApiFake
+type IKubernetesApi interface {
+	GetKubernetesClient() kubernetes.Interface
+	GetDynamicClient() dynamic.Interface
+	GetDiscoveryClient() discovery.DiscoveryInterface
 }
+
+var _ IKubernetesApi = &KubernetesApi{}
+
 func (k8sAPI *KubernetesApi) GetKubernetesClient() kubernetes.Interface {
 	return k8sAPI.KubernetesClient
 }
@@ -109,6 +185,7 @@ func LoadK8sConfig() error {
 	}
 
 	K8SConfig = kubeconfig
+	GetLogger().Debug("loaded kubernetes config", "host", kubeconfig.Host, "inCluster", RunningIncluster, "context", clusterContextName)
 	return nil
 }
 
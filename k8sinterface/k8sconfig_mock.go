@@ -8,14 +8,16 @@ import (
 	discoveryfake "k8s.io/client-go/discovery/fake"
 	dynamicfake "k8s.io/client-go/dynamic/fake"
 	kubernetesfake "k8s.io/client-go/kubernetes/fake"
+	kubetesting "k8s.io/client-go/testing"
 )
 
 func NewKubernetesApiMock() *KubernetesApi {
 	InitializeMapResourcesMock()
+	resourceList, _ := GetResourceListMock()
 	return &KubernetesApi{
 		KubernetesClient: kubernetesfake.NewSimpleClientset(),
 		DynamicClient:    dynamicfake.NewSimpleDynamicClient(&runtime.Scheme{}),
-		DiscoveryClient:  &discoveryfake.FakeDiscovery{},
+		DiscoveryClient:  &discoveryfake.FakeDiscovery{Fake: &kubetesting.Fake{Resources: resourceList}},
 		Context:          context.Background(),
 	}
 }
@@ -137,4 +137,6 @@ func tearDown() {
 	SetClusterContextName("")
 	SetConfigClusterServerName("")
 	SetK8SGitServerVersion("")
+	SetDryRun(false)
+	SetClientConfig(ClientConfig{})
 }
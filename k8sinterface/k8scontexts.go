@@ -0,0 +1,98 @@
+package k8sinterface
+
+import (
+	"fmt"
+	"sort"
+
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// ListContexts returns the names of the contexts of the kubeconfig, sorted
+func ListContexts() []string {
+	kubeConfig := GetConfig()
+	if kubeConfig == nil {
+		return nil
+	}
+	contextNames := make([]string, 0, len(kubeConfig.Contexts))
+	for contextName := range kubeConfig.Contexts {
+		contextNames = append(contextNames, contextName)
+	}
+	sort.Strings(contextNames)
+	return contextNames
+}
+
+// NewKubernetesApiForContext returns a KubernetesApi connected with a given kubeconfig context instead of the current one,
+// throttled by a given client config
+func NewKubernetesApiForContext(contextName string, clientConfig ClientConfig) (*KubernetesApi, error) {
+	restConfig, err := restConfigForContext(contextName)
+	if err != nil {
+		return nil, err
+	}
+	k8sAPI, err := NewKubernetesApiForConfig(restConfig, clientConfig)
+	if err != nil {
+		return nil, err
+	}
+	k8sAPI.contextName = contextName
+
+	restclient.SetDefaultWarningHandler(restclient.NoWarnings{})
+	InitializeMapResources(k8sAPI.DiscoveryClient)
+
+	return k8sAPI, nil
+}
+
+// SwitchContext reconnects the KubernetesApi with a given kubeconfig context, rebuilding its clients with the client config it was created with
+//
+// It must not be called concurrently with other calls on the KubernetesApi. The resource mapping of the package is kept as is.
+// If the context cannot be loaded, the KubernetesApi stays connected with its current context
+func (k8sAPI *KubernetesApi) SwitchContext(contextName string) error {
+	restConfig, err := restConfigForContext(contextName)
+	if err != nil {
+		return err
+	}
+	switched, err := NewKubernetesApiForConfig(restConfig, k8sAPI.clientConfig)
+	if err != nil {
+		return err
+	}
+
+	k8sAPI.ApiExtensionsClient = switched.ApiExtensionsClient
+	k8sAPI.KubernetesClient = switched.KubernetesClient
+	k8sAPI.DynamicClient = switched.DynamicClient
+	k8sAPI.DiscoveryClient = switched.DiscoveryClient
+	k8sAPI.K8SConfig = switched.K8SConfig
+	k8sAPI.contextName = contextName
+	k8sAPI.mapperLock.Lock()
+	k8sAPI.mapper = nil
+	k8sAPI.mapperLock.Unlock()
+	k8sAPI.scaleLock.Lock()
+	k8sAPI.httpClient = switched.httpClient
+	k8sAPI.scaleClient = nil
+	k8sAPI.scaleLock.Unlock()
+	k8sAPI.resetServerCapabilities()
+	k8sAPI.GetLogger().Debug("switched kubeconfig context", "context", contextName, "host", k8sAPI.K8SConfig.Host)
+	return nil
+}
+
+// ContextName returns the name of the kubeconfig context the KubernetesApi is connected with
+func (k8sAPI *KubernetesApi) ContextName() string {
+	if k8sAPI.contextName != "" {
+		return k8sAPI.contextName
+	}
+	return GetContextName()
+}
+
+// restConfigForContext loads the rest config of a given kubeconfig context
+func restConfigForContext(contextName string) (*restclient.Config, error) {
+	kubeConfig := GetConfig()
+	if kubeConfig == nil {
+		return nil, fmt.Errorf("failed to load kubernetes config: no configuration has been provided, try setting KUBECONFIG environment variable")
+	}
+	if _, exist := kubeConfig.Contexts[contextName]; !exist {
+		return nil, fmt.Errorf("context '%s' not found in kubeconfig", contextName)
+	}
+	restConfig, err := clientcmd.NewNonInteractiveClientConfig(*kubeConfig, contextName, &clientcmd.ConfigOverrides{}, nil).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubernetes config of context '%s', reason: %s", contextName, err.Error())
+	}
+	return restConfig, nil
+}
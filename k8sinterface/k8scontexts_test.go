@@ -0,0 +1,40 @@
+package k8sinterface
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListContexts(t *testing.T) {
+	defer tearDown()
+
+	SetClientConfigAPI(newTestKubeconfig("prod", "dev"))
+	assert.Equal(t, []string{"dev", "prod"}, ListContexts())
+
+	SetConnectedToCluster(false)
+	assert.Nil(t, ListContexts())
+}
+
+func TestSwitchContext(t *testing.T) {
+	defer tearDown()
+
+	kubeconfig := newTestKubeconfig("dev", "prod")
+	kubeconfig.CurrentContext = "dev"
+	SetClientConfigAPI(kubeconfig)
+
+	k8sAPI := NewKubernetesApiMock()
+	k8sAPI.clientConfig = ClientConfig{QPS: 50}
+	assert.Equal(t, "dev", k8sAPI.ContextName())
+
+	assert.NoError(t, k8sAPI.SwitchContext("prod"))
+	assert.Equal(t, "prod", k8sAPI.ContextName())
+	assert.Equal(t, "https://prod.example.com", k8sAPI.K8SConfig.Host)
+	assert.Equal(t, float32(50), k8sAPI.K8SConfig.QPS)
+	assert.NotNil(t, k8sAPI.Context)
+
+	// a failed switch keeps the current context
+	assert.Error(t, k8sAPI.SwitchContext("staging"))
+	assert.Equal(t, "prod", k8sAPI.ContextName())
+	assert.Equal(t, "https://prod.example.com", k8sAPI.K8SConfig.Host)
+}
@@ -0,0 +1,108 @@
+package k8sinterface
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// DeleteAndWaitPollInterval is how often DeleteAndWait checks whether a deleted object is gone
+var DeleteAndWaitPollInterval = time.Second
+
+// DeleteOptions sets how an object is deleted, unset fields keep the defaults of the API server
+type DeleteOptions struct {
+	// PropagationPolicy sets whether and how the dependents of the object are deleted:
+	// metav1.DeletePropagationForeground, metav1.DeletePropagationBackground or metav1.DeletePropagationOrphan
+	PropagationPolicy metav1.DeletionPropagation
+	// GracePeriodSeconds is how long the object is given to terminate, 0 deletes it right away
+	GracePeriodSeconds *int64
+	// UID deletes the object only if it has the given UID, so that an object recreated with the same name is not deleted
+	UID types.UID
+	// ResourceVersion deletes the object only if it was not changed since the given resource version
+	ResourceVersion string
+}
+
+func (options DeleteOptions) toDeleteOptions(ctx context.Context) (metav1.DeleteOptions, error) {
+	deleteOptions := metav1.DeleteOptions{DryRun: dryRunOptions(ctx)}
+	switch options.PropagationPolicy {
+	case "":
+	case metav1.DeletePropagationForeground, metav1.DeletePropagationBackground, metav1.DeletePropagationOrphan:
+		deleteOptions.PropagationPolicy = &options.PropagationPolicy
+	default:
+		return deleteOptions, fmt.Errorf("invalid propagation policy '%s'", options.PropagationPolicy)
+	}
+	if options.GracePeriodSeconds != nil {
+		if *options.GracePeriodSeconds < 0 {
+			return deleteOptions, fmt.Errorf("invalid grace period '%d', it must not be negative", *options.GracePeriodSeconds)
+		}
+		deleteOptions.GracePeriodSeconds = options.GracePeriodSeconds
+	}
+	if options.UID != "" || options.ResourceVersion != "" {
+		deleteOptions.Preconditions = &metav1.Preconditions{}
+		if options.UID != "" {
+			deleteOptions.Preconditions.UID = &options.UID
+		}
+		if options.ResourceVersion != "" {
+			deleteOptions.Preconditions.ResourceVersion = &options.ResourceVersion
+		}
+	}
+	return deleteOptions, nil
+}
+
+// DeleteResource deletes a given object of a given resource with given options.
+// If a precondition is not met, the object is not deleted and the returned error wraps a conflict error
+func (k8sAPI *KubernetesApi) DeleteResource(ctx context.Context, groupVersionResource *schema.GroupVersionResource, name, namespace string, options DeleteOptions) error {
+	deleteOptions, err := options.toDeleteOptions(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to DELETE resource, resource: '%s', namespace: '%s', name: '%s', reason: %s", groupVersionResource.String(), namespace, name, err.Error())
+	}
+	if err := k8sAPI.ResourceInterface(groupVersionResource, namespace).Delete(ctx, name, deleteOptions); err != nil {
+		return fmt.Errorf("failed to DELETE resource, resource: '%s', namespace: '%s', name: '%s', reason: %w", groupVersionResource.String(), namespace, name, err)
+	}
+	return nil
+}
+
+// DeleteAndWait works like DeleteResource, and waits until the object is gone, e.g. once its finalizers are done,
+// or until the context is done. An object already gone is not an error
+//
+// An object recreated with the same name while waiting counts as gone. In dry run mode, it returns right after the deletion
+func (k8sAPI *KubernetesApi) DeleteAndWait(ctx context.Context, groupVersionResource *schema.GroupVersionResource, name, namespace string, options DeleteOptions) error {
+	resourceInterface := k8sAPI.ResourceInterface(groupVersionResource, namespace)
+	obj, err := resourceInterface.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to GET resource, resource: '%s', namespace: '%s', name: '%s', reason: %w", groupVersionResource.String(), namespace, name, err)
+	}
+	if err := k8sAPI.DeleteResource(ctx, groupVersionResource, name, namespace, options); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if len(dryRunOptions(ctx)) != 0 {
+		return nil
+	}
+
+	err = wait.PollUntilContextCancel(ctx, DeleteAndWaitPollInterval, true, func(ctx context.Context) (bool, error) {
+		current, err := resourceInterface.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return true, nil
+			}
+			return false, err
+		}
+		return current.GetUID() != obj.GetUID(), nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to wait for resource deletion, resource: '%s', namespace: '%s', name: '%s', reason: %w", groupVersionResource.String(), namespace, name, err)
+	}
+	return nil
+}
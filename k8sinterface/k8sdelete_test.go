@@ -0,0 +1,185 @@
+package k8sinterface
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	restclient "k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newDeleteDeployment(name, uid string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": "default",
+			"uid":       uid,
+		},
+	}}
+}
+
+func TestDeleteResource(t *testing.T) {
+	defer tearDown()
+
+	deploymentsGVR := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	gracePeriod, negativeGracePeriod := int64(0), int64(-1)
+	uid, resourceVersion := types.UID("1234"), "42"
+	foreground := metav1.DeletePropagationForeground
+
+	tests := []struct {
+		name    string
+		options DeleteOptions
+		want    metav1.DeleteOptions
+		wantErr bool
+	}{
+		{
+			name: "Default options",
+		},
+		{
+			name:    "Propagation policy and grace period",
+			options: DeleteOptions{PropagationPolicy: metav1.DeletePropagationForeground, GracePeriodSeconds: &gracePeriod},
+			want:    metav1.DeleteOptions{PropagationPolicy: &foreground, GracePeriodSeconds: &gracePeriod},
+		},
+		{
+			name:    "Preconditions",
+			options: DeleteOptions{UID: uid, ResourceVersion: resourceVersion},
+			want:    metav1.DeleteOptions{Preconditions: &metav1.Preconditions{UID: &uid, ResourceVersion: &resourceVersion}},
+		},
+		{
+			name:    "Invalid propagation policy",
+			options: DeleteOptions{PropagationPolicy: "Cascade"},
+			wantErr: true,
+		},
+		{
+			name:    "Negative grace period",
+			options: DeleteOptions{GracePeriodSeconds: &negativeGracePeriod},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// an API server recording the delete options, which the fake dynamic client drops
+			var lock sync.Mutex
+			var deleteOptions *metav1.DeleteOptions
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				options := metav1.DeleteOptions{}
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&options))
+				options.TypeMeta = metav1.TypeMeta{}
+				lock.Lock()
+				deleteOptions = &options
+				lock.Unlock()
+				w.Header().Set("Content-Type", runtime.ContentTypeJSON)
+				w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Success"}`))
+			}))
+			defer server.Close()
+			k8sAPI, err := NewKubernetesApiForConfig(&restclient.Config{Host: server.URL}, ClientConfig{})
+			assert.NoError(t, err)
+
+			err = k8sAPI.DeleteResource(context.Background(), &deploymentsGVR, "nginx", "default", tt.options)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, deleteOptions)
+				return
+			}
+			assert.NoError(t, err)
+			lock.Lock()
+			defer lock.Unlock()
+			assert.Equal(t, &tt.want, deleteOptions)
+		})
+	}
+
+	t.Run("Unmet precondition", func(t *testing.T) {
+		k8sAPI := NewKubernetesApiMock()
+		client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+		client.PrependReactor("delete", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, apierrors.NewConflict(deploymentsGVR.GroupResource(), "nginx", assert.AnError)
+		})
+		k8sAPI.DynamicClient = client
+
+		err := k8sAPI.DeleteResource(context.Background(), &deploymentsGVR, "nginx", "default", DeleteOptions{UID: uid})
+		assert.True(t, apierrors.IsConflict(err))
+	})
+}
+
+func TestDeleteAndWait(t *testing.T) {
+	deploymentsGVR := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	defer func(interval time.Duration) { DeleteAndWaitPollInterval = interval }(DeleteAndWaitPollInterval)
+	DeleteAndWaitPollInterval = 10 * time.Millisecond
+
+	// newClient returns a client keeping deleted objects, as if blocked by finalizers, until a given function is called
+	newClient := func(objects ...runtime.Object) (*dynamicfake.FakeDynamicClient, func(obj runtime.Object)) {
+		client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), objects...)
+		client.PrependReactor("delete", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, nil
+		})
+		finalize := func(obj runtime.Object) {
+			assert.NoError(t, client.Tracker().Delete(deploymentsGVR, "default", "nginx"))
+			if obj != nil {
+				assert.NoError(t, client.Tracker().Create(deploymentsGVR, obj, "default"))
+			}
+		}
+		return client, finalize
+	}
+
+	t.Run("Waits until the object is gone", func(t *testing.T) {
+		k8sAPI := NewKubernetesApiMock()
+		client, finalize := newClient(newDeleteDeployment("nginx", "1234"))
+		k8sAPI.DynamicClient = client
+		time.AfterFunc(50*time.Millisecond, func() { finalize(nil) })
+
+		start := time.Now()
+		assert.NoError(t, k8sAPI.DeleteAndWait(context.Background(), &deploymentsGVR, "nginx", "default", DeleteOptions{}))
+		assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	})
+
+	t.Run("Recreated object counts as gone", func(t *testing.T) {
+		k8sAPI := NewKubernetesApiMock()
+		client, finalize := newClient(newDeleteDeployment("nginx", "1234"))
+		k8sAPI.DynamicClient = client
+		time.AfterFunc(50*time.Millisecond, func() { finalize(newDeleteDeployment("nginx", "5678")) })
+
+		assert.NoError(t, k8sAPI.DeleteAndWait(context.Background(), &deploymentsGVR, "nginx", "default", DeleteOptions{}))
+	})
+
+	t.Run("Object already gone", func(t *testing.T) {
+		k8sAPI := NewKubernetesApiMock()
+		client, _ := newClient()
+		k8sAPI.DynamicClient = client
+
+		assert.NoError(t, k8sAPI.DeleteAndWait(context.Background(), &deploymentsGVR, "nginx", "default", DeleteOptions{}))
+	})
+
+	t.Run("Context timeout", func(t *testing.T) {
+		k8sAPI := NewKubernetesApiMock()
+		client, _ := newClient(newDeleteDeployment("nginx", "1234"))
+		k8sAPI.DynamicClient = client
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		err := k8sAPI.DeleteAndWait(ctx, &deploymentsGVR, "nginx", "default", DeleteOptions{})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("Dry run", func(t *testing.T) {
+		k8sAPI := NewKubernetesApiMock()
+		client, _ := newClient(newDeleteDeployment("nginx", "1234"))
+		k8sAPI.DynamicClient = client
+
+		assert.NoError(t, k8sAPI.DeleteAndWait(WithDryRun(context.Background()), &deploymentsGVR, "nginx", "default", DeleteOptions{}))
+	})
+}
@@ -102,6 +102,23 @@ func InitializeMapResources(discoveryClient discovery.DiscoveryInterface) {
 
 }
 func setMapResources(resourceList []*metav1.APIResourceList) {
+	resourcesInfoLock.Lock()
+	defer resourcesInfoLock.Unlock()
+	addMapResources(resourceList)
+}
+
+// replaceMapResources replaces the mapping of resources with the resources of a given list at once, readers never see it empty
+func replaceMapResources(resourceList []*metav1.APIResourceList) {
+	resourcesInfoLock.Lock()
+	defer resourcesInfoLock.Unlock()
+	resourceGroupMapping = map[string]string{}
+	resourceNamesapcedScope = []string{}
+	ResourceClusterScope = []string{}
+	addMapResources(resourceList)
+}
+
+// addMapResources adds the resources of a given list missing from the mapping of resources, the caller must hold resourcesInfoLock
+func addMapResources(resourceList []*metav1.APIResourceList) {
 	for i := range resourceList {
 		if resourceList[i] == nil {
 			continue
@@ -125,14 +142,10 @@ func setMapResources(resourceList []*metav1.APIResourceList) {
 				continue
 			}
 
-			resourcesInfoLock.RLock()
-			_, ok := resourceGroupMapping[apiResource.Name]
-			resourcesInfoLock.RUnlock()
-			if ok { // do not override resources in map
+			if _, ok := resourceGroupMapping[apiResource.Name]; ok { // do not override resources in map
 				continue
 			}
 
-			resourcesInfoLock.Lock()
 			resourceGroupMapping[apiResource.Name] = JoinGroupVersion(gv.Group, gv.Version)
 			if apiResource.Namespaced {
 				resourceNamesapcedScope = append(resourceNamesapcedScope, JoinResourceTriplets(gv.Group, gv.Version, apiResource.Name))
@@ -140,7 +153,6 @@ func setMapResources(resourceList []*metav1.APIResourceList) {
 				ResourceClusterScope = append(ResourceClusterScope, JoinResourceTriplets(gv.Group, gv.Version, apiResource.Name))
 
 			}
-			resourcesInfoLock.Unlock()
 		}
 	}
 }
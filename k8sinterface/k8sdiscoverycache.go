@@ -0,0 +1,175 @@
+package k8sinterface
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/disk"
+	"k8s.io/client-go/discovery/cached/memory"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/util/homedir"
+)
+
+// DefaultDiscoveryCacheTTL is how long discovered API resources are cached when DiscoveryCacheConfig.TTL is not set
+const DefaultDiscoveryCacheTTL = 10 * time.Minute
+
+// DiscoveryCacheConfig configures the caching of the API resources discovered by a KubernetesApi, in memory and on disk
+type DiscoveryCacheConfig struct {
+	// Dir is the directory of the disk cache, shared with kubectl by default: ~/.kube/cache
+	Dir string
+	// TTL is how long discovered API resources are cached, DefaultDiscoveryCacheTTL if zero
+	TTL time.Duration
+}
+
+// invalidCacheDirChars are the characters of a host replaced when computing its cache directory, the way kubectl does it
+var invalidCacheDirChars = regexp.MustCompile(`[^(\w/.)]`)
+
+// newCachedDiscoveryClient returns a discovery client caching the API resources in memory, over a cache on disk, whose lookups are counted by given metrics
+func newCachedDiscoveryClient(restConfig *restclient.Config, cacheConfig DiscoveryCacheConfig, metrics *clientMetrics) (discovery.CachedDiscoveryInterface, error) {
+	cacheDir := cacheConfig.Dir
+	if cacheDir == "" {
+		cacheDir = filepath.Join(homedir.HomeDir(), ".kube", "cache")
+	}
+	ttl := cacheConfig.TTL
+	if ttl == 0 {
+		ttl = DefaultDiscoveryCacheTTL
+	}
+
+	// one directory per API server, like kubectl
+	host := strings.Replace(strings.Replace(restConfig.Host, "https://", "", 1), "http://", "", 1)
+	discoveryCacheDir := filepath.Join(cacheDir, "discovery", invalidCacheDirChars.ReplaceAllString(host, "_"))
+	diskClient, err := disk.NewCachedDiscoveryClientForConfig(restConfig, discoveryCacheDir, filepath.Join(cacheDir, "http"), ttl)
+	if err != nil {
+		return nil, err
+	}
+	return newTTLDiscoveryClient(memory.NewMemCacheClient(diskClient), ttl, metrics), nil
+}
+
+// ttlDiscoveryClient invalidates a cached discovery client once its cache is older than a TTL
+//
+// It is fresh as long as the cache was fetched from the API server after the last invalidation and is not expired,
+// a resource missing from a fresh cache is missing from the API server.
+// Its lookups are counted as hits if the cache is populated, and as misses if the API resources are discovered again
+type ttlDiscoveryClient struct {
+	discovery.CachedDiscoveryInterface
+	ttl     time.Duration
+	now     func() time.Time
+	metrics *clientMetrics
+
+	lock          sync.Mutex
+	invalidatedAt time.Time
+	expiresAt     time.Time
+}
+
+func newTTLDiscoveryClient(cachedClient discovery.CachedDiscoveryInterface, ttl time.Duration, metrics *clientMetrics) *ttlDiscoveryClient {
+	return &ttlDiscoveryClient{
+		CachedDiscoveryInterface: cachedClient,
+		ttl:                      ttl,
+		now:                      time.Now,
+		metrics:                  metrics,
+		expiresAt:                time.Now().Add(ttl),
+	}
+}
+
+func (c *ttlDiscoveryClient) Fresh() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return !c.invalidatedAt.IsZero() && c.now().Before(c.expiresAt)
+}
+
+func (c *ttlDiscoveryClient) Invalidate() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.invalidateLocked()
+}
+
+func (c *ttlDiscoveryClient) invalidateLocked() {
+	c.CachedDiscoveryInterface.Invalidate()
+	c.invalidatedAt = c.now()
+	c.expiresAt = c.invalidatedAt.Add(c.ttl)
+}
+
+// expire invalidates the cache if it is older than the TTL, then counts a lookup of the cache
+func (c *ttlDiscoveryClient) expire() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if !c.now().Before(c.expiresAt) {
+		c.invalidateLocked()
+	}
+	// the cached client is fresh once populated
+	c.metrics.cacheLookedUp("discovery", c.CachedDiscoveryInterface.Fresh())
+}
+
+func (c *ttlDiscoveryClient) ServerGroups() (*metav1.APIGroupList, error) {
+	c.expire()
+	return c.CachedDiscoveryInterface.ServerGroups()
+}
+
+func (c *ttlDiscoveryClient) ServerResourcesForGroupVersion(groupVersion string) (*metav1.APIResourceList, error) {
+	c.expire()
+	return c.CachedDiscoveryInterface.ServerResourcesForGroupVersion(groupVersion)
+}
+
+func (c *ttlDiscoveryClient) ServerGroupsAndResources() ([]*metav1.APIGroup, []*metav1.APIResourceList, error) {
+	c.expire()
+	return c.CachedDiscoveryInterface.ServerGroupsAndResources()
+}
+
+func (c *ttlDiscoveryClient) ServerPreferredResources() ([]*metav1.APIResourceList, error) {
+	c.expire()
+	return c.CachedDiscoveryInterface.ServerPreferredResources()
+}
+
+func (c *ttlDiscoveryClient) ServerPreferredNamespacedResources() ([]*metav1.APIResourceList, error) {
+	c.expire()
+	return c.CachedDiscoveryInterface.ServerPreferredNamespacedResources()
+}
+
+// InvalidateDiscovery drops the cached API resources and discovers them again, to pick up the CRDs installed since they were cached
+func (k8sAPI *KubernetesApi) InvalidateDiscovery() {
+	if cachedClient, ok := k8sAPI.DiscoveryClient.(discovery.CachedDiscoveryInterface); ok {
+		cachedClient.Invalidate()
+	}
+	k8sAPI.resetRESTMapper()
+	k8sAPI.resetServerCapabilities()
+	// keep the current mapping if the API resources can not be discovered
+	if resourceList, _ := k8sAPI.DiscoveryClient.ServerPreferredResources(); len(resourceList) != 0 {
+		replaceMapResources(resourceList)
+	}
+}
+
+// invalidateDiscoveryOnNoMatch invalidates the cached API resources if a given error is about a resource missing from a stale cache,
+// returning whether they were invalidated
+func (k8sAPI *KubernetesApi) invalidateDiscoveryOnNoMatch(err error) bool {
+	if err == nil || !(meta.IsNoMatchError(err) || strings.Contains(err.Error(), ResourceNotFoundErr)) {
+		return false
+	}
+	cachedClient, ok := k8sAPI.DiscoveryClient.(discovery.CachedDiscoveryInterface)
+	if !ok || cachedClient.Fresh() {
+		return false
+	}
+	k8sAPI.InvalidateDiscovery()
+	return true
+}
+
+// getGroupVersionResource works like GetGroupVersionResource, discovering the API resources again if the resource is missing from a stale cache
+func (k8sAPI *KubernetesApi) getGroupVersionResource(resource string) (schema.GroupVersionResource, error) {
+	groupVersionResource, err := GetGroupVersionResource(resource)
+	if k8sAPI.invalidateDiscoveryOnNoMatch(err) {
+		groupVersionResource, err = GetGroupVersionResource(resource)
+	}
+	if err != nil && resource != "" {
+		// the mapping of the package guesses plurals, the RESTMapper knows them
+		if mapped, mapperErr := k8sAPI.restMapper().ResourceFor(schema.GroupVersionResource{Resource: strings.ToLower(resource)}); mapperErr == nil {
+			return mapped, nil
+		}
+	}
+	return groupVersionResource, err
+}
@@ -0,0 +1,99 @@
+package k8sinterface
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery/cached/memory"
+	discoveryfake "k8s.io/client-go/discovery/fake"
+	kubetesting "k8s.io/client-go/testing"
+)
+
+func newFakeDiscovery(resources ...string) *discoveryfake.FakeDiscovery {
+	fakeDiscovery := &discoveryfake.FakeDiscovery{Fake: &kubetesting.Fake{}}
+	fakeDiscovery.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{{Name: "pods", Kind: "Pod", Namespaced: true, Verbs: []string{"get", "list"}}},
+		},
+		fakeCustomResources(resources...),
+	}
+	return fakeDiscovery
+}
+
+func fakeCustomResources(resources ...string) *metav1.APIResourceList {
+	resourceList := &metav1.APIResourceList{GroupVersion: "example.com/v1"}
+	for _, resource := range resources {
+		resourceList.APIResources = append(resourceList.APIResources, metav1.APIResource{Name: resource, Namespaced: true, Verbs: []string{"get", "list"}})
+	}
+	return resourceList
+}
+
+func TestTTLDiscoveryClient(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	fakeDiscovery := newFakeDiscovery("widgets")
+	cachedClient := newTTLDiscoveryClient(memory.NewMemCacheClient(fakeDiscovery), time.Minute, nil)
+	cachedClient.now = func() time.Time { return now }
+
+	// never fetched from the API server after an invalidation
+	assert.False(t, cachedClient.Fresh())
+	cachedClient.Invalidate()
+	assert.True(t, cachedClient.Fresh())
+
+	resourceList, err := cachedClient.ServerResourcesForGroupVersion("example.com/v1")
+	assert.NoError(t, err)
+	assert.Len(t, resourceList.APIResources, 1)
+
+	// cached until the TTL expires
+	fakeDiscovery.Resources[1] = fakeCustomResources("widgets", "gadgets")
+	resourceList, err = cachedClient.ServerResourcesForGroupVersion("example.com/v1")
+	assert.NoError(t, err)
+	assert.Len(t, resourceList.APIResources, 1)
+
+	now = now.Add(2 * time.Minute)
+	assert.False(t, cachedClient.Fresh())
+	resourceList, err = cachedClient.ServerResourcesForGroupVersion("example.com/v1")
+	assert.NoError(t, err)
+	assert.Len(t, resourceList.APIResources, 2)
+	assert.True(t, cachedClient.Fresh())
+}
+
+func TestInvalidateDiscovery(t *testing.T) {
+	defer func() {
+		resourceList, _ := GetResourceListMock()
+		replaceMapResources(resourceList)
+	}()
+
+	// the memory cache rejects a group version serving no resources
+	fakeDiscovery := newFakeDiscovery("gizmos")
+	cachedClient := newTTLDiscoveryClient(memory.NewMemCacheClient(fakeDiscovery), time.Hour, nil)
+	_, _, err := cachedClient.ServerGroupsAndResources()
+	assert.NoError(t, err)
+
+	k8sAPI := NewKubernetesApiMock()
+	k8sAPI.DiscoveryClient = cachedClient
+
+	// a CRD installed after the API resources were cached
+	fakeDiscovery.Resources[1] = fakeCustomResources("gizmos", "widgets")
+	_, err = GetGroupVersionResource("widgets")
+	assert.Error(t, err)
+
+	// missing from a stale cache, discovered again
+	groupVersionResource, err := k8sAPI.getGroupVersionResource("widgets")
+	assert.NoError(t, err)
+	assert.Equal(t, "example.com", groupVersionResource.Group)
+	assert.Equal(t, "v1", groupVersionResource.Version)
+	assert.True(t, cachedClient.Fresh())
+
+	// missing from a fresh cache, not discovered again
+	fakeDiscovery.Resources[1] = fakeCustomResources("gizmos", "widgets", "gadgets")
+	_, err = k8sAPI.getGroupVersionResource("gadgets")
+	assert.Error(t, err)
+
+	k8sAPI.InvalidateDiscovery()
+	_, err = GetGroupVersionResource("gadgets")
+	assert.NoError(t, err)
+	assert.True(t, IsResourceInNamespaceScope("gadgets"))
+}
@@ -0,0 +1,37 @@
+package k8sinterface
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var dryRun = false
+
+// dryRunContextKey is the context key WithDryRun stores the dry-run mode under
+type dryRunContextKey struct{}
+
+// SetDryRun sets whether all mutating operations (create, update, apply and delete) run in dry-run mode:
+// the server validates and admits the request, and returns the object as it would be persisted, without persisting it
+func SetDryRun(isDryRun bool) {
+	dryRun = isDryRun
+}
+
+// IsDryRun returns whether all mutating operations run in dry-run mode
+func IsDryRun() bool {
+	return dryRun
+}
+
+// WithDryRun returns a copy of a given context that runs the mutating operations it is passed to in dry-run mode,
+// e.g. k8sAPI.CreateWorkloadWithContext(WithDryRun(ctx), workload), regardless of SetDryRun
+func WithDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunContextKey{}, true)
+}
+
+// dryRunOptions returns the dryRun option of a mutating request made with a given context
+func dryRunOptions(ctx context.Context) []string {
+	if isDryRun, _ := ctx.Value(dryRunContextKey{}).(bool); isDryRun || dryRun {
+		return []string{metav1.DryRunAll}
+	}
+	return nil
+}
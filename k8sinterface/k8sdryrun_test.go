@@ -0,0 +1,46 @@
+package k8sinterface
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDryRunOptions(t *testing.T) {
+	defer tearDown()
+
+	tests := []struct {
+		name     string
+		isDryRun bool
+		ctx      context.Context
+		want     []string
+	}{
+		{
+			name:     "Dry-run is off by default",
+			isDryRun: false,
+			ctx:      context.Background(),
+			want:     nil,
+		},
+		{
+			name:     "Dry-run set for the package",
+			isDryRun: true,
+			ctx:      context.Background(),
+			want:     []string{metav1.DryRunAll},
+		},
+		{
+			name:     "Dry-run set for the call",
+			isDryRun: false,
+			ctx:      WithDryRun(context.Background()),
+			want:     []string{metav1.DryRunAll},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetDryRun(tt.isDryRun)
+			assert.Equal(t, tt.isDryRun, IsDryRun())
+			assert.Equal(t, tt.want, dryRunOptions(tt.ctx))
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package k8sinterface
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -25,6 +26,11 @@ import (
 type IWorkload workloadinterface.IWorkload
 
 func (k8sAPI *KubernetesApi) ListAllWorkload() ([]IWorkload, error) {
+	return k8sAPI.ListAllWorkloadWithContext(k8sAPI.Context)
+}
+
+// ListAllWorkloadWithContext works like ListAllWorkload, but makes the API calls with a given context
+func (k8sAPI *KubernetesApi) ListAllWorkloadWithContext(ctx context.Context) ([]IWorkload, error) {
 	workloads := []IWorkload{}
 	var errs error
 	for resource := range GetResourceGroupMapping() {
@@ -33,7 +39,7 @@ func (k8sAPI *KubernetesApi) ListAllWorkload() ([]IWorkload, error) {
 			errs = fmt.Errorf("%v\n%s", errs, err.Error())
 			continue
 		}
-		w, err := k8sAPI.ListWorkloads(&groupVersionResource, "", nil, nil)
+		w, err := k8sAPI.ListWorkloadsWithContext(ctx, &groupVersionResource, "", nil, nil)
 		if err != nil {
 			errs = fmt.Errorf("%v\n%s", errs, err.Error())
 			continue
@@ -47,16 +53,26 @@ func (k8sAPI *KubernetesApi) ListAllWorkload() ([]IWorkload, error) {
 }
 
 func (k8sAPI *KubernetesApi) GetWorkloadByWlid(wlid string) (IWorkload, error) {
-	return k8sAPI.GetWorkload(wlidpkg.GetNamespaceFromWlid(wlid), wlidpkg.GetKindFromWlid(wlid), wlidpkg.GetNameFromWlid(wlid))
+	return k8sAPI.GetWorkloadByWlidWithContext(k8sAPI.Context, wlid)
+}
+
+// GetWorkloadByWlidWithContext works like GetWorkloadByWlid, but makes the API call with a given context
+func (k8sAPI *KubernetesApi) GetWorkloadByWlidWithContext(ctx context.Context, wlid string) (IWorkload, error) {
+	return k8sAPI.GetWorkloadWithContext(ctx, wlidpkg.GetNamespaceFromWlid(wlid), wlidpkg.GetKindFromWlid(wlid), wlidpkg.GetNameFromWlid(wlid))
 }
 
 func (k8sAPI *KubernetesApi) GetWorkload(namespace, kind, name string) (IWorkload, error) {
-	groupVersionResource, err := GetGroupVersionResource(kind)
+	return k8sAPI.GetWorkloadWithContext(k8sAPI.Context, namespace, kind, name)
+}
+
+// GetWorkloadWithContext works like GetWorkload, but makes the API call with a given context
+func (k8sAPI *KubernetesApi) GetWorkloadWithContext(ctx context.Context, namespace, kind, name string) (IWorkload, error) {
+	groupVersionResource, err := k8sAPI.getGroupVersionResource(kind)
 	if err != nil {
 		return nil, err
 	}
 
-	w, err := k8sAPI.ResourceInterface(&groupVersionResource, namespace).Get(k8sAPI.Context, name, metav1.GetOptions{})
+	w, err := k8sAPI.ResourceInterface(&groupVersionResource, namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to GET resource, kind: '%s', namespace: '%s', name: '%s', reason: %s", kind, namespace, name, err.Error())
 	}
@@ -64,12 +80,17 @@ func (k8sAPI *KubernetesApi) GetWorkload(namespace, kind, name string) (IWorkloa
 }
 
 func (k8sAPI *KubernetesApi) ListWorkloads2(namespace, kind string) ([]IWorkload, error) {
-	groupVersionResource, err := GetGroupVersionResource(kind)
+	return k8sAPI.ListWorkloads2WithContext(k8sAPI.Context, namespace, kind)
+}
+
+// ListWorkloads2WithContext works like ListWorkloads2, but makes the API call with a given context
+func (k8sAPI *KubernetesApi) ListWorkloads2WithContext(ctx context.Context, namespace, kind string) ([]IWorkload, error) {
+	groupVersionResource, err := k8sAPI.getGroupVersionResource(kind)
 	if err != nil {
 		return nil, err
 	}
 
-	uList, err := k8sAPI.ResourceInterface(&groupVersionResource, namespace).List(k8sAPI.Context, metav1.ListOptions{})
+	uList, err := k8sAPI.ResourceInterface(&groupVersionResource, namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to LIST resources, reason: %s", err.Error())
 	}
@@ -81,6 +102,11 @@ func (k8sAPI *KubernetesApi) ListWorkloads2(namespace, kind string) ([]IWorkload
 }
 
 func (k8sAPI *KubernetesApi) ListWorkloads(groupVersionResource *schema.GroupVersionResource, namespace string, podLabels, fieldSelector map[string]string) ([]IWorkload, error) {
+	return k8sAPI.ListWorkloadsWithContext(k8sAPI.Context, groupVersionResource, namespace, podLabels, fieldSelector)
+}
+
+// ListWorkloadsWithContext works like ListWorkloads, but makes the API call with a given context
+func (k8sAPI *KubernetesApi) ListWorkloadsWithContext(ctx context.Context, groupVersionResource *schema.GroupVersionResource, namespace string, podLabels, fieldSelector map[string]string) ([]IWorkload, error) {
 	listOptions := metav1.ListOptions{}
 	if len(podLabels) > 0 {
 		set := labels.Set(podLabels)
@@ -90,7 +116,7 @@ func (k8sAPI *KubernetesApi) ListWorkloads(groupVersionResource *schema.GroupVer
 		set := labels.Set(fieldSelector)
 		listOptions.FieldSelector = SelectorToString(set)
 	}
-	uList, err := k8sAPI.ResourceInterface(groupVersionResource, namespace).List(k8sAPI.Context, listOptions)
+	uList, err := k8sAPI.ResourceInterface(groupVersionResource, namespace).List(ctx, listOptions)
 	if err != nil {
 		return nil, fmt.Errorf("failed to LIST resources, reason: %s", err.Error())
 	}
@@ -102,11 +128,25 @@ func (k8sAPI *KubernetesApi) ListWorkloads(groupVersionResource *schema.GroupVer
 }
 
 func (k8sAPI *KubernetesApi) DeleteWorkloadByWlid(wlid string) error {
-	groupVersionResource, err := GetGroupVersionResource(wlidpkg.GetKindFromWlid(wlid))
+	return k8sAPI.DeleteWorkloadByWlidWithContext(k8sAPI.Context, wlid)
+}
+
+// DeleteWorkloadByWlidWithContext works like DeleteWorkloadByWlid, but makes the API call with a given context
+func (k8sAPI *KubernetesApi) DeleteWorkloadByWlidWithContext(ctx context.Context, wlid string) error {
+	return k8sAPI.DeleteWorkloadByWlidWithOptions(ctx, wlid, DeleteOptions{})
+}
+
+// DeleteWorkloadByWlidWithOptions works like DeleteWorkloadByWlidWithContext, and deletes the workload with given options
+func (k8sAPI *KubernetesApi) DeleteWorkloadByWlidWithOptions(ctx context.Context, wlid string, options DeleteOptions) error {
+	groupVersionResource, err := k8sAPI.getGroupVersionResource(wlidpkg.GetKindFromWlid(wlid))
 	if err != nil {
 		return err
 	}
-	err = k8sAPI.ResourceInterface(&groupVersionResource, wlidpkg.GetNamespaceFromWlid(wlid)).Delete(k8sAPI.Context, wlidpkg.GetNameFromWlid(wlid), metav1.DeleteOptions{})
+	deleteOptions, err := options.toDeleteOptions(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to DELETE resource, workloadID: '%s', reason: %s", wlid, err.Error())
+	}
+	err = k8sAPI.ResourceInterface(&groupVersionResource, wlidpkg.GetNamespaceFromWlid(wlid)).Delete(ctx, wlidpkg.GetNameFromWlid(wlid), deleteOptions)
 	if err != nil {
 		return fmt.Errorf("failed to DELETE resource, workloadID: '%s', reason: %s", wlid, err.Error())
 	}
@@ -114,7 +154,12 @@ func (k8sAPI *KubernetesApi) DeleteWorkloadByWlid(wlid string) error {
 }
 
 func (k8sAPI *KubernetesApi) CreateWorkload(workload IWorkload) (IWorkload, error) {
-	groupVersionResource, err := GetGroupVersionResource(workload.GetKind())
+	return k8sAPI.CreateWorkloadWithContext(k8sAPI.Context, workload)
+}
+
+// CreateWorkloadWithContext works like CreateWorkload, but makes the API call with a given context
+func (k8sAPI *KubernetesApi) CreateWorkloadWithContext(ctx context.Context, workload IWorkload) (IWorkload, error) {
+	groupVersionResource, err := k8sAPI.getGroupVersionResource(workload.GetKind())
 	if err != nil {
 		return nil, err
 	}
@@ -122,7 +167,7 @@ func (k8sAPI *KubernetesApi) CreateWorkload(workload IWorkload) (IWorkload, erro
 	if err != nil {
 		return nil, err
 	}
-	w, err := k8sAPI.ResourceInterface(&groupVersionResource, workload.GetNamespace()).Create(k8sAPI.Context, obj, metav1.CreateOptions{})
+	w, err := k8sAPI.ResourceInterface(&groupVersionResource, workload.GetNamespace()).Create(ctx, obj, metav1.CreateOptions{DryRun: dryRunOptions(ctx)})
 	if err != nil {
 		return nil, fmt.Errorf("failed to CREATE resource, workload: '%s', reason: %s", workload.ToString(), err.Error())
 	}
@@ -130,7 +175,12 @@ func (k8sAPI *KubernetesApi) CreateWorkload(workload IWorkload) (IWorkload, erro
 }
 
 func (k8sAPI *KubernetesApi) UpdateWorkload(workload IWorkload) (IWorkload, error) {
-	groupVersionResource, err := GetGroupVersionResource(workload.GetKind())
+	return k8sAPI.UpdateWorkloadWithContext(k8sAPI.Context, workload)
+}
+
+// UpdateWorkloadWithContext works like UpdateWorkload, but makes the API call with a given context
+func (k8sAPI *KubernetesApi) UpdateWorkloadWithContext(ctx context.Context, workload IWorkload) (IWorkload, error) {
+	groupVersionResource, err := k8sAPI.getGroupVersionResource(workload.GetKind())
 	if err != nil {
 		return nil, err
 	}
@@ -140,7 +190,7 @@ func (k8sAPI *KubernetesApi) UpdateWorkload(workload IWorkload) (IWorkload, erro
 		return nil, err
 	}
 
-	w, err := k8sAPI.ResourceInterface(&groupVersionResource, workload.GetNamespace()).Update(k8sAPI.Context, obj, metav1.UpdateOptions{})
+	w, err := k8sAPI.ResourceInterface(&groupVersionResource, workload.GetNamespace()).Update(ctx, obj, metav1.UpdateOptions{DryRun: dryRunOptions(ctx)})
 	if err != nil {
 		return nil, fmt.Errorf("failed to UPDATE resource, workload: '%s', reason: %s", workload.ToString(), err.Error())
 	}
@@ -148,11 +198,16 @@ func (k8sAPI *KubernetesApi) UpdateWorkload(workload IWorkload) (IWorkload, erro
 }
 
 func (k8sAPI *KubernetesApi) GetNamespace(ns string) (IWorkload, error) {
-	groupVersionResource, err := GetGroupVersionResource("namespace")
+	return k8sAPI.GetNamespaceWithContext(k8sAPI.Context, ns)
+}
+
+// GetNamespaceWithContext works like GetNamespace, but makes the API call with a given context
+func (k8sAPI *KubernetesApi) GetNamespaceWithContext(ctx context.Context, ns string) (IWorkload, error) {
+	groupVersionResource, err := k8sAPI.getGroupVersionResource("namespace")
 	if err != nil {
 		return nil, err
 	}
-	w, err := k8sAPI.DynamicClient.Resource(groupVersionResource).Get(k8sAPI.Context, ns, metav1.GetOptions{})
+	w, err := k8sAPI.DynamicClient.Resource(groupVersionResource).Get(ctx, ns, metav1.GetOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get namespace: '%s', reason: %s", ns, err.Error())
 	}
@@ -168,6 +223,11 @@ func (k8sAPI *KubernetesApi) ResourceInterface(resource *schema.GroupVersionReso
 
 // CalculateWorkloadParentRecursive returns the parent of the workload kind and name
 func (k8sAPI *KubernetesApi) CalculateWorkloadParentRecursive(workload IWorkload) (string, string, error) {
+	return k8sAPI.CalculateWorkloadParentRecursiveWithContext(k8sAPI.Context, workload)
+}
+
+// CalculateWorkloadParentRecursiveWithContext works like CalculateWorkloadParentRecursive, but makes the API calls with a given context
+func (k8sAPI *KubernetesApi) CalculateWorkloadParentRecursiveWithContext(ctx context.Context, workload IWorkload) (string, string, error) {
 	if workload == nil {
 		return "", "", fmt.Errorf("workload is nil")
 	}
@@ -184,7 +244,7 @@ func (k8sAPI *KubernetesApi) CalculateWorkloadParentRecursive(workload IWorkload
 		podLabels := workload.GetLabels()
 
 		// Pod without owner, fallback to pod-template-hash label
-		replicas, err := k8sAPI.ListWorkloads(&schema.GroupVersionResource{
+		replicas, err := k8sAPI.ListWorkloadsWithContext(ctx, &schema.GroupVersionResource{
 			Group:    "apps",
 			Version:  "v1",
 			Resource: "replicasets",
@@ -206,14 +266,14 @@ func (k8sAPI *KubernetesApi) CalculateWorkloadParentRecursive(workload IWorkload
 		ownerName = ownerReference.Name
 	}
 
-	parentWorkload, err := k8sAPI.GetWorkload(workload.GetNamespace(), ownerKind, ownerName)
+	parentWorkload, err := k8sAPI.GetWorkloadWithContext(ctx, workload.GetNamespace(), ownerKind, ownerName)
 	if err != nil {
 		if strings.Contains(err.Error(), ResourceNotFoundErr) || strings.Contains(err.Error(), ResourceForbiddenErr) { // if parent is CRD
 			return workload.GetKind(), workload.GetName(), nil // parent found
 		}
 		return workload.GetKind(), workload.GetName(), err
 	}
-	return k8sAPI.CalculateWorkloadParentRecursive(parentWorkload)
+	return k8sAPI.CalculateWorkloadParentRecursiveWithContext(ctx, parentWorkload)
 }
 
 func WorkloadHasParent(workload IWorkload) bool {
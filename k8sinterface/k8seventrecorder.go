@@ -0,0 +1,58 @@
+package k8sinterface
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+// EventRecorder emits Kubernetes events about workloads, attributed to a source component
+//
+// Events are emitted asynchronously, rate limited and aggregated the way client-go does it: similar events are folded into a single event
+// counting their occurrences, and events about an object are dropped beyond a burst, refilled over time. Call Shutdown once done
+type EventRecorder struct {
+	broadcaster record.EventBroadcaster
+	recorder    record.EventRecorder
+}
+
+// NewEventRecorder returns an EventRecorder emitting events with the KubernetesApi, attributed to a given component,
+// rate limited and aggregated as set by given correlator options, whose zero fields are set to the client-go defaults
+func (k8sAPI *KubernetesApi) NewEventRecorder(component string, correlatorOptions record.CorrelatorOptions) *EventRecorder {
+	broadcaster := record.NewBroadcaster(record.WithCorrelatorOptions(correlatorOptions))
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: k8sAPI.KubernetesClient.CoreV1().Events("")})
+	return &EventRecorder{
+		broadcaster: broadcaster,
+		recorder:    broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: component}),
+	}
+}
+
+// Event emits an event of a given type, Normal or Warning, about a given workload
+func (r *EventRecorder) Event(workload IWorkload, eventType, reason, message string) error {
+	if eventType != corev1.EventTypeNormal && eventType != corev1.EventTypeWarning {
+		return fmt.Errorf("invalid event type: '%s', must be '%s' or '%s'", eventType, corev1.EventTypeNormal, corev1.EventTypeWarning)
+	}
+	obj, err := workload.ToUnstructured()
+	if err != nil {
+		return err
+	}
+	r.recorder.Event(obj, eventType, reason, message)
+	return nil
+}
+
+// Eventf works like Event, with a message formatted with fmt.Sprintf
+func (r *EventRecorder) Eventf(workload IWorkload, eventType, reason, messageFmt string, args ...interface{}) error {
+	return r.Event(workload, eventType, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+// Recorder returns the underlying client-go recorder, to emit events about typed objects
+func (r *EventRecorder) Recorder() record.EventRecorder {
+	return r.recorder
+}
+
+// Shutdown stops emitting events, dropping the ones not emitted yet
+func (r *EventRecorder) Shutdown() {
+	r.broadcaster.Shutdown()
+}
@@ -0,0 +1,50 @@
+package k8sinterface
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kubescape/k8s-interface/workloadinterface"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestEventRecorder(t *testing.T) {
+	k8sAPI := NewKubernetesApiMock()
+	recorder := k8sAPI.NewEventRecorder("kubescape", record.CorrelatorOptions{})
+	defer recorder.Shutdown()
+
+	pod := workloadinterface.NewWorkloadObj(map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata": map[string]interface{}{
+			"name":      "nginx",
+			"namespace": "default",
+			"uid":       "1234",
+		},
+	})
+
+	assert.Error(t, recorder.Event(pod, "Critical", "Scanned", "scan failed"))
+	assert.NoError(t, recorder.Eventf(pod, corev1.EventTypeWarning, "Vulnerable", "%d critical vulnerabilities", 3))
+
+	var events *corev1.EventList
+	assert.Eventually(t, func() bool {
+		var err error
+		events, err = k8sAPI.KubernetesClient.CoreV1().Events("default").List(context.Background(), metav1.ListOptions{})
+		return err == nil && len(events.Items) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	if len(events.Items) != 1 {
+		return
+	}
+
+	event := events.Items[0]
+	assert.Equal(t, corev1.EventTypeWarning, event.Type)
+	assert.Equal(t, "Vulnerable", event.Reason)
+	assert.Equal(t, "3 critical vulnerabilities", event.Message)
+	assert.Equal(t, "kubescape", event.Source.Component)
+	assert.Equal(t, "nginx", event.InvolvedObject.Name)
+	assert.Equal(t, "Pod", event.InvolvedObject.Kind)
+}
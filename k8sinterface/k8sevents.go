@@ -0,0 +1,165 @@
+package k8sinterface
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+// ObjectEvent is an event about an object, from either the core/v1 or the events.k8s.io/v1 API
+type ObjectEvent struct {
+	UID     string
+	Type    string
+	Reason  string
+	Message string
+	// Source is the component that reported the event
+	Source string
+	Count  int32
+	// FirstTimestamp and LastTimestamp are the first and last times the event was observed
+	FirstTimestamp time.Time
+	LastTimestamp  time.Time
+}
+
+// ObjectEvents are the events about an object, oldest first
+type ObjectEvents []ObjectEvent
+
+// Warnings returns the events of type Warning
+func (events ObjectEvents) Warnings() ObjectEvents {
+	return events.ofType(corev1.EventTypeWarning)
+}
+
+// Normal returns the events of type Normal
+func (events ObjectEvents) Normal() ObjectEvents {
+	return events.ofType(corev1.EventTypeNormal)
+}
+
+func (events ObjectEvents) ofType(eventType string) ObjectEvents {
+	filtered := ObjectEvents{}
+	for i := range events {
+		if events[i].Type == eventType {
+			filtered = append(filtered, events[i])
+		}
+	}
+	return filtered
+}
+
+// GetEventsForObject returns the events about a given workload from both the core/v1 and the events.k8s.io/v1 APIs,
+// deduplicated and sorted by the time they were last observed, oldest first
+//
+// Events are matched by the UID of the workload, or by its kind and name if either has no UID
+func (k8sAPI *KubernetesApi) GetEventsForObject(ctx context.Context, workload IWorkload) (ObjectEvents, error) {
+	namespace, kind, name, uid := workload.GetNamespace(), workload.GetKind(), workload.GetName(), workload.GetUID()
+	matches := func(objKind, objName, objUID string) bool {
+		if uid != "" && objUID != "" {
+			return uid == objUID
+		}
+		return kind == objKind && name == objName
+	}
+
+	coreEvents, err := k8sAPI.KubernetesClient.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("involvedObject.name", name).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to LIST events, kind: '%s', namespace: '%s', name: '%s', reason: %s", kind, namespace, name, err.Error())
+	}
+	events := ObjectEvents{}
+	seen := map[string]bool{}
+	for i := range coreEvents.Items {
+		event := &coreEvents.Items[i]
+		if !matches(event.InvolvedObject.Kind, event.InvolvedObject.Name, string(event.InvolvedObject.UID)) {
+			continue
+		}
+		seen[string(event.UID)] = true
+		events = append(events, objectEventFromCore(event))
+	}
+
+	// both APIs serve the same events, the events.k8s.io/v1 API only adds the ones missing from the core/v1 API
+	eventsV1, err := k8sAPI.KubernetesClient.EventsV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("regarding.name", name).String(),
+	})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to LIST events, kind: '%s', namespace: '%s', name: '%s', reason: %s", kind, namespace, name, err.Error())
+	}
+	if eventsV1 != nil {
+		for i := range eventsV1.Items {
+			event := &eventsV1.Items[i]
+			if seen[string(event.UID)] || !matches(event.Regarding.Kind, event.Regarding.Name, string(event.Regarding.UID)) {
+				continue
+			}
+			seen[string(event.UID)] = true
+			events = append(events, objectEventFromEventsV1(event))
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].LastTimestamp.Before(events[j].LastTimestamp)
+	})
+	return events, nil
+}
+
+func objectEventFromCore(event *corev1.Event) ObjectEvent {
+	source := event.Source.Component
+	if source == "" {
+		source = event.ReportingController
+	}
+	count := max(event.Count, 1)
+	lastTimestamp := firstNonZeroTime(event.LastTimestamp.Time, event.EventTime.Time, event.FirstTimestamp.Time, event.CreationTimestamp.Time)
+	if event.Series != nil {
+		count = event.Series.Count
+		lastTimestamp = event.Series.LastObservedTime.Time
+	}
+	return ObjectEvent{
+		UID:            string(event.UID),
+		Type:           event.Type,
+		Reason:         event.Reason,
+		Message:        event.Message,
+		Source:         source,
+		Count:          count,
+		FirstTimestamp: firstNonZeroTime(event.FirstTimestamp.Time, event.EventTime.Time, event.CreationTimestamp.Time),
+		LastTimestamp:  lastTimestamp,
+	}
+}
+
+func objectEventFromEventsV1(event *eventsv1.Event) ObjectEvent {
+	count := max(event.DeprecatedCount, 1)
+	lastTimestamp := firstNonZeroTime(event.DeprecatedLastTimestamp.Time, event.EventTime.Time, event.CreationTimestamp.Time)
+	if event.Series != nil {
+		count = event.Series.Count
+		lastTimestamp = event.Series.LastObservedTime.Time
+	}
+	return ObjectEvent{
+		UID:            string(event.UID),
+		Type:           event.Type,
+		Reason:         event.Reason,
+		Message:        event.Note,
+		Source:         firstNonEmpty(event.ReportingController, event.DeprecatedSource.Component),
+		Count:          count,
+		FirstTimestamp: firstNonZeroTime(event.DeprecatedFirstTimestamp.Time, event.EventTime.Time, event.CreationTimestamp.Time),
+		LastTimestamp:  lastTimestamp,
+	}
+}
+
+func firstNonZeroTime(times ...time.Time) time.Time {
+	for _, t := range times {
+		if !t.IsZero() {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
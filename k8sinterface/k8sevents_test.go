@@ -0,0 +1,126 @@
+package k8sinterface
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kubescape/k8s-interface/workloadinterface"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kubernetesfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetEventsForObject(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	nginx := corev1.ObjectReference{Kind: "Pod", Namespace: "default", Name: "nginx", UID: "1234"}
+	coreEvent := func(uid, eventType, reason string, involvedObject corev1.ObjectReference, lastTimestamp time.Time) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "nginx." + uid, Namespace: "default", UID: types.UID("event-" + uid)},
+			InvolvedObject: involvedObject,
+			Type:           eventType,
+			Reason:         reason,
+			Source:         corev1.EventSource{Component: "kubelet"},
+			Count:          2,
+			LastTimestamp:  metav1.NewTime(lastTimestamp),
+		}
+	}
+
+	k8sAPI := NewKubernetesApiMock()
+	k8sAPI.KubernetesClient = kubernetesfake.NewSimpleClientset(
+		coreEvent("a", corev1.EventTypeWarning, "BackOff", nginx, now.Add(time.Minute)),
+		coreEvent("b", corev1.EventTypeNormal, "Pulled", nginx, now),
+		// an event about a previous pod with the same name
+		coreEvent("c", corev1.EventTypeNormal, "Pulled", corev1.ObjectReference{Kind: "Pod", Namespace: "default", Name: "nginx", UID: "5678"}, now),
+		// the core/v1 event "a", as served by the events.k8s.io/v1 API
+		&eventsv1.Event{
+			ObjectMeta: metav1.ObjectMeta{Name: "nginx.a", Namespace: "default", UID: "event-a"},
+			Regarding:  nginx,
+			Type:       corev1.EventTypeWarning,
+			Reason:     "BackOff",
+		},
+		&eventsv1.Event{
+			ObjectMeta:          metav1.ObjectMeta{Name: "nginx.d", Namespace: "default", UID: "event-d"},
+			Regarding:           nginx,
+			Type:                corev1.EventTypeWarning,
+			Reason:              "Unhealthy",
+			Note:                "Readiness probe failed",
+			ReportingController: "kubelet",
+			Series:              &eventsv1.EventSeries{Count: 5, LastObservedTime: metav1.NewMicroTime(now.Add(2 * time.Minute))},
+		},
+	)
+
+	pod := workloadinterface.NewWorkloadObj(map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata": map[string]interface{}{
+			"name":      "nginx",
+			"namespace": "default",
+			"uid":       "1234",
+		},
+	})
+	events, err := k8sAPI.GetEventsForObject(context.Background(), pod)
+	assert.NoError(t, err)
+
+	reasons := make([]string, len(events))
+	for i := range events {
+		reasons[i] = events[i].Reason
+	}
+	assert.Equal(t, []string{"Pulled", "BackOff", "Unhealthy"}, reasons)
+	assert.Equal(t, ObjectEvent{
+		UID:           "event-d",
+		Type:          corev1.EventTypeWarning,
+		Reason:        "Unhealthy",
+		Message:       "Readiness probe failed",
+		Source:        "kubelet",
+		Count:         5,
+		LastTimestamp: now.Add(2 * time.Minute),
+	}, events[2])
+
+	assert.Len(t, events.Warnings(), 2)
+	assert.Len(t, events.Normal(), 1)
+	assert.Equal(t, "Pulled", events.Normal()[0].Reason)
+}
+
+func TestGetEventsForObjectSeries(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	nginx := corev1.ObjectReference{Kind: "Pod", Namespace: "default", Name: "nginx", UID: "1234"}
+
+	k8sAPI := NewKubernetesApiMock()
+	k8sAPI.KubernetesClient = kubernetesfake.NewSimpleClientset(
+		// a core/v1 event reported with the events.k8s.io/v1 API, which keeps its count and last time in the series
+		&corev1.Event{
+			ObjectMeta:          metav1.ObjectMeta{Name: "nginx.a", Namespace: "default", UID: "event-a"},
+			InvolvedObject:      nginx,
+			Type:                corev1.EventTypeWarning,
+			Reason:              "BackOff",
+			ReportingController: "kubelet",
+			EventTime:           metav1.NewMicroTime(now),
+			Series:              &corev1.EventSeries{Count: 7, LastObservedTime: metav1.NewMicroTime(now.Add(3 * time.Minute))},
+		},
+	)
+
+	pod := workloadinterface.NewWorkloadObj(map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata": map[string]interface{}{
+			"name":      "nginx",
+			"namespace": "default",
+			"uid":       "1234",
+		},
+	})
+	events, err := k8sAPI.GetEventsForObject(context.Background(), pod)
+	assert.NoError(t, err)
+	assert.Equal(t, ObjectEvents{{
+		UID:            "event-a",
+		Type:           corev1.EventTypeWarning,
+		Reason:         "BackOff",
+		Source:         "kubelet",
+		Count:          7,
+		FirstTimestamp: now,
+		LastTimestamp:  now.Add(3 * time.Minute),
+	}}, events)
+}
@@ -0,0 +1,77 @@
+package k8sinterface
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
+)
+
+// ExecInPod runs a command in a container of a pod, streaming the given stdin, stdout and stderr, any of which can be nil, and returns its exit code
+//
+// A command that runs and exits with a non-zero code is not an error: the error reports failures to run the command.
+// With tty set, stderr is merged into stdout. The command is stopped once the context is done, so a context with a timeout bounds its duration,
+// in which case the returned error wraps the error of the context
+func (k8sAPI *KubernetesApi) ExecInPod(ctx context.Context, namespace, podName, containerName string, command []string, stdin io.Reader, stdout, stderr io.Writer, tty bool) (int, error) {
+	if len(command) == 0 {
+		return 0, fmt.Errorf("failed to EXEC in pod, namespace: '%s', name: '%s', reason: command is empty", namespace, podName)
+	}
+	if k8sAPI.K8SConfig == nil {
+		return 0, fmt.Errorf("failed to EXEC in pod, namespace: '%s', name: '%s', reason: kubernetes config is not loaded", namespace, podName)
+	}
+
+	req := k8sAPI.KubernetesClient.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(podName).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: containerName,
+			Command:   command,
+			Stdin:     stdin != nil,
+			Stdout:    stdout != nil,
+			Stderr:    stderr != nil && !tty,
+			TTY:       tty,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(k8sAPI.K8SConfig, http.MethodPost, req.URL())
+	if err != nil {
+		return 0, fmt.Errorf("failed to EXEC in pod, namespace: '%s', name: '%s', reason: %s", namespace, podName, err.Error())
+	}
+	streamOptions := remotecommand.StreamOptions{
+		Stdin:  stdin,
+		Stdout: stdout,
+		Tty:    tty,
+	}
+	if !tty {
+		streamOptions.Stderr = stderr
+	}
+
+	exitCode, err := execExitCode(executor.StreamWithContext(ctx, streamOptions))
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, fmt.Errorf("failed to EXEC in pod, namespace: '%s', name: '%s', reason: %w", namespace, podName, ctx.Err())
+		}
+		return 0, fmt.Errorf("failed to EXEC in pod, namespace: '%s', name: '%s', reason: %s", namespace, podName, err.Error())
+	}
+	return exitCode, nil
+}
+
+// execExitCode returns the exit code of a command from the error of its stream, which is nil on success
+// and an exit error when the command exits with a non-zero code. Any other error is returned as is
+func execExitCode(err error) (int, error) {
+	if err == nil {
+		return 0, nil
+	}
+	var exitErr utilexec.ExitError
+	if errors.As(err, &exitErr) && exitErr.Exited() {
+		return exitErr.ExitStatus(), nil
+	}
+	return 0, err
+}
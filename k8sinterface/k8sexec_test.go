@@ -0,0 +1,63 @@
+package k8sinterface
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	utilexec "k8s.io/client-go/util/exec"
+)
+
+func TestExecInPod(t *testing.T) {
+	k8sAPI := NewKubernetesApiMock()
+
+	_, err := k8sAPI.ExecInPod(context.Background(), "default", "nginx", "nginx", nil, nil, nil, nil, false)
+	assert.ErrorContains(t, err, "command is empty")
+
+	_, err = k8sAPI.ExecInPod(context.Background(), "default", "nginx", "nginx", []string{"ls"}, nil, nil, nil, false)
+	assert.ErrorContains(t, err, "kubernetes config is not loaded")
+}
+
+func TestExecExitCode(t *testing.T) {
+	streamErr := errors.New("connection refused")
+	tests := []struct {
+		name         string
+		err          error
+		wantExitCode int
+		wantErr      error
+	}{
+		{
+			name:         "Success",
+			err:          nil,
+			wantExitCode: 0,
+		},
+		{
+			name:         "Non-zero exit code",
+			err:          utilexec.CodeExitError{Err: errors.New("command terminated with exit code 2"), Code: 2},
+			wantExitCode: 2,
+		},
+		{
+			name:         "Wrapped exit error",
+			err:          fmt.Errorf("stream: %w", utilexec.CodeExitError{Err: errors.New("command terminated with exit code 127"), Code: 127}),
+			wantExitCode: 127,
+		},
+		{
+			name:    "Stream failure",
+			err:     streamErr,
+			wantErr: streamErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exitCode, err := execExitCode(tt.err)
+			assert.Equal(t, tt.wantExitCode, exitCode)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package k8sinterface
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kubescape/k8s-interface/workloadinterface"
+	apiextensionsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	apiextensionsscheme "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/scheme"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/apimachinery/pkg/version"
+	discoveryfake "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubernetesfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	kubetesting "k8s.io/client-go/testing"
+)
+
+// FakeServerVersion is the version of the API server the KubernetesApi fakes report
+var FakeServerVersion = version.Info{Major: "1", Minor: "29", GitVersion: "v1.29.0"}
+
+// fakeVerbs are the verbs of the API resources the KubernetesApi fakes add for the kinds missing from the mock API resources
+var fakeVerbs = metav1.Verbs{"create", "delete", "deletecollection", "get", "list", "patch", "update", "watch"}
+
+// NewKubernetesApiFake returns a KubernetesApi whose clients are in-memory fakes holding given objects, to unit test code using the package
+// without a cluster. The objects of the built-in kinds are served by both the kubernetes and the dynamic clients, the CRDs by both the
+// api extensions and the dynamic clients, and the other ones by the dynamic client
+//
+// The API resources are the mock ones of InitializeMapResourcesMock, plus a resource guessed from the kind of the objects missing from them,
+// e.g. custom resources, namespaced if the object has a namespace. Like NewKubernetesApiMock, it initializes the resource mapping of the package.
+// The KubernetesApi has no rest config, so that the features talking to the API server directly, e.g. exec or port forwarding, are not supported
+func NewKubernetesApiFake(objects ...IWorkload) (*KubernetesApi, error) {
+	resourceLists, err := GetResourceListMock()
+	if err != nil {
+		return nil, err
+	}
+
+	type fakeObject struct {
+		obj       *unstructured.Unstructured
+		resource  schema.GroupVersionResource
+		namespace string
+	}
+	fakeObjects := make([]fakeObject, 0, len(objects))
+	for _, workload := range objects {
+		obj, err := workload.ToUnstructured()
+		if err != nil {
+			return nil, err
+		}
+		var resource schema.GroupVersionResource
+		var namespaced bool
+		resourceLists, resource, namespaced = fakeResourceFor(resourceLists, obj.GroupVersionKind(), obj.GetNamespace() != "")
+		// the fake clients keep the objects of the cluster-scoped resources out of the namespaces
+		obj = obj.DeepCopy()
+		if !namespaced {
+			obj.SetNamespace("")
+		}
+		fakeObjects = append(fakeObjects, fakeObject{obj: obj, resource: resource, namespace: obj.GetNamespace()})
+	}
+
+	// the dynamic client lists the resources whose list kind it knows only
+	listKinds := map[schema.GroupVersionResource]string{}
+	for _, resourceList := range resourceLists {
+		groupVersion, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, apiResource := range resourceList.APIResources {
+			if !strings.Contains(apiResource.Name, "/") {
+				listKinds[groupVersion.WithResource(apiResource.Name)] = apiResource.Kind + "List"
+			}
+		}
+	}
+
+	kubernetesClient := kubernetesfake.NewSimpleClientset()
+	apiExtensionsClient := apiextensionsfake.NewSimpleClientset()
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds)
+	typedClients := []struct {
+		typedScheme *runtime.Scheme
+		tracker     kubetesting.ObjectTracker
+	}{
+		{typedScheme: scheme.Scheme, tracker: kubernetesClient.Tracker()},
+		{typedScheme: apiextensionsscheme.Scheme, tracker: apiExtensionsClient.Tracker()},
+	}
+	for _, fake := range fakeObjects {
+		if err := dynamicClient.Tracker().Create(fake.resource, fake.obj, fake.namespace); err != nil {
+			return nil, fmt.Errorf("failed to add object to the fake clients, kind: '%s', namespace: '%s', name: '%s', reason: %w", fake.obj.GetKind(), fake.namespace, fake.obj.GetName(), err)
+		}
+		for _, typedClient := range typedClients {
+			if !typedClient.typedScheme.Recognizes(fake.obj.GroupVersionKind()) {
+				continue
+			}
+			typedObj, err := typedClient.typedScheme.New(fake.obj.GroupVersionKind())
+			if err == nil {
+				err = runtime.DefaultUnstructuredConverter.FromUnstructured(fake.obj.Object, typedObj)
+			}
+			if err == nil {
+				err = typedClient.tracker.Create(fake.resource, typedObj, fake.namespace)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to add object to the fake clients, kind: '%s', namespace: '%s', name: '%s', reason: %w", fake.obj.GetKind(), fake.namespace, fake.obj.GetName(), err)
+			}
+		}
+	}
+
+	setMapResources(resourceLists)
+	serverVersion := FakeServerVersion
+	return &KubernetesApi{
+		ApiExtensionsClient: apiExtensionsClient,
+		KubernetesClient:    kubernetesClient,
+		DynamicClient:       dynamicClient,
+		DiscoveryClient:     &discoveryfake.FakeDiscovery{Fake: &kubetesting.Fake{Resources: resourceLists}, FakedServerVersion: &serverVersion},
+		Context:             context.Background(),
+	}, nil
+}
+
+// NewKubernetesApiFakeFromYAML works like NewKubernetesApiFake, with the objects of given YAML or JSON fixtures, e.g. read from testdata files.
+// A fixture holds one or more documents separated by "---", and the items of the lists, e.g. of kind List, are added one by one
+func NewKubernetesApiFakeFromYAML(fixtures ...[]byte) (*KubernetesApi, error) {
+	objects := []IWorkload{}
+	for i := range fixtures {
+		fixtureObjects, err := decodeFixture(fixtures[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode fixture %d, reason: %w", i, err)
+		}
+		objects = append(objects, fixtureObjects...)
+	}
+	return NewKubernetesApiFake(objects...)
+}
+
+// decodeFixture returns the objects of the documents of a YAML or JSON fixture, skipping the empty documents
+func decodeFixture(fixture []byte) ([]IWorkload, error) {
+	objects := []IWorkload{}
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(fixture), 4096)
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				return objects, nil
+			}
+			return nil, err
+		}
+		if len(obj.Object) == 0 {
+			continue
+		}
+		if !obj.IsList() {
+			if err := validateFixtureObject(obj); err != nil {
+				return nil, err
+			}
+			objects = append(objects, workloadinterface.NewWorkloadObj(obj.Object))
+			continue
+		}
+		if err := obj.EachListItem(func(item runtime.Object) error {
+			itemObj := item.(*unstructured.Unstructured)
+			if err := validateFixtureObject(itemObj); err != nil {
+				return err
+			}
+			objects = append(objects, workloadinterface.NewWorkloadObj(itemObj.Object))
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// validateFixtureObject returns an error if an object of a fixture has no kind, apiVersion or name
+func validateFixtureObject(obj *unstructured.Unstructured) error {
+	if obj.GetKind() == "" || obj.GetAPIVersion() == "" || obj.GetName() == "" {
+		return fmt.Errorf("object has no kind, apiVersion or name, kind: '%s', apiVersion: '%s', name: '%s'", obj.GetKind(), obj.GetAPIVersion(), obj.GetName())
+	}
+	return nil
+}
+
+// fakeResourceFor returns the resource of a given kind in given API resources and whether it is namespaced,
+// adding a resource guessed from the kind if missing, namespaced or not as given
+func fakeResourceFor(resourceLists []*metav1.APIResourceList, gvk schema.GroupVersionKind, namespaced bool) ([]*metav1.APIResourceList, schema.GroupVersionResource, bool) {
+	groupVersion := gvk.GroupVersion().String()
+	var groupVersionResources *metav1.APIResourceList
+	for _, resourceList := range resourceLists {
+		if resourceList.GroupVersion != groupVersion {
+			continue
+		}
+		groupVersionResources = resourceList
+		for _, apiResource := range resourceList.APIResources {
+			if apiResource.Kind == gvk.Kind && !strings.Contains(apiResource.Name, "/") {
+				return resourceLists, gvk.GroupVersion().WithResource(apiResource.Name), apiResource.Namespaced
+			}
+		}
+	}
+
+	resource, _ := meta.UnsafeGuessKindToResource(gvk)
+	if groupVersionResources == nil {
+		groupVersionResources = &metav1.APIResourceList{GroupVersion: groupVersion}
+		resourceLists = append(resourceLists, groupVersionResources)
+	}
+	groupVersionResources.APIResources = append(groupVersionResources.APIResources, metav1.APIResource{
+		Name:       resource.Resource,
+		Kind:       gvk.Kind,
+		Namespaced: namespaced,
+		Verbs:      fakeVerbs,
+	})
+	return resourceLists, resource, namespaced
+}
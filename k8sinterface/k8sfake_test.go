@@ -0,0 +1,163 @@
+package k8sinterface
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kubescape/k8s-interface/workloadinterface"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const fakeFixture = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+  namespace: default
+spec:
+  replicas: 2
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: default
+---
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: Pod
+  metadata:
+    name: nginx-1
+    namespace: default
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: settings
+    namespace: default
+---
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: knob
+  namespace: default
+`
+
+func TestNewKubernetesApiFakeFromYAML(t *testing.T) {
+	defer tearDown()
+
+	k8sAPI, err := NewKubernetesApiFakeFromYAML([]byte(fakeFixture))
+	assert.NoError(t, err)
+	var _ IKubernetesApi = k8sAPI
+
+	// the typed clients
+	deployment, err := k8sAPI.KubernetesClient.AppsV1().Deployments("default").Get(context.Background(), "nginx", metav1.GetOptions{})
+	if assert.NoError(t, err) {
+		assert.Equal(t, int32(2), *deployment.Spec.Replicas)
+	}
+	_, err = k8sAPI.KubernetesClient.CoreV1().Namespaces().Get(context.Background(), "default", metav1.GetOptions{})
+	assert.NoError(t, err)
+
+	// the dynamic client
+	pods, err := k8sAPI.DynamicClient.Resource(podsResource).Namespace("default").List(context.Background(), metav1.ListOptions{})
+	if assert.NoError(t, err) && assert.Len(t, pods.Items, 1) {
+		assert.Equal(t, "nginx-1", pods.Items[0].GetName())
+	}
+	widgets, err := k8sAPI.DynamicClient.Resource(schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}).Namespace("default").List(context.Background(), metav1.ListOptions{})
+	if assert.NoError(t, err) {
+		assert.Len(t, widgets.Items, 1)
+	}
+
+	// the workloads
+	workload, err := k8sAPI.GetWorkload("default", "ConfigMap", "settings")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "settings", workload.GetName())
+	}
+	workloads, err := k8sAPI.ListWorkloads2("default", "Widget")
+	if assert.NoError(t, err) && assert.Len(t, workloads, 1) {
+		assert.Equal(t, "knob", workloads[0].GetName())
+	}
+
+	// the discovery
+	supported, err := k8sAPI.SupportsAPI(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"})
+	assert.NoError(t, err)
+	assert.True(t, supported)
+	serverVersion, err := k8sAPI.GetServerVersion()
+	if assert.NoError(t, err) {
+		assert.Equal(t, "1.29.0", serverVersion.String())
+	}
+}
+
+func TestNewKubernetesApiFake(t *testing.T) {
+	defer tearDown()
+
+	// a namespace set on a cluster-scoped object is ignored
+	node := workloadinterface.NewWorkloadObj(map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Node",
+		"metadata":   map[string]interface{}{"name": "worker", "namespace": "default"},
+	})
+	k8sAPI, err := NewKubernetesApiFake(node)
+	assert.NoError(t, err)
+	assert.Equal(t, "default", node.GetNamespace())
+
+	_, err = k8sAPI.KubernetesClient.CoreV1().Nodes().Get(context.Background(), "worker", metav1.GetOptions{})
+	assert.NoError(t, err)
+
+	// the same object twice
+	_, err = NewKubernetesApiFake(node, node)
+	assert.Error(t, err)
+}
+
+func TestDecodeFixture(t *testing.T) {
+	tests := []struct {
+		name    string
+		fixture string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "JSON",
+			fixture: `{"apiVersion":"v1","kind":"Pod","metadata":{"name":"nginx"}}`,
+			want:    []string{"nginx"},
+		},
+		{
+			name:    "Empty",
+			fixture: "---\n---\n",
+			want:    []string{},
+		},
+		{
+			name:    "Invalid YAML",
+			fixture: "kind: [Pod",
+			wantErr: true,
+		},
+		{
+			name:    "Missing kind",
+			fixture: "apiVersion: v1\nmetadata:\n  name: nginx\n",
+			wantErr: true,
+		},
+		{
+			name:    "List item missing name",
+			fixture: "apiVersion: v1\nkind: List\nitems:\n- apiVersion: v1\n  kind: Pod\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects, err := decodeFixture([]byte(tt.fixture))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			names := []string{}
+			for _, obj := range objects {
+				names = append(names, obj.GetName())
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package k8sinterface
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// Leader election timings, the ones client-go based controllers use by default
+var (
+	// LeaderElectionLeaseDuration is how long non-leaders wait before taking over a lease which was not renewed
+	LeaderElectionLeaseDuration = 15 * time.Second
+	// LeaderElectionRenewDeadline is how long the leader retries renewing its lease before giving up leadership
+	LeaderElectionRenewDeadline = 10 * time.Second
+	// LeaderElectionRetryPeriod is how long to wait between attempts to acquire or renew the lease
+	LeaderElectionRetryPeriod = 2 * time.Second
+)
+
+// RunWithLeaderElection runs for leadership on a Lease lock of a given name and namespace, under a given identity, until the context is done
+//
+// The callbacks are called when leadership is acquired or lost, OnStartedLeading with a context canceled once it is lost.
+// Leadership is lost for good: to run again, call RunWithLeaderElection again. The lease is released when the context is done,
+// so another instance can take over right away
+func (k8sAPI *KubernetesApi) RunWithLeaderElection(ctx context.Context, lockName, lockNamespace, identity string, callbacks leaderelection.LeaderCallbacks) error {
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Name:      lockName,
+			Namespace: lockNamespace,
+		},
+		Client: k8sAPI.KubernetesClient.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity: identity,
+		},
+	}
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		LeaseDuration:   LeaderElectionLeaseDuration,
+		RenewDeadline:   LeaderElectionRenewDeadline,
+		RetryPeriod:     LeaderElectionRetryPeriod,
+		Callbacks:       callbacks,
+		ReleaseOnCancel: true,
+		Name:            lockName,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to run leader election, namespace: '%s', name: '%s', reason: %s", lockNamespace, lockName, err.Error())
+	}
+	elector.Run(ctx)
+	return nil
+}
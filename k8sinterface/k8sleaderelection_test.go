@@ -0,0 +1,64 @@
+package k8sinterface
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/leaderelection"
+)
+
+func TestRunWithLeaderElection(t *testing.T) {
+	k8sAPI := NewKubernetesApiMock()
+
+	t.Run("missing callbacks", func(t *testing.T) {
+		err := k8sAPI.RunWithLeaderElection(context.Background(), "kubescape", "kubescape", "node-1", leaderelection.LeaderCallbacks{})
+		assert.Error(t, err)
+	})
+
+	t.Run("missing identity", func(t *testing.T) {
+		err := k8sAPI.RunWithLeaderElection(context.Background(), "kubescape", "kubescape", "", leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(context.Context) {},
+			OnStoppedLeading: func() {},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("leads until the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		// the callbacks other than OnStoppedLeading run in their own goroutines
+		started := make(chan struct{})
+		stopped := false
+		newLeader := make(chan string, 1)
+		err := k8sAPI.RunWithLeaderElection(ctx, "kubescape", "kubescape", "node-1", leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(context.Context) {
+				close(started)
+				cancel()
+			},
+			OnStoppedLeading: func() {
+				stopped = true
+			},
+			OnNewLeader: func(identity string) {
+				select {
+				case newLeader <- identity:
+				default:
+				}
+			},
+		})
+		assert.NoError(t, err)
+		<-started
+		assert.True(t, stopped)
+		assert.Equal(t, "node-1", <-newLeader)
+
+		// the lease is released once the context is done
+		lease, err := k8sAPI.KubernetesClient.CoordinationV1().Leases("kubescape").Get(context.Background(), "kubescape", metav1.GetOptions{})
+		assert.NoError(t, err)
+		if assert.NotNil(t, lease.Spec.HolderIdentity) {
+			assert.Empty(t, *lease.Spec.HolderIdentity)
+		}
+	})
+}
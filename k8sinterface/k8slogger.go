@@ -0,0 +1,112 @@
+package k8sinterface
+
+import (
+	"fmt"
+
+	"github.com/go-logr/logr"
+	logger "github.com/kubescape/go-logger"
+	"github.com/kubescape/go-logger/helpers"
+)
+
+// Logger receives the diagnostics of the package, e.g. the retries of requests and the reconnections of watches,
+// as a message with alternating keys and values
+type Logger interface {
+	Debug(msg string, keysAndValues ...interface{})
+	Info(msg string, keysAndValues ...interface{})
+	Warning(msg string, keysAndValues ...interface{})
+	Error(msg string, err error, keysAndValues ...interface{})
+}
+
+var packageLogger Logger = goLogger{}
+
+// SetLogger sets the package-level logger, used by the KubernetesApi instances whose client config sets none and by the cloud support.
+// A nil logger restores the default one, logging with github.com/kubescape/go-logger
+func SetLogger(l Logger) {
+	if l == nil {
+		l = goLogger{}
+	}
+	packageLogger = l
+}
+
+// GetLogger returns the package-level logger
+func GetLogger() Logger {
+	return packageLogger
+}
+
+// loggerOrDefault returns a given logger, or the package-level one if nil
+func loggerOrDefault(l Logger) Logger {
+	if l == nil {
+		return GetLogger()
+	}
+	return l
+}
+
+// GetLogger returns the logger of the KubernetesApi, the package-level one if its client config sets none
+func (k8sAPI *KubernetesApi) GetLogger() Logger {
+	return loggerOrDefault(k8sAPI.logger)
+}
+
+// goLogger logs with github.com/kubescape/go-logger
+type goLogger struct{}
+
+func (goLogger) Debug(msg string, keysAndValues ...interface{}) {
+	logger.L().Debug(msg, goLoggerDetails(keysAndValues)...)
+}
+
+func (goLogger) Info(msg string, keysAndValues ...interface{}) {
+	logger.L().Info(msg, goLoggerDetails(keysAndValues)...)
+}
+
+func (goLogger) Warning(msg string, keysAndValues ...interface{}) {
+	logger.L().Warning(msg, goLoggerDetails(keysAndValues)...)
+}
+
+func (goLogger) Error(msg string, err error, keysAndValues ...interface{}) {
+	logger.L().Error(msg, append(goLoggerDetails(keysAndValues), helpers.Error(err))...)
+}
+
+// goLoggerDetails converts alternating keys and values into go-logger details, a key without a value has a nil one
+func goLoggerDetails(keysAndValues []interface{}) []helpers.IDetails {
+	details := make([]helpers.IDetails, 0, (len(keysAndValues)+1)/2)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		key := fmt.Sprint(keysAndValues[i])
+		var value interface{}
+		if i+1 < len(keysAndValues) {
+			value = keysAndValues[i+1]
+		}
+		if err, ok := value.(error); ok && key == "error" {
+			details = append(details, helpers.Error(err))
+			continue
+		}
+		details = append(details, helpers.Interface(key, value))
+	}
+	return details
+}
+
+// logrLogger logs with a logr.Logger
+type logrLogger struct {
+	logr logr.Logger
+}
+
+// NewLogrLogger returns a Logger logging with a given logr.Logger, e.g. to route the diagnostics of the package to zap or slog.
+// Debug messages are logged at verbosity 1, info messages and warnings at verbosity 0
+func NewLogrLogger(l logr.Logger) Logger {
+	// skip the frame of the logrLogger, so that the caller is reported
+	return &logrLogger{logr: l.WithCallDepth(1)}
+}
+
+func (l *logrLogger) Debug(msg string, keysAndValues ...interface{}) {
+	l.logr.V(1).Info(msg, keysAndValues...)
+}
+
+func (l *logrLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.logr.Info(msg, keysAndValues...)
+}
+
+func (l *logrLogger) Warning(msg string, keysAndValues ...interface{}) {
+	l.logr.Info(msg, keysAndValues...)
+}
+
+func (l *logrLogger) Error(msg string, err error, keysAndValues ...interface{}) {
+	l.logr.Error(err, msg, keysAndValues...)
+}
@@ -0,0 +1,145 @@
+package k8sinterface
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr/funcr"
+	"github.com/kubescape/go-logger/helpers"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	restclient "k8s.io/client-go/rest"
+)
+
+// recordingLogger records the messages logged, prefixed by their level
+type recordingLogger struct {
+	lock     sync.Mutex
+	messages []string
+}
+
+func (l *recordingLogger) record(level, msg string) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.messages = append(l.messages, level+": "+msg)
+}
+
+func (l *recordingLogger) Debug(msg string, keysAndValues ...interface{})   { l.record("debug", msg) }
+func (l *recordingLogger) Info(msg string, keysAndValues ...interface{})    { l.record("info", msg) }
+func (l *recordingLogger) Warning(msg string, keysAndValues ...interface{}) { l.record("warning", msg) }
+func (l *recordingLogger) Error(msg string, err error, keysAndValues ...interface{}) {
+	l.record("error", msg)
+}
+
+func (l *recordingLogger) recorded() []string {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return append([]string{}, l.messages...)
+}
+
+func TestSetLogger(t *testing.T) {
+	defer SetLogger(nil)
+
+	assert.Equal(t, goLogger{}, GetLogger())
+
+	packageLogger := &recordingLogger{}
+	SetLogger(packageLogger)
+	assert.Same(t, packageLogger, GetLogger())
+	assert.Same(t, packageLogger, NewKubernetesApiMock().GetLogger())
+
+	// the logger of the client config takes precedence
+	clientLogger := &recordingLogger{}
+	k8sAPI, err := NewKubernetesApiForConfig(&restclient.Config{Host: "https://127.0.0.1:6443"}, ClientConfig{Logger: clientLogger})
+	assert.NoError(t, err)
+	assert.Same(t, clientLogger, k8sAPI.GetLogger())
+	assert.Empty(t, packageLogger.recorded())
+
+	SetLogger(nil)
+	assert.Equal(t, goLogger{}, GetLogger())
+}
+
+func TestGoLoggerDetails(t *testing.T) {
+	err := errors.New("connection refused")
+	tests := []struct {
+		name          string
+		keysAndValues []interface{}
+		want          []helpers.IDetails
+	}{
+		{
+			name:          "Pairs",
+			keysAndValues: []interface{}{"host", "https://127.0.0.1:6443", "qps", float32(50)},
+			want:          []helpers.IDetails{helpers.Interface("host", "https://127.0.0.1:6443"), helpers.Interface("qps", float32(50))},
+		},
+		{
+			name:          "Error",
+			keysAndValues: []interface{}{"error", err},
+			want:          []helpers.IDetails{helpers.Error(err)},
+		},
+		{
+			name:          "Key without a value",
+			keysAndValues: []interface{}{"context"},
+			want:          []helpers.IDetails{helpers.Interface("context", nil)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, goLoggerDetails(tt.keysAndValues))
+		})
+	}
+}
+
+func TestLogrLogger(t *testing.T) {
+	lines := []string{}
+	sink := funcr.New(func(prefix, args string) { lines = append(lines, args) }, funcr.Options{})
+	l := NewLogrLogger(sink)
+
+	l.Debug("retrying request to the API server", "attempt", 1)
+	l.Info("loaded kubernetes config", "inCluster", true)
+	l.Warning("failed to watch resources again", "resource", "v1/pods")
+	l.Error("unable to get secret", errors.New("forbidden"), "secret name", "registry")
+
+	// debug messages are not logged at verbosity 0
+	if assert.Len(t, lines, 3) {
+		assert.Contains(t, lines[0], `"msg"="loaded kubernetes config" "inCluster"=true`)
+		assert.Contains(t, lines[1], `"msg"="failed to watch resources again" "resource"="v1/pods"`)
+		assert.Contains(t, lines[2], `"msg"="unable to get secret" "error"="forbidden" "secret name"="registry"`)
+	}
+
+	lines = lines[:0]
+	NewLogrLogger(funcr.New(func(prefix, args string) { lines = append(lines, args) }, funcr.Options{Verbosity: 1})).Debug("retrying request to the API server", "attempt", 1)
+	if assert.Len(t, lines, 1) {
+		assert.Contains(t, lines[0], `"level"=1 "msg"="retrying request to the API server" "attempt"=1`)
+	}
+}
+
+func TestClientLogger(t *testing.T) {
+	defer tearDown()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", runtime.ContentTypeJSON)
+		w.Write([]byte(`{"kind":"PodList","apiVersion":"v1","items":[]}`))
+	}))
+	defer server.Close()
+
+	clientLogger := &recordingLogger{}
+	k8sAPI, err := NewKubernetesApiForConfig(&restclient.Config{Host: server.URL}, ClientConfig{
+		Logger: clientLogger,
+		Retry:  &RetryConfig{InitialBackoff: time.Millisecond, Budget: NewRetryBudget(10, 0)},
+	})
+	assert.NoError(t, err)
+	_, err = k8sAPI.KubernetesClient.CoreV1().Pods("").List(context.Background(), metav1.ListOptions{})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"debug: connecting to the API server", "debug: retrying request to the API server"}, clientLogger.recorded())
+}
@@ -0,0 +1,130 @@
+package k8sinterface
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maxLogLineSize is the longest log line StreamPodLogs reads, longer lines fail the stream of their container
+const maxLogLineSize = 1024 * 1024
+
+// PodLogOptions selects the logs StreamPodLogs streams
+type PodLogOptions struct {
+	// Container is the container to stream the logs of, which can be empty for pods with a single container
+	Container string
+	// AllContainers streams the logs of all the containers of the pod, init containers included, in which case Container is ignored
+	AllContainers bool
+	// Follow keeps streaming new logs until the container stops or the context is done
+	Follow bool
+	// SinceTime only streams the logs written after a given time, if set
+	SinceTime *time.Time
+	// TailLines only streams a given number of lines from the end of the logs, if set
+	TailLines *int64
+	// Previous streams the logs of the previous run of the container
+	Previous bool
+}
+
+// PodLogLine is a line of the logs of a container
+type PodLogLine struct {
+	Container string
+	Line      string
+	// Err is set on the last line of a container whose logs failed to be opened or read, in which case Line is empty
+	Err error
+}
+
+// StreamPodLogs streams the logs of a pod line by line, tagged by container, until all the streams end or the context is done
+//
+// Lines of a container are in order, lines of different containers are interleaved as they are read.
+// The streams are opened before it returns, and the channel is closed once all of them end. A failure to open the stream of a single container
+// is returned as an error, while with AllContainers it is sent as the only line of its container and the other containers are streamed
+func (k8sAPI *KubernetesApi) StreamPodLogs(ctx context.Context, namespace, podName string, opts PodLogOptions) (<-chan PodLogLine, error) {
+	containers := []string{opts.Container}
+	if opts.AllContainers {
+		pod, err := k8sAPI.KubernetesClient.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to GET pod, namespace: '%s', name: '%s', reason: %s", namespace, podName, err.Error())
+		}
+		containers = make([]string, 0, len(pod.Spec.InitContainers)+len(pod.Spec.Containers))
+		for _, container := range pod.Spec.InitContainers {
+			containers = append(containers, container.Name)
+		}
+		for _, container := range pod.Spec.Containers {
+			containers = append(containers, container.Name)
+		}
+	}
+
+	streams := make([]io.ReadCloser, len(containers))
+	openErrs := make([]error, len(containers))
+	for i, container := range containers {
+		logOptions := &corev1.PodLogOptions{
+			Container: container,
+			Follow:    opts.Follow,
+			Previous:  opts.Previous,
+			TailLines: opts.TailLines,
+		}
+		if opts.SinceTime != nil {
+			sinceTime := metav1.NewTime(*opts.SinceTime)
+			logOptions.SinceTime = &sinceTime
+		}
+		stream, err := k8sAPI.KubernetesClient.CoreV1().Pods(namespace).GetLogs(podName, logOptions).Stream(ctx)
+		if err != nil {
+			err = fmt.Errorf("failed to GET logs, namespace: '%s', name: '%s', container: '%s', reason: %s", namespace, podName, container, err.Error())
+			if !opts.AllContainers {
+				return nil, err
+			}
+			openErrs[i] = err
+			continue
+		}
+		streams[i] = stream
+	}
+
+	lines := make(chan PodLogLine)
+	var wg sync.WaitGroup
+	for i := range containers {
+		wg.Add(1)
+		go func(container string, stream io.ReadCloser, openErr error) {
+			defer wg.Done()
+			if openErr != nil {
+				select {
+				case lines <- PodLogLine{Container: container, Err: openErr}:
+				case <-ctx.Done():
+				}
+				return
+			}
+			defer stream.Close()
+			scanLogLines(ctx, container, stream, lines)
+		}(containers[i], streams[i], openErrs[i])
+	}
+	go func() {
+		wg.Wait()
+		close(lines)
+	}()
+	return lines, nil
+}
+
+// scanLogLines sends the lines of a log stream to a channel until the stream ends or the context is done
+func scanLogLines(ctx context.Context, container string, stream io.Reader, lines chan<- PodLogLine) {
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLogLineSize)
+	for scanner.Scan() {
+		select {
+		case lines <- PodLogLine{Container: container, Line: scanner.Text()}:
+		case <-ctx.Done():
+			return
+		}
+	}
+	// a stream cut by the context being done is not a failure
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		select {
+		case lines <- PodLogLine{Container: container, Err: err}:
+		case <-ctx.Done():
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package k8sinterface
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	kubernetesfake "k8s.io/client-go/kubernetes/fake"
+	restclient "k8s.io/client-go/rest"
+)
+
+func TestStreamPodLogs(t *testing.T) {
+	k8sAPI := NewKubernetesApiMock()
+	k8sAPI.KubernetesClient = kubernetesfake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "default"},
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "init"}},
+			Containers:     []corev1.Container{{Name: "nginx"}, {Name: "sidecar"}},
+		},
+	})
+
+	tests := []struct {
+		name    string
+		podName string
+		opts    PodLogOptions
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "Single container",
+			podName: "nginx",
+			opts:    PodLogOptions{Container: "nginx"},
+			want:    []string{"nginx: fake logs"},
+		},
+		{
+			name:    "All containers are tagged",
+			podName: "nginx",
+			opts:    PodLogOptions{AllContainers: true},
+			want:    []string{"init: fake logs", "nginx: fake logs", "sidecar: fake logs"},
+		},
+		{
+			name:    "Missing pod",
+			podName: "redis",
+			opts:    PodLogOptions{AllContainers: true},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, err := k8sAPI.StreamPodLogs(context.Background(), "default", tt.podName, tt.opts)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			var got []string
+			for line := range lines {
+				assert.NoError(t, line.Err)
+				got = append(got, line.Container+": "+line.Line)
+			}
+			assert.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func TestStreamPodLogsContainerFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if !strings.HasSuffix(r.URL.Path, "/log") {
+			w.Write([]byte(`{"kind":"Pod","apiVersion":"v1","metadata":{"name":"nginx","namespace":"default"},"spec":{"containers":[{"name":"nginx"},{"name":"sidecar"}]}}`))
+			return
+		}
+		container := r.URL.Query().Get("container")
+		if container == "sidecar" {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","code":500}`))
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(container + " logs"))
+	}))
+	defer server.Close()
+
+	k8sAPI := NewKubernetesApiMock()
+	var err error
+	k8sAPI.KubernetesClient, err = kubernetes.NewForConfig(&restclient.Config{Host: server.URL})
+	assert.NoError(t, err)
+
+	// a single container fails the call
+	_, err = k8sAPI.StreamPodLogs(context.Background(), "default", "nginx", PodLogOptions{Container: "sidecar"})
+	assert.Error(t, err)
+
+	// a failing container does not stop the others from being streamed
+	lines, err := k8sAPI.StreamPodLogs(context.Background(), "default", "nginx", PodLogOptions{AllContainers: true})
+	assert.NoError(t, err)
+	var got []string
+	failed := map[string]bool{}
+	for line := range lines {
+		if line.Err != nil {
+			failed[line.Container] = true
+			continue
+		}
+		got = append(got, line.Container+": "+line.Line)
+	}
+	assert.Equal(t, []string{"nginx: nginx logs"}, got)
+	assert.Equal(t, map[string]bool{"sidecar": true}, failed)
+}
+
+func TestScanLogLines(t *testing.T) {
+	lines := make(chan PodLogLine, 10)
+	scanLogLines(context.Background(), "nginx", strings.NewReader("first\nsecond\n"+strings.Repeat("x", maxLogLineSize+1)), lines)
+	close(lines)
+
+	var got []PodLogLine
+	for line := range lines {
+		got = append(got, line)
+	}
+	assert.Len(t, got, 3)
+	assert.Equal(t, PodLogLine{Container: "nginx", Line: "first"}, got[0])
+	assert.Equal(t, PodLogLine{Container: "nginx", Line: "second"}, got[1])
+	assert.Error(t, got[2].Err)
+
+	// nothing is sent once the context is done
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	blocked := make(chan PodLogLine)
+	scanLogLines(ctx, "nginx", strings.NewReader("first\n"), blocked)
+}
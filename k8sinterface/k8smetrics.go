@@ -0,0 +1,201 @@
+package k8sinterface
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+const (
+	metricsNamespace = "k8s_interface"
+	metricsSubsystem = "client"
+)
+
+// latencyBuckets are the buckets of the request latencies and throttle wait times, in seconds
+var latencyBuckets = []float64{0.005, 0.025, 0.1, 0.25, 0.5, 1, 2, 4, 8, 15, 30, 60}
+
+// clientMetrics are the Prometheus collectors of the requests of a KubernetesApi to the API server. Its methods are no-ops on a nil clientMetrics,
+// so that the metrics are only collected if a registerer is set in the client config
+type clientMetrics struct {
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	throttleWait    prometheus.Histogram
+	watchReconnects *prometheus.CounterVec
+	cacheRequests   *prometheus.CounterVec
+}
+
+// newClientMetrics registers the collectors of the client metrics with a given registerer, nil if the registerer is nil
+//
+// The KubernetesApi instances sharing a registerer share the collectors, the ones already registered are reused
+func newClientMetrics(registerer prometheus.Registerer) (*clientMetrics, error) {
+	if registerer == nil {
+		return nil, nil
+	}
+	metrics := &clientMetrics{}
+	var err error
+	if metrics.requests, err = registerCollector(registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "requests_total",
+		Help:      "Number of requests to the API server, by verb, resource and response code, 'error' if no response was received",
+	}, []string{"verb", "group", "resource", "code"})); err != nil {
+		return nil, err
+	}
+	if metrics.requestDuration, err = registerCollector(registerer, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "request_duration_seconds",
+		Help:      "Latency of the requests to the API server until the response headers are received, by verb and resource",
+		Buckets:   latencyBuckets,
+	}, []string{"verb", "group", "resource"})); err != nil {
+		return nil, err
+	}
+	if metrics.throttleWait, err = registerCollector(registerer, prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "rate_limiter_wait_seconds",
+		Help:      "Time the requests to the API server waited for the client-side rate limiter",
+		Buckets:   latencyBuckets,
+	})); err != nil {
+		return nil, err
+	}
+	if metrics.watchReconnects, err = registerCollector(registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "watch_reconnects_total",
+		Help:      "Number of times the watches closed by the API server were established again, by resource",
+	}, []string{"group", "resource"})); err != nil {
+		return nil, err
+	}
+	if metrics.cacheRequests, err = registerCollector(registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "cache_requests_total",
+		Help:      "Number of lookups of the caches of the discovered server version and API resources, of the discovery cache and of the resource caches, by cache and result: hit or miss",
+	}, []string{"cache", "result"})); err != nil {
+		return nil, err
+	}
+	return metrics, nil
+}
+
+// registerCollector registers a given collector, or returns the equivalent collector registered before
+func registerCollector[T prometheus.Collector](registerer prometheus.Registerer, collector T) (T, error) {
+	if err := registerer.Register(collector); err != nil {
+		var alreadyRegistered prometheus.AlreadyRegisteredError
+		if errors.As(err, &alreadyRegistered) {
+			if existing, ok := alreadyRegistered.ExistingCollector.(T); ok {
+				return existing, nil
+			}
+		}
+		return collector, err
+	}
+	return collector, nil
+}
+
+// instrument returns a copy of a given rest config counting and timing its requests
+func (metrics *clientMetrics) instrument(restConfig *restclient.Config) *restclient.Config {
+	if metrics == nil || restConfig == nil {
+		return restConfig
+	}
+	restConfig = restclient.CopyConfig(restConfig)
+	restConfig.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &metricsRoundTripper{metrics: metrics, next: rt}
+	})
+	return restConfig
+}
+
+// throttled returns a copy of a given rest config whose rate limiter times the waits of the requests, for a client of its own.
+// Without a rate limiter in the rest config, the client-go default one throttling at QPS and Burst is created, unless throttling is disabled
+func (metrics *clientMetrics) throttled(restConfig *restclient.Config) *restclient.Config {
+	if metrics == nil || restConfig == nil || (restConfig.RateLimiter == nil && restConfig.QPS < 0) {
+		return restConfig
+	}
+	restConfig = restclient.CopyConfig(restConfig)
+	rateLimiter := restConfig.RateLimiter
+	if rateLimiter == nil {
+		qps, burst := restConfig.QPS, restConfig.Burst
+		if qps == 0 {
+			qps = restclient.DefaultQPS
+		}
+		if burst == 0 {
+			burst = restclient.DefaultBurst
+		}
+		rateLimiter = flowcontrol.NewTokenBucketRateLimiter(qps, burst)
+	}
+	restConfig.RateLimiter = &metricsRateLimiter{RateLimiter: rateLimiter, wait: metrics.throttleWait}
+	return restConfig
+}
+
+// watchReconnected counts a watch of a given resource established again
+func (metrics *clientMetrics) watchReconnected(groupVersionResource *schema.GroupVersionResource) {
+	if metrics == nil {
+		return
+	}
+	metrics.watchReconnects.WithLabelValues(groupVersionResource.Group, groupVersionResource.Resource).Inc()
+}
+
+// cacheLookedUp counts a lookup of a given cache, hit if the value was cached
+func (metrics *clientMetrics) cacheLookedUp(cache string, hit bool) {
+	if metrics == nil {
+		return
+	}
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	metrics.cacheRequests.WithLabelValues(cache, result).Inc()
+}
+
+// metricsRoundTripper counts and times the requests to the API server by verb and resource
+type metricsRoundTripper struct {
+	metrics *clientMetrics
+	next    http.RoundTripper
+}
+
+func (rt *metricsRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	info := parseRequestInfo(req)
+	resource := info.Resource
+	if resource == "" {
+		// non-resource requests, e.g. discovery, are few and their paths are bounded
+		resource = info.Path
+	}
+	if info.Subresource != "" {
+		resource += "/" + info.Subresource
+	}
+
+	start := time.Now()
+	resp, err := rt.next.RoundTrip(req)
+	rt.metrics.requestDuration.WithLabelValues(info.Verb, info.Group, resource).Observe(time.Since(start).Seconds())
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	rt.metrics.requests.WithLabelValues(info.Verb, info.Group, resource, code).Inc()
+	return resp, err
+}
+
+// metricsRateLimiter times the waits for a rate limiter
+type metricsRateLimiter struct {
+	flowcontrol.RateLimiter
+	wait prometheus.Observer
+}
+
+func (l *metricsRateLimiter) Accept() {
+	start := time.Now()
+	l.RateLimiter.Accept()
+	l.wait.Observe(time.Since(start).Seconds())
+}
+
+func (l *metricsRateLimiter) Wait(ctx context.Context) error {
+	start := time.Now()
+	err := l.RateLimiter.Wait(ctx)
+	l.wait.Observe(time.Since(start).Seconds())
+	return err
+}
@@ -0,0 +1,176 @@
+package k8sinterface
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/discovery/cached/memory"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	restclient "k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+// rateLimiterWaits returns the number of waits for the rate limiters gathered by a given registry
+func rateLimiterWaits(t *testing.T, registry *prometheus.Registry) uint64 {
+	families, err := registry.Gather()
+	assert.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() == "k8s_interface_client_rate_limiter_wait_seconds" {
+			return family.GetMetric()[0].GetHistogram().GetSampleCount()
+		}
+	}
+	return 0
+}
+
+func TestClientMetrics(t *testing.T) {
+	defer tearDown()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", runtime.ContentTypeJSON)
+		if r.URL.Path == "/api/v1/namespaces/kube-system/secrets" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"Forbidden","code":403}`))
+			return
+		}
+		w.Write([]byte(`{"kind":"PodList","apiVersion":"v1","items":[]}`))
+	}))
+	defer server.Close()
+
+	registry := prometheus.NewRegistry()
+	k8sAPI, err := NewKubernetesApiForConfig(&restclient.Config{Host: server.URL}, ClientConfig{MetricsRegisterer: registry})
+	assert.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err = k8sAPI.KubernetesClient.CoreV1().Pods("default").List(context.Background(), metav1.ListOptions{})
+		assert.NoError(t, err)
+	}
+	_, err = k8sAPI.KubernetesClient.CoreV1().Secrets("kube-system").List(context.Background(), metav1.ListOptions{})
+	assert.Error(t, err)
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(k8sAPI.metrics.requests.WithLabelValues("list", "", "pods", "200")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(k8sAPI.metrics.requests.WithLabelValues("list", "", "secrets", "403")))
+	assert.Equal(t, 2, testutil.CollectAndCount(k8sAPI.metrics.requestDuration))
+	assert.Equal(t, uint64(3), rateLimiterWaits(t, registry))
+
+	// the KubernetesApi instances sharing a registerer share the collectors
+	other, err := NewKubernetesApiForConfig(&restclient.Config{Host: server.URL}, ClientConfig{MetricsRegisterer: registry})
+	assert.NoError(t, err)
+	_, err = other.KubernetesClient.CoreV1().Pods("default").List(context.Background(), metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, float64(3), testutil.ToFloat64(k8sAPI.metrics.requests.WithLabelValues("list", "", "pods", "200")))
+
+	// no metrics without a registerer
+	k8sAPI, err = NewKubernetesApiForConfig(&restclient.Config{Host: server.URL}, ClientConfig{})
+	assert.NoError(t, err)
+	assert.Nil(t, k8sAPI.metrics)
+}
+
+func TestThrottled(t *testing.T) {
+	metrics, err := newClientMetrics(prometheus.NewRegistry())
+	assert.NoError(t, err)
+
+	restConfig := &restclient.Config{Host: "https://127.0.0.1:6443"}
+	throttled := metrics.throttled(restConfig)
+	assert.Nil(t, restConfig.RateLimiter)
+	if assert.IsType(t, &metricsRateLimiter{}, throttled.RateLimiter) {
+		assert.Equal(t, restclient.DefaultQPS, throttled.RateLimiter.QPS())
+	}
+
+	rateLimiter := flowcontrol.NewTokenBucketRateLimiter(50, 100)
+	throttled = metrics.throttled(&restclient.Config{RateLimiter: rateLimiter})
+	if assert.IsType(t, &metricsRateLimiter{}, throttled.RateLimiter) {
+		assert.Equal(t, rateLimiter, throttled.RateLimiter.(*metricsRateLimiter).RateLimiter)
+	}
+
+	// throttling disabled
+	assert.Nil(t, metrics.throttled(&restclient.Config{QPS: -1}).RateLimiter)
+
+	var noMetrics *clientMetrics
+	assert.Equal(t, restConfig, noMetrics.throttled(restConfig))
+	assert.Equal(t, restConfig, noMetrics.instrument(restConfig))
+}
+
+func TestCacheMetrics(t *testing.T) {
+	k8sAPI := NewKubernetesApiMock()
+	k8sAPI.DiscoveryClient = newCapabilitiesDiscovery("v1.29.0")
+	var err error
+	k8sAPI.metrics, err = newClientMetrics(prometheus.NewRegistry())
+	assert.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		_, err = k8sAPI.HasFeature(FeatureEphemeralContainers)
+		assert.NoError(t, err)
+		_, err = k8sAPI.GetServerVersion()
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, float64(1), testutil.ToFloat64(k8sAPI.metrics.cacheRequests.WithLabelValues("server_resources", "miss")))
+	assert.Equal(t, float64(2), testutil.ToFloat64(k8sAPI.metrics.cacheRequests.WithLabelValues("server_resources", "hit")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(k8sAPI.metrics.cacheRequests.WithLabelValues("server_version", "miss")))
+	assert.Equal(t, float64(2), testutil.ToFloat64(k8sAPI.metrics.cacheRequests.WithLabelValues("server_version", "hit")))
+
+	// the discovery cache misses until it is populated
+	cachedClient := newTTLDiscoveryClient(memory.NewMemCacheClient(newFakeDiscovery("widgets")), time.Hour, k8sAPI.metrics)
+	for i := 0; i < 3; i++ {
+		_, err = cachedClient.ServerGroups()
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, float64(1), testutil.ToFloat64(k8sAPI.metrics.cacheRequests.WithLabelValues("discovery", "miss")))
+	assert.Equal(t, float64(2), testutil.ToFloat64(k8sAPI.metrics.cacheRequests.WithLabelValues("discovery", "hit")))
+
+	// the resource cache misses until its informer has synced
+	podsGVR := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	k8sAPI.DynamicClient = dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{podsGVR: "PodList"})
+	cache := k8sAPI.NewResourceCache(0)
+	assert.NoError(t, cache.Register(podsGVR, ""))
+	_, err = cache.List(podsGVR, "", "")
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cache.Start(ctx)
+	assert.True(t, cache.WaitForCacheSync(ctx))
+	_, err = cache.List(podsGVR, "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1), testutil.ToFloat64(k8sAPI.metrics.cacheRequests.WithLabelValues("resource_cache", "miss")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(k8sAPI.metrics.cacheRequests.WithLabelValues("resource_cache", "hit")))
+}
+
+func TestWatchReconnectMetrics(t *testing.T) {
+	k8sAPI := NewKubernetesApiMock()
+	var err error
+	k8sAPI.metrics, err = newClientMetrics(prometheus.NewRegistry())
+	assert.NoError(t, err)
+
+	// the first watch is closed by the server, the next ones stay open
+	var watches atomic.Int32
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	dynamicClient.PrependWatchReactor("pods", func(action k8stesting.Action) (bool, watch.Interface, error) {
+		watcher := watch.NewFake()
+		if watches.Add(1) == 1 {
+			watcher.Stop()
+		}
+		return true, watcher, nil
+	})
+	k8sAPI.DynamicClient = dynamicClient
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, err = k8sAPI.WatchResourcesWithContext(ctx, &podsResource, "default", "")
+	assert.NoError(t, err)
+
+	reconnects := k8sAPI.metrics.watchReconnects.WithLabelValues("", "pods")
+	assert.Eventually(t, func() bool { return testutil.ToFloat64(reconnects) == 1 }, 5*time.Second, 50*time.Millisecond)
+}
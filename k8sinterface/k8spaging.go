@@ -0,0 +1,133 @@
+package k8sinterface
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kubescape/k8s-interface/workloadinterface"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ListResourcesPaged lists a given resource in a given namespace, or in all namespaces if empty, a page of at most pageSize objects at a time,
+// and calls fn with each page, so the whole list is never held in memory
+//
+// The limit and continue fields of the list options are managed by the pager. If fn returns an error, listing stops and the error is returned
+func (k8sAPI *KubernetesApi) ListResourcesPaged(groupVersionResource *schema.GroupVersionResource, namespace string, listOptions metav1.ListOptions, pageSize int64, fn func(page []IWorkload) error) error {
+	return k8sAPI.ListResourcesPagedWithContext(k8sAPI.Context, groupVersionResource, namespace, listOptions, pageSize, fn)
+}
+
+// ListResourcesPagedWithContext works like ListResourcesPaged, but makes the API calls with a given context
+func (k8sAPI *KubernetesApi) ListResourcesPagedWithContext(ctx context.Context, groupVersionResource *schema.GroupVersionResource, namespace string, listOptions metav1.ListOptions, pageSize int64, fn func(page []IWorkload) error) error {
+	if pageSize <= 0 {
+		return fmt.Errorf("invalid page size: %d, must be positive", pageSize)
+	}
+
+	listOptions.Limit = pageSize
+	listOptions.Continue = ""
+	for {
+		page, continueToken, err := k8sAPI.listPage(ctx, groupVersionResource, namespace, listOptions)
+		if err != nil {
+			return err
+		}
+		if err := fn(page); err != nil {
+			return err
+		}
+		if continueToken == "" {
+			return nil
+		}
+		listOptions.Continue = continueToken
+	}
+}
+
+// ResourceIterator iterates over the objects of a resource, fetching a page at a time
+//
+//	it := k8sAPI.NewResourceIterator(&gvr, "", metav1.ListOptions{}, 500)
+//	for it.Next() {
+//		workload := it.Workload()
+//	}
+//	if err := it.Err(); err != nil {
+//		return err
+//	}
+type ResourceIterator struct {
+	ctx                  context.Context
+	k8sAPI               *KubernetesApi
+	groupVersionResource *schema.GroupVersionResource
+	namespace            string
+	listOptions          metav1.ListOptions
+
+	page    []IWorkload
+	current IWorkload
+	done    bool
+	err     error
+}
+
+// NewResourceIterator returns a ResourceIterator over a given resource in a given namespace, or in all namespaces if empty,
+// fetching pages of at most pageSize objects
+func (k8sAPI *KubernetesApi) NewResourceIterator(groupVersionResource *schema.GroupVersionResource, namespace string, listOptions metav1.ListOptions, pageSize int64) *ResourceIterator {
+	return k8sAPI.NewResourceIteratorWithContext(k8sAPI.Context, groupVersionResource, namespace, listOptions, pageSize)
+}
+
+// NewResourceIteratorWithContext works like NewResourceIterator, but makes the API calls with a given context
+func (k8sAPI *KubernetesApi) NewResourceIteratorWithContext(ctx context.Context, groupVersionResource *schema.GroupVersionResource, namespace string, listOptions metav1.ListOptions, pageSize int64) *ResourceIterator {
+	it := &ResourceIterator{
+		ctx:                  ctx,
+		k8sAPI:               k8sAPI,
+		groupVersionResource: groupVersionResource,
+		namespace:            namespace,
+		listOptions:          listOptions,
+	}
+	if pageSize <= 0 {
+		it.err = fmt.Errorf("invalid page size: %d, must be positive", pageSize)
+		it.done = true
+	}
+	it.listOptions.Limit = pageSize
+	it.listOptions.Continue = ""
+	return it
+}
+
+// Next advances the iterator to the next object, fetching the next page if needed. It returns false once there are no more objects or on error
+func (it *ResourceIterator) Next() bool {
+	// a page can be empty while more pages follow, so keep fetching until there is an object or the list is done
+	for len(it.page) == 0 {
+		if it.done {
+			it.current = nil
+			return false
+		}
+		page, continueToken, err := it.k8sAPI.listPage(it.ctx, it.groupVersionResource, it.namespace, it.listOptions)
+		if err != nil {
+			it.err = err
+			it.done = true
+			it.current = nil
+			return false
+		}
+		it.page = page
+		it.listOptions.Continue = continueToken
+		it.done = continueToken == ""
+	}
+	it.current, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+// Workload returns the object the iterator is at
+func (it *ResourceIterator) Workload() IWorkload {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *ResourceIterator) Err() error {
+	return it.err
+}
+
+// listPage lists a single page, returning its objects and the continue token of the next page, empty if it is the last
+func (k8sAPI *KubernetesApi) listPage(ctx context.Context, groupVersionResource *schema.GroupVersionResource, namespace string, listOptions metav1.ListOptions) ([]IWorkload, string, error) {
+	uList, err := k8sAPI.ResourceInterface(groupVersionResource, namespace).List(ctx, listOptions)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to LIST resources, reason: %s", err.Error())
+	}
+	workloads := make([]IWorkload, len(uList.Items))
+	for i := range uList.Items {
+		workloads[i] = workloadinterface.NewWorkloadObj(uList.Items[i].Object)
+	}
+	return workloads, uList.GetContinue(), nil
+}
@@ -0,0 +1,76 @@
+package k8sinterface
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func newPagingKubernetesApiMock(names ...string) *KubernetesApi {
+	k8sAPI := NewKubernetesApiMock()
+	objects := make([]runtime.Object, len(names))
+	for i, name := range names {
+		objects[i] = &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": "default",
+			},
+		}}
+	}
+	k8sAPI.DynamicClient = dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{{Version: "v1", Resource: "pods"}: "PodList"}, objects...)
+	return k8sAPI
+}
+
+func TestListResourcesPaged(t *testing.T) {
+	podsGVR := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	k8sAPI := newPagingKubernetesApiMock("nginx", "redis")
+
+	var names []string
+	err := k8sAPI.ListResourcesPaged(&podsGVR, "default", metav1.ListOptions{}, 1, func(page []IWorkload) error {
+		for _, workload := range page {
+			names = append(names, workload.GetName())
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"nginx", "redis"}, names)
+
+	stop := errors.New("stop")
+	err = k8sAPI.ListResourcesPagedWithContext(context.Background(), &podsGVR, "default", metav1.ListOptions{}, 1, func(page []IWorkload) error {
+		return stop
+	})
+	assert.ErrorIs(t, err, stop)
+
+	err = k8sAPI.ListResourcesPaged(&podsGVR, "default", metav1.ListOptions{}, 0, func(page []IWorkload) error {
+		return nil
+	})
+	assert.Error(t, err)
+}
+
+func TestResourceIterator(t *testing.T) {
+	podsGVR := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	k8sAPI := newPagingKubernetesApiMock("nginx", "redis")
+
+	var names []string
+	it := k8sAPI.NewResourceIterator(&podsGVR, "default", metav1.ListOptions{}, 1)
+	for it.Next() {
+		names = append(names, it.Workload().GetName())
+	}
+	assert.NoError(t, it.Err())
+	assert.ElementsMatch(t, []string{"nginx", "redis"}, names)
+	assert.Nil(t, it.Workload())
+
+	it = k8sAPI.NewResourceIterator(&podsGVR, "default", metav1.ListOptions{}, -1)
+	assert.False(t, it.Next())
+	assert.Error(t, it.Err())
+}
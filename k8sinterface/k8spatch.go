@@ -0,0 +1,179 @@
+package k8sinterface
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/kubescape/k8s-interface/workloadinterface"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// PatchResource patches a given object of a given resource with a given patch, and returns the object as persisted
+//
+// The patch type is one of types.StrategicMergePatchType, types.MergePatchType and types.JSONPatchType, whose patches
+// StrategicMergeFrom, MergePatchFrom and JSONPatchFrom compute respectively. Custom resources do not support strategic merge patches
+func (k8sAPI *KubernetesApi) PatchResource(ctx context.Context, groupVersionResource *schema.GroupVersionResource, name, namespace string, patchType types.PatchType, payload []byte) (IWorkload, error) {
+	w, err := k8sAPI.ResourceInterface(groupVersionResource, namespace).Patch(ctx, name, patchType, payload, metav1.PatchOptions{DryRun: dryRunOptions(ctx)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to PATCH resource, resource: '%s', namespace: '%s', name: '%s', reason: %s", groupVersionResource.String(), namespace, name, err.Error())
+	}
+	return workloadinterface.NewWorkloadObj(w.Object), nil
+}
+
+// StrategicMergeFrom returns the strategic merge patch changing a given object into another one, e.g. merging lists of containers by name.
+// Only the kinds built into the API server are supported, for the others use MergePatchFrom or JSONPatchFrom
+func StrategicMergeFrom(original, modified IWorkload) ([]byte, error) {
+	gvk := schema.FromAPIVersionAndKind(original.GetApiVersion(), original.GetKind())
+	dataStruct, err := scheme.Scheme.New(gvk)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create strategic merge patch, kind: '%s', reason: %s", gvk.String(), err.Error())
+	}
+	originalJSON, modifiedJSON, err := marshalPatchObjects(original, modified)
+	if err != nil {
+		return nil, err
+	}
+	patch, err := strategicpatch.CreateTwoWayMergePatch(originalJSON, modifiedJSON, dataStruct)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create strategic merge patch, kind: '%s', reason: %s", gvk.String(), err.Error())
+	}
+	return patch, nil
+}
+
+// MergePatchFrom returns the JSON merge patch (RFC 7386) changing a given object into another one. Lists are replaced as a whole
+func MergePatchFrom(original, modified IWorkload) ([]byte, error) {
+	originalJSON, modifiedJSON, err := marshalPatchObjects(original, modified)
+	if err != nil {
+		return nil, err
+	}
+	patch, err := jsonpatch.CreateMergePatch(originalJSON, modifiedJSON)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create merge patch, reason: %s", err.Error())
+	}
+	return patch, nil
+}
+
+// JSONPatchFrom returns the JSON patch (RFC 6902) changing a given object into another one, with an operation per changed field.
+// Lists are patched by index if only appended to, shortened or changed in place, and replaced as a whole otherwise
+func JSONPatchFrom(original, modified IWorkload) ([]byte, error) {
+	originalJSON, modifiedJSON, err := marshalPatchObjects(original, modified)
+	if err != nil {
+		return nil, err
+	}
+	// the objects are decoded from JSON so that equal numbers are of the same type
+	var originalObj, modifiedObj interface{}
+	if err := json.Unmarshal(originalJSON, &originalObj); err != nil {
+		return nil, fmt.Errorf("failed to create JSON patch, reason: %s", err.Error())
+	}
+	if err := json.Unmarshal(modifiedJSON, &modifiedObj); err != nil {
+		return nil, fmt.Errorf("failed to create JSON patch, reason: %s", err.Error())
+	}
+	operations := diffJSON("", originalObj, modifiedObj, []jsonPatchOperation{})
+	return json.Marshal(operations)
+}
+
+func marshalPatchObjects(original, modified IWorkload) ([]byte, []byte, error) {
+	originalJSON, err := json.Marshal(original.GetObject())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal original object, reason: %s", err.Error())
+	}
+	modifiedJSON, err := json.Marshal(modified.GetObject())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal modified object, reason: %s", err.Error())
+	}
+	return originalJSON, modifiedJSON, nil
+}
+
+// jsonPatchOperation is an operation of a JSON patch
+type jsonPatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+var jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// diffJSON appends to given operations the ones changing a given decoded JSON value into another one, at a given JSON pointer
+func diffJSON(path string, original, modified interface{}, operations []jsonPatchOperation) []jsonPatchOperation {
+	switch originalValue := original.(type) {
+	case map[string]interface{}:
+		if modifiedValue, ok := modified.(map[string]interface{}); ok {
+			return diffJSONObjects(path, originalValue, modifiedValue, operations)
+		}
+	case []interface{}:
+		if modifiedValue, ok := modified.([]interface{}); ok {
+			return diffJSONArrays(path, originalValue, modifiedValue, operations)
+		}
+	}
+	if reflect.DeepEqual(original, modified) {
+		return operations
+	}
+	return append(operations, jsonPatchOperation{Op: "replace", Path: path, Value: jsonPatchValue(modified)})
+}
+
+func diffJSONObjects(path string, original, modified map[string]interface{}, operations []jsonPatchOperation) []jsonPatchOperation {
+	keys := make([]string, 0, len(original)+len(modified))
+	for key := range original {
+		keys = append(keys, key)
+	}
+	for key := range modified {
+		if _, ok := original[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		keyPath := path + "/" + jsonPointerEscaper.Replace(key)
+		originalValue, inOriginal := original[key]
+		modifiedValue, inModified := modified[key]
+		switch {
+		case !inModified:
+			operations = append(operations, jsonPatchOperation{Op: "remove", Path: keyPath})
+		case !inOriginal:
+			operations = append(operations, jsonPatchOperation{Op: "add", Path: keyPath, Value: jsonPatchValue(modifiedValue)})
+		default:
+			operations = diffJSON(keyPath, originalValue, modifiedValue, operations)
+		}
+	}
+	return operations
+}
+
+func diffJSONArrays(path string, original, modified []interface{}, operations []jsonPatchOperation) []jsonPatchOperation {
+	common := len(original)
+	if len(modified) < common {
+		common = len(modified)
+	}
+	// an array whose elements are inserted or removed other than at its end is replaced, patching it by index would shift all the elements
+	if len(original) != len(modified) && !reflect.DeepEqual(original[:common], modified[:common]) {
+		return append(operations, jsonPatchOperation{Op: "replace", Path: path, Value: jsonPatchValue(modified)})
+	}
+	for i := 0; i < common; i++ {
+		operations = diffJSON(path+"/"+strconv.Itoa(i), original[i], modified[i], operations)
+	}
+	for i := common; i < len(modified); i++ {
+		operations = append(operations, jsonPatchOperation{Op: "add", Path: path + "/" + strconv.Itoa(i), Value: jsonPatchValue(modified[i])})
+	}
+	// the elements are removed from the end, so that the indexes of the others do not change
+	for i := len(original) - 1; i >= common; i-- {
+		operations = append(operations, jsonPatchOperation{Op: "remove", Path: path + "/" + strconv.Itoa(i)})
+	}
+	return operations
+}
+
+// jsonPatchValue returns a given value of an operation, marshaling null explicitly as nil values are omitted
+func jsonPatchValue(value interface{}) interface{} {
+	if value == nil {
+		return json.RawMessage("null")
+	}
+	return value
+}
@@ -0,0 +1,128 @@
+package k8sinterface
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/kubescape/k8s-interface/workloadinterface"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func newPatchWorkload(t *testing.T, obj string) IWorkload {
+	workload, err := workloadinterface.NewWorkload([]byte(obj))
+	assert.NoError(t, err)
+	return workload
+}
+
+const patchDeployment = `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"nginx","namespace":"default","labels":{"app":"nginx"}},"spec":{"replicas":1,"template":{"spec":{"containers":[{"name":"nginx","image":"nginx:1.14.2"},{"name":"sidecar","image":"busybox"}]}}}}`
+
+const patchDeploymentModified = `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"nginx","namespace":"default","labels":{"tier":"web"}},"spec":{"replicas":3,"template":{"spec":{"containers":[{"name":"nginx","image":"nginx:1.25.0"},{"name":"sidecar","image":"busybox"}]}}}}`
+
+func TestStrategicMergeFrom(t *testing.T) {
+	patch, err := StrategicMergeFrom(newPatchWorkload(t, patchDeployment), newPatchWorkload(t, patchDeploymentModified))
+	assert.NoError(t, err)
+	// the containers are merged by name, so that only the changed image is patched
+	assert.JSONEq(t, `{"metadata":{"labels":{"app":null,"tier":"web"}},"spec":{"replicas":3,"template":{"spec":{"$setElementOrder/containers":[{"name":"nginx"},{"name":"sidecar"}],"containers":[{"image":"nginx:1.25.0","name":"nginx"}]}}}}`, string(patch))
+
+	crd := `{"apiVersion":"example.com/v1","kind":"Widget","metadata":{"name":"widget"}}`
+	_, err = StrategicMergeFrom(newPatchWorkload(t, crd), newPatchWorkload(t, crd))
+	assert.Error(t, err)
+}
+
+func TestMergePatchFrom(t *testing.T) {
+	patch, err := MergePatchFrom(newPatchWorkload(t, patchDeployment), newPatchWorkload(t, patchDeploymentModified))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"metadata":{"labels":{"app":null,"tier":"web"}},"spec":{"replicas":3,"template":{"spec":{"containers":[{"image":"nginx:1.25.0","name":"nginx"},{"image":"busybox","name":"sidecar"}]}}}}`, string(patch))
+}
+
+func TestJSONPatchFrom(t *testing.T) {
+	tests := []struct {
+		name     string
+		original string
+		modified string
+		want     string
+	}{
+		{
+			name:     "Unchanged",
+			original: patchDeployment,
+			modified: patchDeployment,
+			want:     `[]`,
+		},
+		{
+			name:     "Changed fields",
+			original: patchDeployment,
+			modified: patchDeploymentModified,
+			want:     `[{"op":"remove","path":"/metadata/labels/app"},{"op":"add","path":"/metadata/labels/tier","value":"web"},{"op":"replace","path":"/spec/replicas","value":3},{"op":"replace","path":"/spec/template/spec/containers/0/image","value":"nginx:1.25.0"}]`,
+		},
+		{
+			name:     "Escaped keys",
+			original: `{"metadata":{"annotations":{"example.com/a":"1"}}}`,
+			modified: `{"metadata":{"annotations":{"example.com/a":"2","b~c":null}}}`,
+			want:     `[{"op":"add","path":"/metadata/annotations/b~0c","value":null},{"op":"replace","path":"/metadata/annotations/example.com~1a","value":"2"}]`,
+		},
+		{
+			name:     "Lists appended to and shortened",
+			original: `{"spec":{"a":[1,2],"b":[1,2,3]}}`,
+			modified: `{"spec":{"a":[1,2,3],"b":[1]}}`,
+			want:     `[{"op":"add","path":"/spec/a/2","value":3},{"op":"remove","path":"/spec/b/2"},{"op":"remove","path":"/spec/b/1"}]`,
+		},
+		{
+			name:     "List inserted into",
+			original: `{"spec":{"a":[1,2]}}`,
+			modified: `{"spec":{"a":[0,1,2]}}`,
+			want:     `[{"op":"replace","path":"/spec/a","value":[0,1,2]}]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patch, err := JSONPatchFrom(newPatchWorkload(t, tt.original), newPatchWorkload(t, tt.modified))
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(patch))
+
+			// applying the patch to the original object results in the modified one
+			decoded, err := jsonpatch.DecodePatch(patch)
+			assert.NoError(t, err)
+			patched, err := decoded.Apply([]byte(tt.original))
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.modified, string(patched))
+		})
+	}
+}
+
+func TestPatchResource(t *testing.T) {
+	k8sAPI := NewKubernetesApiMock()
+	deploymentsGVR := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	original := newPatchWorkload(t, patchDeployment)
+	modified := newPatchWorkload(t, patchDeploymentModified)
+	for _, patchType := range []types.PatchType{types.MergePatchType, types.JSONPatchType} {
+		t.Run(string(patchType), func(t *testing.T) {
+			k8sAPI.DynamicClient = dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+				map[schema.GroupVersionResource]string{deploymentsGVR: "DeploymentList"}, &unstructured.Unstructured{Object: newPatchWorkload(t, patchDeployment).GetObject()})
+
+			var payload []byte
+			var err error
+			if patchType == types.JSONPatchType {
+				payload, err = JSONPatchFrom(original, modified)
+			} else {
+				payload, err = MergePatchFrom(original, modified)
+			}
+			assert.NoError(t, err)
+
+			workload, err := k8sAPI.PatchResource(context.Background(), &deploymentsGVR, "nginx", "default", patchType, payload)
+			assert.NoError(t, err)
+			spec, _ := json.Marshal(workload.GetObject()["spec"])
+			assert.JSONEq(t, `{"replicas":3,"template":{"spec":{"containers":[{"name":"nginx","image":"nginx:1.25.0"},{"name":"sidecar","image":"busybox"}]}}}`, string(spec))
+			assert.Equal(t, map[string]string{"tier": "web"}, workload.GetLabels())
+		})
+	}
+
+	_, err := k8sAPI.PatchResource(context.Background(), &deploymentsGVR, "missing", "default", types.MergePatchType, []byte(`{}`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,96 @@
+package k8sinterface
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// PortForward is a port forwarded from localhost to a pod, returned by KubernetesApi.PortForward
+type PortForward struct {
+	// Ready is closed once the port is forwarded
+	Ready <-chan struct{}
+	// Err receives the error that stopped the forwarding, if any, and is closed once the forwarding stopped
+	Err <-chan error
+
+	forwarder *portforward.PortForwarder
+	stopCh    chan struct{}
+	closeOnce sync.Once
+}
+
+// LocalPort returns the local port forwarded to the pod, chosen by the system if 0 was requested. It is known once Ready is closed
+func (pf *PortForward) LocalPort() (uint16, error) {
+	ports, err := pf.forwarder.GetPorts()
+	if err != nil {
+		return 0, err
+	}
+	if len(ports) == 0 {
+		return 0, fmt.Errorf("no port forwarded")
+	}
+	return ports[0].Local, nil
+}
+
+// Close stops the forwarding. It is safe to call more than once
+func (pf *PortForward) Close() {
+	pf.closeOnce.Do(func() {
+		close(pf.stopCh)
+	})
+}
+
+// PortForward forwards a local port on localhost, or a port chosen by the system if 0, to a port of a pod, until Close is called or the context is done
+//
+// The port is forwarded once the Ready channel of the returned PortForward is closed, or failed to be if its Err channel receives an error first
+func (k8sAPI *KubernetesApi) PortForward(ctx context.Context, namespace, podName string, localPort, remotePort uint16) (*PortForward, error) {
+	if remotePort == 0 {
+		return nil, fmt.Errorf("failed to PORT-FORWARD to pod, namespace: '%s', name: '%s', reason: remote port is required", namespace, podName)
+	}
+	if k8sAPI.K8SConfig == nil {
+		return nil, fmt.Errorf("failed to PORT-FORWARD to pod, namespace: '%s', name: '%s', reason: kubernetes config is not loaded", namespace, podName)
+	}
+
+	transport, upgrader, err := spdy.RoundTripperFor(k8sAPI.K8SConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to PORT-FORWARD to pod, namespace: '%s', name: '%s', reason: %s", namespace, podName, err.Error())
+	}
+	url := k8sAPI.KubernetesClient.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(podName).
+		SubResource("portforward").
+		URL()
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, url)
+
+	stopCh := make(chan struct{})
+	readyCh := make(chan struct{})
+	forwarder, err := portforward.New(dialer, []string{fmt.Sprintf("%d:%d", localPort, remotePort)}, stopCh, readyCh, io.Discard, io.Discard)
+	if err != nil {
+		return nil, fmt.Errorf("failed to PORT-FORWARD to pod, namespace: '%s', name: '%s', reason: %s", namespace, podName, err.Error())
+	}
+
+	errCh := make(chan error, 1)
+	pf := &PortForward{
+		Ready:     readyCh,
+		Err:       errCh,
+		forwarder: forwarder,
+		stopCh:    stopCh,
+	}
+	go func() {
+		defer close(errCh)
+		if err := forwarder.ForwardPorts(); err != nil {
+			errCh <- fmt.Errorf("failed to PORT-FORWARD to pod, namespace: '%s', name: '%s', reason: %s", namespace, podName, err.Error())
+		}
+	}()
+	go func() {
+		select {
+		case <-ctx.Done():
+			pf.Close()
+		case <-stopCh:
+		}
+	}()
+	return pf, nil
+}
@@ -0,0 +1,27 @@
+package k8sinterface
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPortForward(t *testing.T) {
+	k8sAPI := NewKubernetesApiMock()
+
+	_, err := k8sAPI.PortForward(context.Background(), "default", "nginx", 8080, 0)
+	assert.ErrorContains(t, err, "remote port is required")
+
+	_, err = k8sAPI.PortForward(context.Background(), "default", "nginx", 8080, 80)
+	assert.ErrorContains(t, err, "kubernetes config is not loaded")
+}
+
+func TestPortForwardClose(t *testing.T) {
+	pf := &PortForward{stopCh: make(chan struct{})}
+	pf.Close()
+	pf.Close()
+
+	_, open := <-pf.stopCh
+	assert.False(t, open)
+}
@@ -0,0 +1,185 @@
+package k8sinterface
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kubescape/k8s-interface/workloadinterface"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+)
+
+// resourceCacheKey identifies an informer of a ResourceCache: the resource it watches and the label selector it is scoped to
+type resourceCacheKey struct {
+	resource      schema.GroupVersionResource
+	labelSelector string
+}
+
+// ResourceCache serves GET and LIST of registered resources from in-memory caches kept up to date by shared informers
+//
+// Resources are registered per GVR, optionally scoped to a label selector, and informers scoped to the same selector share a factory.
+// Register the resources, then call Start and WaitForCacheSync before reading from the cache.
+// The reads of a ResourceCache of a KubernetesApi are counted by its metrics, as misses if the informer has not synced yet
+type ResourceCache struct {
+	dynamicClient dynamic.Interface
+	resyncPeriod  time.Duration
+	metrics       *clientMetrics
+
+	mu        sync.Mutex
+	factories map[string]dynamicinformer.DynamicSharedInformerFactory
+	informers map[resourceCacheKey]informers.GenericInformer
+}
+
+// NewResourceCache returns an empty ResourceCache backed by the dynamic client of the KubernetesApi
+//
+// A resync period of 0 disables periodic resyncs
+func (k8sAPI *KubernetesApi) NewResourceCache(resyncPeriod time.Duration) *ResourceCache {
+	cache := NewResourceCache(k8sAPI.DynamicClient, resyncPeriod)
+	cache.metrics = k8sAPI.metrics
+	return cache
+}
+
+// NewResourceCache returns an empty ResourceCache backed by a given dynamic client
+//
+// A resync period of 0 disables periodic resyncs
+func NewResourceCache(dynamicClient dynamic.Interface, resyncPeriod time.Duration) *ResourceCache {
+	return &ResourceCache{
+		dynamicClient: dynamicClient,
+		resyncPeriod:  resyncPeriod,
+		factories:     map[string]dynamicinformer.DynamicSharedInformerFactory{},
+		informers:     map[resourceCacheKey]informers.GenericInformer{},
+	}
+}
+
+// Register adds an informer for a given resource, scoped to a given label selector, in all namespaces. An empty label selector watches every object
+//
+// Registering the same resource and selector twice is a no-op. Resources registered after Start are only watched once Start is called again
+func (c *ResourceCache) Register(resource schema.GroupVersionResource, labelSelector string) error {
+	if _, err := labels.Parse(labelSelector); err != nil {
+		return fmt.Errorf("invalid label selector '%s', reason: %s", labelSelector, err.Error())
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := resourceCacheKey{resource: resource, labelSelector: labelSelector}
+	if _, ok := c.informers[key]; ok {
+		return nil
+	}
+
+	factory, ok := c.factories[labelSelector]
+	if !ok {
+		factory = dynamicinformer.NewFilteredDynamicSharedInformerFactory(c.dynamicClient, c.resyncPeriod, metav1.NamespaceAll, func(options *metav1.ListOptions) {
+			options.LabelSelector = labelSelector
+		})
+		c.factories[labelSelector] = factory
+	}
+	c.informers[key] = factory.ForResource(resource)
+	return nil
+}
+
+// Start starts the informers of all registered resources, which run until the context is done
+func (c *ResourceCache) Start(ctx context.Context) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, factory := range c.factories {
+		factory.Start(ctx.Done())
+	}
+}
+
+// WaitForCacheSync waits until the caches of all started informers are synced, or until the context is done
+//
+// It returns false if any cache did not sync
+func (c *ResourceCache) WaitForCacheSync(ctx context.Context) bool {
+	c.mu.Lock()
+	factories := make([]dynamicinformer.DynamicSharedInformerFactory, 0, len(c.factories))
+	for _, factory := range c.factories {
+		factories = append(factories, factory)
+	}
+	c.mu.Unlock()
+
+	synced := true
+	for _, factory := range factories {
+		for _, ok := range factory.WaitForCacheSync(ctx.Done()) {
+			synced = synced && ok
+		}
+	}
+	return synced
+}
+
+// Get returns an object of a registered resource and selector from the cache. The namespace is ignored for cluster-scoped resources
+func (c *ResourceCache) Get(resource schema.GroupVersionResource, labelSelector, namespace, name string) (IWorkload, error) {
+	informer, err := c.informer(resource, labelSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	var obj runtime.Object
+	if IsNamespaceScope(&resource) {
+		obj, err = informer.Lister().ByNamespace(namespace).Get(name)
+	} else {
+		obj, err = informer.Lister().Get(name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to GET resource from cache, resource: '%s', namespace: '%s', name: '%s', reason: %s", resource.String(), namespace, name, err.Error())
+	}
+	return toWorkload(obj)
+}
+
+// List returns the objects of a registered resource and selector from the cache, in a given namespace or in all namespaces if empty
+func (c *ResourceCache) List(resource schema.GroupVersionResource, labelSelector, namespace string) ([]IWorkload, error) {
+	informer, err := c.informer(resource, labelSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	var objs []runtime.Object
+	if namespace != "" && IsNamespaceScope(&resource) {
+		objs, err = informer.Lister().ByNamespace(namespace).List(labels.Everything())
+	} else {
+		objs, err = informer.Lister().List(labels.Everything())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to LIST resources from cache, resource: '%s', reason: %s", resource.String(), err.Error())
+	}
+
+	workloads := make([]IWorkload, 0, len(objs))
+	for _, obj := range objs {
+		workload, err := toWorkload(obj)
+		if err != nil {
+			return nil, err
+		}
+		workloads = append(workloads, workload)
+	}
+	return workloads, nil
+}
+
+func (c *ResourceCache) informer(resource schema.GroupVersionResource, labelSelector string) (informers.GenericInformer, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	informer, ok := c.informers[resourceCacheKey{resource: resource, labelSelector: labelSelector}]
+	if !ok {
+		return nil, fmt.Errorf("resource '%s' with label selector '%s' is not registered in the cache", resource.String(), labelSelector)
+	}
+	c.metrics.cacheLookedUp("resource_cache", informer.Informer().HasSynced())
+	return informer, nil
+}
+
+// toWorkload converts an object served by a dynamic informer into a workload
+func toWorkload(obj runtime.Object) (IWorkload, error) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("unexpected object type '%T' in cache", obj)
+	}
+	return workloadinterface.NewWorkloadObj(u.Object), nil
+}
@@ -0,0 +1,56 @@
+package k8sinterface
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func TestResourceCache(t *testing.T) {
+	InitializeMapResourcesMock()
+
+	podsGVR := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	newPod := func(name, app string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": "default",
+				"labels":    map[string]interface{}{"app": app},
+			},
+		}}
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{podsGVR: "PodList"},
+		newPod("nginx", "nginx"), newPod("redis", "redis"))
+
+	cache := NewResourceCache(client, 0)
+	assert.Error(t, cache.Register(podsGVR, "app in (nginx"))
+	assert.NoError(t, cache.Register(podsGVR, "app=nginx"))
+	assert.NoError(t, cache.Register(podsGVR, "app=nginx"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cache.Start(ctx)
+	assert.True(t, cache.WaitForCacheSync(ctx))
+
+	workload, err := cache.Get(podsGVR, "app=nginx", "default", "nginx")
+	assert.NoError(t, err)
+	assert.Equal(t, "nginx", workload.GetName())
+
+	_, err = cache.Get(podsGVR, "app=nginx", "default", "redis")
+	assert.Error(t, err)
+
+	workloads, err := cache.List(podsGVR, "app=nginx", "")
+	assert.NoError(t, err)
+	assert.Len(t, workloads, 1)
+
+	_, err = cache.List(podsGVR, "app=redis", "default")
+	assert.Error(t, err)
+}
@@ -0,0 +1,77 @@
+package k8sinterface
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/restmapper"
+)
+
+// GVKToGVR returns the resource of a given kind, of the version of the kind
+func (k8sAPI *KubernetesApi) GVKToGVR(gvk schema.GroupVersionKind) (schema.GroupVersionResource, error) {
+	mapping, err := k8sAPI.restMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return schema.GroupVersionResource{}, fmt.Errorf("failed to map kind to resource, kind: '%s', reason: %w", gvk.String(), err)
+	}
+	return mapping.Resource, nil
+}
+
+// GVRToGVK returns the kind of a given resource, which can be partially specified, e.g. without group or version
+func (k8sAPI *KubernetesApi) GVRToGVK(gvr schema.GroupVersionResource) (schema.GroupVersionKind, error) {
+	gvk, err := k8sAPI.restMapper().KindFor(gvr)
+	if err != nil {
+		return schema.GroupVersionKind{}, fmt.Errorf("failed to map resource to kind, resource: '%s', reason: %w", gvr.String(), err)
+	}
+	return gvk, nil
+}
+
+// KindToResource returns the resource of a given kind, of the preferred version of its group
+//
+// The kind can be qualified by its group, e.g. "Deployment.apps", to tell apart kinds of the same name in different groups
+func (k8sAPI *KubernetesApi) KindToResource(kind string) (schema.GroupVersionResource, error) {
+	mapping, err := k8sAPI.restMapper().RESTMapping(schema.ParseGroupKind(kind))
+	if err != nil {
+		return schema.GroupVersionResource{}, fmt.Errorf("failed to map kind to resource, kind: '%s', reason: %w", kind, err)
+	}
+	return mapping.Resource, nil
+}
+
+// IsNamespacedResource returns true if a given resource, which can be partially specified, is namespaced
+func (k8sAPI *KubernetesApi) IsNamespacedResource(gvr schema.GroupVersionResource) (bool, error) {
+	gvk, err := k8sAPI.GVRToGVK(gvr)
+	if err != nil {
+		return false, err
+	}
+	mapping, err := k8sAPI.restMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return false, fmt.Errorf("failed to map kind to resource, kind: '%s', reason: %w", gvk.String(), err)
+	}
+	return mapping.Scope.Name() == meta.RESTScopeNameNamespace, nil
+}
+
+// restMapper returns the RESTMapper of the KubernetesApi, which discovers the API resources on first use,
+// then again on a kind or resource missing from a stale cache
+func (k8sAPI *KubernetesApi) restMapper() *restmapper.DeferredDiscoveryRESTMapper {
+	k8sAPI.mapperLock.Lock()
+	defer k8sAPI.mapperLock.Unlock()
+	if k8sAPI.mapper == nil {
+		cachedClient, ok := k8sAPI.DiscoveryClient.(discovery.CachedDiscoveryInterface)
+		if !ok {
+			cachedClient = newTTLDiscoveryClient(memory.NewMemCacheClient(k8sAPI.DiscoveryClient), DefaultDiscoveryCacheTTL, k8sAPI.metrics)
+		}
+		k8sAPI.mapper = restmapper.NewDeferredDiscoveryRESTMapper(cachedClient)
+	}
+	return k8sAPI.mapper
+}
+
+// resetRESTMapper makes the RESTMapper discover the API resources again on next use
+func (k8sAPI *KubernetesApi) resetRESTMapper() {
+	k8sAPI.mapperLock.Lock()
+	defer k8sAPI.mapperLock.Unlock()
+	if k8sAPI.mapper != nil {
+		k8sAPI.mapper.Reset()
+	}
+}
@@ -0,0 +1,84 @@
+package k8sinterface
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestRESTMapper(t *testing.T) {
+	k8sAPI := NewKubernetesApiMock()
+	deployments := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+
+	t.Run("GVKToGVR", func(t *testing.T) {
+		gvr, err := k8sAPI.GVKToGVR(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"})
+		assert.NoError(t, err)
+		assert.Equal(t, deployments, gvr)
+
+		gvr, err = k8sAPI.GVKToGVR(schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "NetworkPolicy"})
+		assert.NoError(t, err)
+		assert.Equal(t, "networkpolicies", gvr.Resource)
+
+		_, err = k8sAPI.GVKToGVR(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"})
+		assert.True(t, meta.IsNoMatchError(err))
+	})
+
+	t.Run("GVRToGVK", func(t *testing.T) {
+		gvk, err := k8sAPI.GVRToGVK(deployments)
+		assert.NoError(t, err)
+		assert.Equal(t, schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, gvk)
+
+		gvk, err = k8sAPI.GVRToGVK(schema.GroupVersionResource{Resource: "daemonsets"})
+		assert.NoError(t, err)
+		assert.Equal(t, schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "DaemonSet"}, gvk)
+	})
+
+	t.Run("KindToResource", func(t *testing.T) {
+		gvr, err := k8sAPI.KindToResource("Deployment.apps")
+		assert.NoError(t, err)
+		assert.Equal(t, deployments, gvr)
+
+		gvr, err = k8sAPI.KindToResource("Ingress.networking.k8s.io")
+		assert.NoError(t, err)
+		assert.Equal(t, schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"}, gvr)
+
+		_, err = k8sAPI.KindToResource("Widget.example.com")
+		assert.Error(t, err)
+	})
+
+	t.Run("IsNamespacedResource", func(t *testing.T) {
+		namespaced, err := k8sAPI.IsNamespacedResource(deployments)
+		assert.NoError(t, err)
+		assert.True(t, namespaced)
+
+		namespaced, err = k8sAPI.IsNamespacedResource(schema.GroupVersionResource{Version: "v1", Resource: "nodes"})
+		assert.NoError(t, err)
+		assert.False(t, namespaced)
+
+		_, err = k8sAPI.IsNamespacedResource(schema.GroupVersionResource{Resource: "widgets"})
+		assert.Error(t, err)
+	})
+}
+
+func TestRESTMapperRediscovery(t *testing.T) {
+	fakeDiscovery := newFakeDiscovery()
+	k8sAPI := NewKubernetesApiMock()
+	k8sAPI.DiscoveryClient = fakeDiscovery
+
+	_, err := k8sAPI.KindToResource("Pod")
+	assert.NoError(t, err)
+
+	// a CRD installed after the API resources were discovered
+	fakeDiscovery.Resources[1] = fakeCustomResources("widgets")
+	fakeDiscovery.Resources[1].APIResources[0].Kind = "Widget"
+	gvr, err := k8sAPI.KindToResource("Widget.example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}, gvr)
+
+	// resources the mapping of the package does not know are resolved by the RESTMapper
+	gvr, err = k8sAPI.getGroupVersionResource("widget")
+	assert.NoError(t, err)
+	assert.Equal(t, "widgets", gvr.Resource)
+}
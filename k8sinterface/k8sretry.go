@@ -0,0 +1,212 @@
+package k8sinterface
+
+import (
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultMaxRetries     = 3
+	defaultInitialBackoff = 100 * time.Millisecond
+	defaultMaxBackoff     = 10 * time.Second
+)
+
+// DefaultRetryBudget is the retry budget shared by all the clients whose retry config sets none:
+// bursts of up to 10 retries, and retries sustained at up to 10% of the requests
+var DefaultRetryBudget = NewRetryBudget(10, 0.1)
+
+// RetryConfig configures the retries of idempotent requests (GET, HEAD, OPTIONS, PUT and DELETE)
+// that fail with 429 Too Many Requests, a 5xx server error or a connection reset
+//
+// Retries are delayed by a jittered exponential backoff, or by the Retry-After header of the response if set, up to MaxBackoff.
+// Zero fields are set to their defaults
+type RetryConfig struct {
+	// MaxRetries is the maximum number of retries of a request, 3 by default
+	MaxRetries int
+	// InitialBackoff is the delay before the first retry, doubled on every retry, 100ms by default
+	InitialBackoff time.Duration
+	// MaxBackoff is the maximum delay before a retry, 10s by default
+	MaxBackoff time.Duration
+	// Budget caps the number of retries, DefaultRetryBudget by default
+	Budget *RetryBudget
+}
+
+// RetryBudget caps retries across requests, so that a struggling API server is not flooded with retries.
+// It is safe for concurrent use, and meant to be shared across clients
+//
+// Every retry spends a token, and every request that is not a retry earns a fraction of a token, up to a maximum
+type RetryBudget struct {
+	mu        sync.Mutex
+	tokens    float64
+	maxTokens float64
+	ratio     float64
+}
+
+// NewRetryBudget returns a full RetryBudget allowing bursts of up to maxTokens retries, and retries sustained at up to ratio of the requests
+func NewRetryBudget(maxTokens, ratio float64) *RetryBudget {
+	return &RetryBudget{
+		tokens:    maxTokens,
+		maxTokens: maxTokens,
+		ratio:     ratio,
+	}
+}
+
+// deposit earns a request's fraction of a token
+func (b *RetryBudget) deposit() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = min(b.tokens+b.ratio, b.maxTokens)
+}
+
+// withdraw spends a token for a retry, and reports whether there was one to spend
+func (b *RetryBudget) withdraw() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// withDefaults returns the retry config with its zero fields set to their defaults
+func (config RetryConfig) withDefaults() RetryConfig {
+	if config.MaxRetries == 0 {
+		config.MaxRetries = defaultMaxRetries
+	}
+	if config.InitialBackoff == 0 {
+		config.InitialBackoff = defaultInitialBackoff
+	}
+	if config.MaxBackoff == 0 {
+		config.MaxBackoff = defaultMaxBackoff
+	}
+	if config.Budget == nil {
+		config.Budget = DefaultRetryBudget
+	}
+	return config
+}
+
+// retryRoundTripper retries the idempotent requests of a wrapped round tripper
+type retryRoundTripper struct {
+	config RetryConfig
+	// logger logs the retries, the package-level logger if nil
+	logger Logger
+	next   http.RoundTripper
+}
+
+// newRetryRoundTripper returns a round tripper retrying the idempotent requests of a given round tripper as configured
+func newRetryRoundTripper(config RetryConfig, logger Logger, next http.RoundTripper) http.RoundTripper {
+	return &retryRoundTripper{config: config.withDefaults(), logger: logger, next: next}
+}
+
+func (rt *retryRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.config.Budget.deposit()
+	// the body of a request that cannot be replayed cannot be retried
+	if !isIdempotent(req.Method) || (req.Body != nil && req.Body != http.NoBody && req.GetBody == nil) {
+		return rt.next.RoundTrip(req)
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := rt.next.RoundTrip(req)
+		if attempt >= rt.config.MaxRetries || !isRetriable(resp, err) {
+			return resp, err
+		}
+		if !rt.config.Budget.withdraw() {
+			loggerOrDefault(rt.logger).Debug("not retrying request to the API server, the retry budget is spent", "method", req.Method, "url", req.URL.Redacted())
+			return resp, err
+		}
+
+		delay := rt.backoff(attempt, resp)
+		loggerOrDefault(rt.logger).Debug("retrying request to the API server",
+			"method", req.Method, "url", req.URL.Redacted(), "attempt", attempt+1, "delay", delay.String(), "reason", retryReason(resp, err))
+		if resp != nil {
+			// drain the body so that the connection can be reused
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// backoff returns the delay before a given retry: the Retry-After of the response if set, or else
+// a jittered exponential backoff, between half and all of InitialBackoff doubled per retry
+func (rt *retryRoundTripper) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if delay, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			return min(delay, rt.config.MaxBackoff)
+		}
+	}
+	delay := rt.config.InitialBackoff
+	for i := 0; i < attempt && delay < rt.config.MaxBackoff; i++ {
+		delay *= 2
+	}
+	delay = min(delay, rt.config.MaxBackoff)
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// parseRetryAfter parses a Retry-After header, given either in seconds or as an HTTP date
+func parseRetryAfter(retryAfter string) (time.Duration, bool) {
+	if retryAfter == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(retryAfter); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(retryAfter); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryReason returns why a request is retried: the status of its response, or else its error
+func retryReason(resp *http.Response, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return resp.Status
+}
+
+// isRetriable reports whether a request failed with 429 Too Many Requests, a 5xx server error or a connection reset
+func isRetriable(resp *http.Response, err error) bool {
+	if err != nil {
+		return errors.Is(err, syscall.ECONNRESET)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package k8sinterface
+
+import (
+	"context"
+	"fmt"
+
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/client-go/dynamic"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/scale"
+	"k8s.io/client-go/util/retry"
+)
+
+// GetScale returns the scale subresource of a given object of a given resource, e.g. Deployments, StatefulSets, ReplicaSets
+// or custom resources whose definitions enable the scale subresource
+func (k8sAPI *KubernetesApi) GetScale(ctx context.Context, groupVersionResource *schema.GroupVersionResource, name, namespace string) (*autoscalingv1.Scale, error) {
+	scalesGetter, err := k8sAPI.scales()
+	if err != nil {
+		return nil, fmt.Errorf("failed to GET scale, resource: '%s', namespace: '%s', name: '%s', reason: %s", groupVersionResource.String(), namespace, name, err.Error())
+	}
+	s, err := scalesGetter.Scales(namespace).Get(ctx, groupVersionResource.GroupResource(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to GET scale, resource: '%s', namespace: '%s', name: '%s', reason: %w", groupVersionResource.String(), namespace, name, err)
+	}
+	return s, nil
+}
+
+// UpdateScale sets the number of replicas of a given object of a given resource through its scale subresource, and returns the scale as persisted.
+// The scale is read again and the update retried if the object changed in between
+func (k8sAPI *KubernetesApi) UpdateScale(ctx context.Context, groupVersionResource *schema.GroupVersionResource, name, namespace string, replicas int32) (*autoscalingv1.Scale, error) {
+	if replicas < 0 {
+		return nil, fmt.Errorf("failed to UPDATE scale, resource: '%s', namespace: '%s', name: '%s', reason: invalid replicas '%d'", groupVersionResource.String(), namespace, name, replicas)
+	}
+	scalesGetter, err := k8sAPI.scales()
+	if err != nil {
+		return nil, fmt.Errorf("failed to UPDATE scale, resource: '%s', namespace: '%s', name: '%s', reason: %s", groupVersionResource.String(), namespace, name, err.Error())
+	}
+
+	var updated *autoscalingv1.Scale
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		s, err := scalesGetter.Scales(namespace).Get(ctx, groupVersionResource.GroupResource(), name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		s.Spec.Replicas = replicas
+		updated, err = scalesGetter.Scales(namespace).Update(ctx, groupVersionResource.GroupResource(), s, metav1.UpdateOptions{DryRun: dryRunOptions(ctx)})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to UPDATE scale, resource: '%s', namespace: '%s', name: '%s', reason: %w", groupVersionResource.String(), namespace, name, err)
+	}
+	return updated, nil
+}
+
+// scales returns the scale client of the KubernetesApi, which resolves the versions of resources and of their scale subresources with the discovery client
+//
+// Like the other clients, it sends its requests through the shared HTTP client, throttled by a rate limiter of its own
+func (k8sAPI *KubernetesApi) scales() (scale.ScalesGetter, error) {
+	k8sAPI.scaleLock.Lock()
+	defer k8sAPI.scaleLock.Unlock()
+	if k8sAPI.scaleClient == nil {
+		if k8sAPI.K8SConfig == nil {
+			return nil, fmt.Errorf("no rest config to create the scale client with")
+		}
+		httpClient := k8sAPI.httpClient
+		if httpClient == nil {
+			var err error
+			if httpClient, err = newHTTPClient(k8sAPI.K8SConfig); err != nil {
+				return nil, err
+			}
+		}
+		// the scale client decodes the scales of any group, as scale.NewForConfig sets it up
+		scaleConfig := k8sAPI.metrics.throttled(restclient.CopyConfig(k8sAPI.K8SConfig))
+		scaleConfig.GroupVersion = &schema.GroupVersion{}
+		scaleConfig.NegotiatedSerializer = serializer.NewCodecFactory(scale.NewScaleConverter().Scheme()).WithoutConversion()
+		if scaleConfig.UserAgent == "" {
+			scaleConfig.UserAgent = restclient.DefaultKubernetesUserAgent()
+		}
+		restClient, err := restclient.RESTClientForConfigAndClient(scaleConfig, httpClient)
+		if err != nil {
+			return nil, err
+		}
+		k8sAPI.scaleClient = scale.New(restClient, k8sAPI.restMapper(), dynamic.LegacyAPIPathResolverFunc, scale.NewDiscoveryScaleKindResolver(k8sAPI.DiscoveryClient))
+	}
+	return k8sAPI.scaleClient, nil
+}
@@ -0,0 +1,222 @@
+package k8sinterface
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	goruntime "runtime"
+	"sync"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	restclient "k8s.io/client-go/rest"
+	fakescale "k8s.io/client-go/scale/fake"
+	k8stesting "k8s.io/client-go/testing"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// newFakeScaleClient returns a scale client serving a scale of given replicas, which rejects the first given number of updates as conflicting
+func newFakeScaleClient(replicas int32, conflicts int) (*fakescale.FakeScaleClient, *[]int32) {
+	client := &fakescale.FakeScaleClient{}
+	updates := []int32{}
+	client.AddReactor("get", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		getAction := action.(k8stesting.GetAction)
+		if getAction.GetName() != "nginx" {
+			return true, nil, apierrors.NewNotFound(action.GetResource().GroupResource(), getAction.GetName())
+		}
+		return true, &autoscalingv1.Scale{
+			ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: action.GetNamespace(), ResourceVersion: "1"},
+			Spec:       autoscalingv1.ScaleSpec{Replicas: replicas},
+			Status:     autoscalingv1.ScaleStatus{Replicas: replicas},
+		}, nil
+	})
+	client.AddReactor("update", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		s := action.(k8stesting.UpdateAction).GetObject().(*autoscalingv1.Scale)
+		updates = append(updates, s.Spec.Replicas)
+		if len(updates) <= conflicts {
+			return true, nil, apierrors.NewConflict(action.GetResource().GroupResource(), s.Name, assert.AnError)
+		}
+		replicas = s.Spec.Replicas
+		return true, s, nil
+	})
+	return client, &updates
+}
+
+func TestGetScale(t *testing.T) {
+	tests := []struct {
+		name         string
+		resource     schema.GroupVersionResource
+		objectName   string
+		wantReplicas int32
+		wantErr      bool
+	}{
+		{
+			name:         "Deployment",
+			resource:     schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"},
+			objectName:   "nginx",
+			wantReplicas: 2,
+		},
+		{
+			name:         "Custom resource",
+			resource:     schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"},
+			objectName:   "nginx",
+			wantReplicas: 2,
+		},
+		{
+			name:       "Missing object",
+			resource:   schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "statefulsets"},
+			objectName: "redis",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k8sAPI := NewKubernetesApiMock()
+			k8sAPI.scaleClient, _ = newFakeScaleClient(2, 0)
+
+			s, err := k8sAPI.GetScale(context.Background(), &tt.resource, tt.objectName, "default")
+			if tt.wantErr {
+				assert.True(t, apierrors.IsNotFound(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantReplicas, s.Spec.Replicas)
+		})
+	}
+}
+
+func TestUpdateScale(t *testing.T) {
+	deploymentsGVR := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	tests := []struct {
+		name        string
+		replicas    int32
+		conflicts   int
+		wantUpdates []int32
+		wantErr     bool
+	}{
+		{
+			name:        "Scaled up",
+			replicas:    5,
+			wantUpdates: []int32{5},
+		},
+		{
+			name:        "Scaled to zero",
+			replicas:    0,
+			wantUpdates: []int32{0},
+		},
+		{
+			name:        "Retried on conflict",
+			replicas:    3,
+			conflicts:   1,
+			wantUpdates: []int32{3, 3},
+		},
+		{
+			name:        "Negative replicas",
+			replicas:    -1,
+			wantUpdates: []int32{},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k8sAPI := NewKubernetesApiMock()
+			client, updates := newFakeScaleClient(2, tt.conflicts)
+			k8sAPI.scaleClient = client
+
+			s, err := k8sAPI.UpdateScale(context.Background(), &deploymentsGVR, "nginx", "default", tt.replicas)
+			assert.Equal(t, tt.wantUpdates, *updates)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.replicas, s.Spec.Replicas)
+
+			s, err = k8sAPI.GetScale(context.Background(), &deploymentsGVR, "nginx", "default")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.replicas, s.Spec.Replicas)
+		})
+	}
+}
+
+func TestScalesWithoutRestConfig(t *testing.T) {
+	k8sAPI := NewKubernetesApiMock()
+	_, err := k8sAPI.GetScale(context.Background(), &schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}, "nginx", "default")
+	assert.Error(t, err)
+}
+
+func TestScales(t *testing.T) {
+	if goruntime.GOOS == "windows" {
+		t.Skip("the exec plugin is a shell script")
+	}
+	defer tearDown()
+
+	plugin := filepath.Join(t.TempDir(), "get-token")
+	script := `#!/bin/sh
+echo '{"apiVersion":"client.authentication.k8s.io/v1","kind":"ExecCredential","status":{"token":"token"}}'
+`
+	assert.NoError(t, os.WriteFile(plugin, []byte(script), 0o755))
+
+	// an API server serving the discovery of Deployments and their scale subresource, which rejects the first scale request as unauthorized
+	var lock sync.Mutex
+	requests, scaleRequests := 0, 0
+	responses := map[string]string{
+		"/api":          `{"kind":"APIVersions","versions":["v1"],"serverAddressByClientCIDRs":[]}`,
+		"/api/v1":       `{"kind":"APIResourceList","apiVersion":"v1","groupVersion":"v1","resources":[]}`,
+		"/apis":         `{"kind":"APIGroupList","apiVersion":"v1","groups":[{"name":"apps","versions":[{"groupVersion":"apps/v1","version":"v1"}],"preferredVersion":{"groupVersion":"apps/v1","version":"v1"}}]}`,
+		"/apis/apps/v1": `{"kind":"APIResourceList","apiVersion":"v1","groupVersion":"apps/v1","resources":[{"name":"deployments","singularName":"deployment","namespaced":true,"kind":"Deployment","verbs":["get","list"]},{"name":"deployments/scale","singularName":"","namespaced":true,"group":"autoscaling","version":"v1","kind":"Scale","verbs":["get","update"]}]}`,
+		"/apis/apps/v1/namespaces/default/deployments/nginx/scale": `{"kind":"Scale","apiVersion":"autoscaling/v1","metadata":{"name":"nginx","namespace":"default"},"spec":{"replicas":2},"status":{"replicas":2}}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		requests++
+		response, found := responses[r.URL.Path]
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.URL.Path == "/apis/apps/v1/namespaces/default/deployments/nginx/scale" {
+			if scaleRequests++; scaleRequests == 1 {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+		}
+		w.Header().Set("Content-Type", runtime.ContentTypeJSON)
+		w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	restConfig := &restclient.Config{
+		Host: server.URL,
+		ExecProvider: &clientcmdapi.ExecConfig{
+			Command:         plugin,
+			APIVersion:      "client.authentication.k8s.io/v1",
+			InteractiveMode: clientcmdapi.NeverExecInteractiveMode,
+		},
+	}
+	registry := prometheus.NewRegistry()
+	k8sAPI, err := NewKubernetesApiForConfig(restConfig, ClientConfig{MetricsRegisterer: registry})
+	assert.NoError(t, err)
+
+	// the scale client shares the HTTP client retrying unauthorized requests
+	s, err := k8sAPI.GetScale(context.Background(), &schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}, "nginx", "default")
+	assert.NoError(t, err)
+	if assert.NotNil(t, s) {
+		assert.Equal(t, int32(2), s.Spec.Replicas)
+	}
+
+	// and waits for a rate limiter timed by the metrics, once for every request but the retried one
+	lock.Lock()
+	defer lock.Unlock()
+	assert.Equal(t, 2, scaleRequests)
+	assert.Equal(t, uint64(requests-1), rateLimiterWaits(t, registry))
+}
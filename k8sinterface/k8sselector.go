@@ -0,0 +1,148 @@
+package k8sinterface
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/kubescape/k8s-interface/workloadinterface"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/selection"
+)
+
+// fieldPathRegex matches the field paths of field selectors, e.g. "status.phase"
+var fieldPathRegex = regexp.MustCompile(`^[A-Za-z0-9_.\-]+$`)
+
+// Selector builds label and field selectors, e.g.
+//
+//	NewSelector().Eq("app", "nginx").Exists("tier").FieldEq("status.phase", "Running")
+//
+// Keys and values are validated as they are added, the first invalid one is returned as an error when the selectors are compiled
+type Selector struct {
+	labelRequirements []labels.Requirement
+	fieldSelectors    []fields.Selector
+	err               error
+}
+
+// NewSelector returns an empty Selector, which selects everything
+func NewSelector() *Selector {
+	return &Selector{}
+}
+
+// Eq selects the resources with a given label set to a given value
+func (s *Selector) Eq(key, value string) *Selector {
+	return s.addLabelRequirement(key, selection.Equals, value)
+}
+
+// NotEq selects the resources without a given label set to a given value, including the ones without the label
+func (s *Selector) NotEq(key, value string) *Selector {
+	return s.addLabelRequirement(key, selection.NotEquals, value)
+}
+
+// In selects the resources with a given label set to any of given values
+func (s *Selector) In(key string, values ...string) *Selector {
+	return s.addLabelRequirement(key, selection.In, values...)
+}
+
+// NotIn selects the resources without a given label set to any of given values, including the ones without the label
+func (s *Selector) NotIn(key string, values ...string) *Selector {
+	return s.addLabelRequirement(key, selection.NotIn, values...)
+}
+
+// Exists selects the resources with a given label, whatever its value
+func (s *Selector) Exists(key string) *Selector {
+	return s.addLabelRequirement(key, selection.Exists)
+}
+
+// DoesNotExist selects the resources without a given label
+func (s *Selector) DoesNotExist(key string) *Selector {
+	return s.addLabelRequirement(key, selection.DoesNotExist)
+}
+
+// FieldEq selects the resources with a given field set to a given value, e.g. FieldEq("status.phase", "Running").
+// Only some fields of every kind can be selected on, as set by the API server
+func (s *Selector) FieldEq(field, value string) *Selector {
+	return s.addFieldSelector(field, fields.OneTermEqualSelector(field, value))
+}
+
+// FieldNotEq selects the resources without a given field set to a given value
+func (s *Selector) FieldNotEq(field, value string) *Selector {
+	return s.addFieldSelector(field, fields.OneTermNotEqualSelector(field, value))
+}
+
+func (s *Selector) addLabelRequirement(key string, operator selection.Operator, values ...string) *Selector {
+	if s.err != nil {
+		return s
+	}
+	requirement, err := labels.NewRequirement(key, operator, values)
+	if err != nil {
+		s.err = fmt.Errorf("invalid label selector, key: '%s', reason: %s", key, err.Error())
+		return s
+	}
+	s.labelRequirements = append(s.labelRequirements, *requirement)
+	return s
+}
+
+func (s *Selector) addFieldSelector(field string, selector fields.Selector) *Selector {
+	if s.err != nil {
+		return s
+	}
+	if !fieldPathRegex.MatchString(field) {
+		s.err = fmt.Errorf("invalid field selector, field: '%s'", field)
+		return s
+	}
+	s.fieldSelectors = append(s.fieldSelectors, selector)
+	return s
+}
+
+// LabelSelector compiles the label selector, empty if no label is selected on
+func (s *Selector) LabelSelector() (string, error) {
+	if s.err != nil {
+		return "", s.err
+	}
+	return labels.NewSelector().Add(s.labelRequirements...).String(), nil
+}
+
+// FieldSelector compiles the field selector, empty if no field is selected on
+func (s *Selector) FieldSelector() (string, error) {
+	if s.err != nil {
+		return "", s.err
+	}
+	if len(s.fieldSelectors) == 0 {
+		return "", nil
+	}
+	return fields.AndSelectors(s.fieldSelectors...).String(), nil
+}
+
+// ListOptions returns list options with the compiled label and field selectors, for ListResourcesPaged, NewResourceIterator and the like
+func (s *Selector) ListOptions() (metav1.ListOptions, error) {
+	labelSelector, err := s.LabelSelector()
+	if err != nil {
+		return metav1.ListOptions{}, err
+	}
+	fieldSelector, err := s.FieldSelector()
+	if err != nil {
+		return metav1.ListOptions{}, err
+	}
+	return metav1.ListOptions{LabelSelector: labelSelector, FieldSelector: fieldSelector}, nil
+}
+
+// ListWorkloadsWithSelector works like ListWorkloadsWithContext, with the resources selected by a Selector
+func (k8sAPI *KubernetesApi) ListWorkloadsWithSelector(ctx context.Context, groupVersionResource *schema.GroupVersionResource, namespace string, selector *Selector) ([]IWorkload, error) {
+	listOptions, err := selector.ListOptions()
+	if err != nil {
+		return nil, err
+	}
+	uList, err := k8sAPI.ResourceInterface(groupVersionResource, namespace).List(ctx, listOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to LIST resources, reason: %s", err.Error())
+	}
+	workloads := make([]IWorkload, len(uList.Items))
+	for i := range uList.Items {
+		workloads[i] = workloadinterface.NewWorkloadObj(uList.Items[i].Object)
+	}
+	return workloads, nil
+}
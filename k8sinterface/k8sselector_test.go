@@ -0,0 +1,114 @@
+package k8sinterface
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func TestSelector(t *testing.T) {
+	tests := []struct {
+		name              string
+		selector          *Selector
+		wantLabelSelector string
+		wantFieldSelector string
+		wantErr           bool
+	}{
+		{
+			name:     "Empty",
+			selector: NewSelector(),
+		},
+		{
+			name:              "Label operators",
+			selector:          NewSelector().Eq("app", "nginx").NotEq("tier", "db").In("env", "prod", "dev").NotIn("zone", "a").Exists("team").DoesNotExist("canary"),
+			wantLabelSelector: "app=nginx,!canary,env in (dev,prod),team,tier!=db,zone notin (a)",
+		},
+		{
+			name:              "Field selectors",
+			selector:          NewSelector().FieldEq("status.phase", "Running").FieldNotEq("spec.nodeName", "node-1"),
+			wantFieldSelector: "status.phase=Running,spec.nodeName!=node-1",
+		},
+		{
+			name:              "Field values escaped",
+			selector:          NewSelector().FieldEq("metadata.name", "a,b=c"),
+			wantFieldSelector: `metadata.name=a\,b\=c`,
+		},
+		{
+			name:              "Labels and fields",
+			selector:          NewSelector().Eq("app", "nginx").FieldEq("metadata.namespace", "default"),
+			wantLabelSelector: "app=nginx",
+			wantFieldSelector: "metadata.namespace=default",
+		},
+		{
+			name:     "Invalid label key",
+			selector: NewSelector().Eq("app", "nginx").Eq("not a key", "nginx"),
+			wantErr:  true,
+		},
+		{
+			name:     "Invalid label value",
+			selector: NewSelector().Eq("app", "not a value"),
+			wantErr:  true,
+		},
+		{
+			name:     "In without values",
+			selector: NewSelector().In("app"),
+			wantErr:  true,
+		},
+		{
+			name:     "Invalid field",
+			selector: NewSelector().FieldEq("status.phase=Running", ""),
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			labelSelector, err := tt.selector.LabelSelector()
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.wantLabelSelector, labelSelector)
+
+			fieldSelector, err := tt.selector.FieldSelector()
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.wantFieldSelector, fieldSelector)
+
+			listOptions, err := tt.selector.ListOptions()
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.wantLabelSelector, listOptions.LabelSelector)
+			assert.Equal(t, tt.wantFieldSelector, listOptions.FieldSelector)
+		})
+	}
+}
+
+func TestListWorkloadsWithSelector(t *testing.T) {
+	k8sAPI := NewKubernetesApiMock()
+	objects := []runtime.Object{}
+	for name, app := range map[string]string{"nginx-1": "nginx", "nginx-2": "nginx", "redis": "redis"} {
+		objects = append(objects, &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": "default",
+				"labels":    map[string]interface{}{"app": app},
+			},
+		}})
+	}
+	podsGVR := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	k8sAPI.DynamicClient = dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{podsGVR: "PodList"}, objects...)
+
+	workloads, err := k8sAPI.ListWorkloadsWithSelector(context.Background(), &podsGVR, "default", NewSelector().In("app", "nginx", "apache"))
+	assert.NoError(t, err)
+	names := []string{}
+	for _, workload := range workloads {
+		names = append(names, workload.GetName())
+	}
+	assert.ElementsMatch(t, []string{"nginx-1", "nginx-2"}, names)
+
+	_, err = k8sAPI.ListWorkloadsWithSelector(context.Background(), &podsGVR, "default", NewSelector().Exists("-"))
+	assert.Error(t, err)
+}
@@ -33,6 +33,11 @@ func SetLabel(labels map[string]string, key string, val bool) {
 }
 
 func (k8sAPI *KubernetesApi) ListPods(namespace string, podLabels map[string]string, fieldSelector string) (*corev1.PodList, error) {
+	return k8sAPI.ListPodsWithContext(k8sAPI.Context, namespace, podLabels, fieldSelector)
+}
+
+// ListPodsWithContext works like ListPods, but makes the API call with a given context
+func (k8sAPI *KubernetesApi) ListPodsWithContext(ctx context.Context, namespace string, podLabels map[string]string, fieldSelector string) (*corev1.PodList, error) {
 	listOptions := metav1.ListOptions{}
 	if len(podLabels) > 0 {
 		set := labels.Set(podLabels)
@@ -43,7 +48,7 @@ func (k8sAPI *KubernetesApi) ListPods(namespace string, podLabels map[string]str
 		listOptions.FieldSelector = fieldSelector
 	}
 
-	pods, err := k8sAPI.KubernetesClient.CoreV1().Pods(namespace).List(context.Background(), listOptions)
+	pods, err := k8sAPI.KubernetesClient.CoreV1().Pods(namespace).List(ctx, listOptions)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,55 @@
+package k8sinterface
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kubescape/k8s-interface/workloadinterface"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// UpdateStatus updates the status of a given workload, and returns the workload as persisted
+//
+// If the resource has a status subresource, only the status is updated, so that the spec is neither overwritten nor validated,
+// e.g. against immutable fields. Otherwise, the whole workload is updated, like with UpdateWorkloadWithContext
+func (k8sAPI *KubernetesApi) UpdateStatus(ctx context.Context, workload IWorkload) (IWorkload, error) {
+	groupVersionResource, err := k8sAPI.getGroupVersionResource(workload.GetKind())
+	if err != nil {
+		return nil, err
+	}
+	obj, err := workload.ToUnstructured()
+	if err != nil {
+		return nil, err
+	}
+	hasStatus, err := k8sAPI.hasSubresource(groupVersionResource, "status")
+	if err != nil {
+		return nil, fmt.Errorf("failed to UPDATE resource status, workload: '%s', reason: %s", workload.ToString(), err.Error())
+	}
+
+	resourceInterface := k8sAPI.ResourceInterface(&groupVersionResource, workload.GetNamespace())
+	updateOptions := metav1.UpdateOptions{DryRun: dryRunOptions(ctx)}
+	if hasStatus {
+		obj, err = resourceInterface.UpdateStatus(ctx, obj, updateOptions)
+	} else {
+		obj, err = resourceInterface.Update(ctx, obj, updateOptions)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to UPDATE resource status, workload: '%s', reason: %w", workload.ToString(), err)
+	}
+	return workloadinterface.NewWorkloadObj(obj.Object), nil
+}
+
+// hasSubresource returns true if a given resource has a given subresource, e.g. "status" or "scale"
+func (k8sAPI *KubernetesApi) hasSubresource(groupVersionResource schema.GroupVersionResource, subresource string) (bool, error) {
+	resourceList, err := k8sAPI.serverResources(groupVersionResource.GroupVersion())
+	if err != nil {
+		return false, err
+	}
+	for _, apiResource := range resourceList.APIResources {
+		if apiResource.Name == groupVersionResource.Resource+"/"+subresource {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,67 @@
+package k8sinterface
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kubescape/k8s-interface/workloadinterface"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	discoveryfake "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubetesting "k8s.io/client-go/testing"
+)
+
+func TestUpdateStatus(t *testing.T) {
+	newObject := func(apiVersion, kind string) map[string]interface{} {
+		return map[string]interface{}{
+			"apiVersion": apiVersion,
+			"kind":       kind,
+			"metadata":   map[string]interface{}{"name": "nginx", "namespace": "default"},
+		}
+	}
+	tests := []struct {
+		name            string
+		obj             map[string]interface{}
+		wantSubresource string
+	}{
+		{
+			name:            "Status subresource",
+			obj:             newObject("apps/v1", "Deployment"),
+			wantSubresource: "status",
+		},
+		{
+			name: "No status subresource",
+			obj:  newObject("v1", "ConfigMap"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k8sAPI := NewKubernetesApiMock()
+			k8sAPI.DiscoveryClient = &discoveryfake.FakeDiscovery{Fake: &kubetesting.Fake{Resources: []*metav1.APIResourceList{
+				{
+					GroupVersion: "v1",
+					APIResources: []metav1.APIResource{{Name: "configmaps", Kind: "ConfigMap", Namespaced: true}},
+				},
+				{
+					GroupVersion: "apps/v1",
+					APIResources: []metav1.APIResource{{Name: "deployments", Kind: "Deployment", Namespaced: true}, {Name: "deployments/status", Kind: "Deployment", Namespaced: true}},
+				},
+			}}}
+			client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), &unstructured.Unstructured{Object: tt.obj})
+			k8sAPI.DynamicClient = client
+
+			obj := runtime.DeepCopyJSON(tt.obj)
+			obj["status"] = map[string]interface{}{"observedGeneration": int64(1)}
+			updated, err := k8sAPI.UpdateStatus(context.Background(), workloadinterface.NewWorkloadObj(obj))
+			assert.NoError(t, err)
+			assert.Equal(t, map[string]interface{}{"observedGeneration": int64(1)}, updated.GetObject()["status"])
+
+			actions := client.Actions()
+			assert.Equal(t, "update", actions[len(actions)-1].GetVerb())
+			assert.Equal(t, tt.wantSubresource, actions[len(actions)-1].GetSubresource())
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package k8sinterface
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	restclient "k8s.io/client-go/rest"
+)
+
+// WithTokenRotation returns a copy of a given rest config that authenticates with the bearer token in a given file,
+// reloaded within tokenFileCheckInterval of the file changing, so that long-lived processes keep working once a bound service account token is rotated
+//
+// It is opt-in: configs with a BearerTokenFile, such as the in-cluster config, already reload the file through client-go, at most a minute late
+func WithTokenRotation(restConfig *restclient.Config, tokenFile string) *restclient.Config {
+	restConfig = restclient.CopyConfig(restConfig)
+	// the wrapping round tripper runs after the client-go authentication, which sets the token it last read from the file, up to a minute old
+	restConfig.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return newTokenFileRoundTripper(tokenFile, tokenFileCheckInterval, rt)
+	})
+	return restConfig
+}
+
+// tokenFileCheckInterval is how long WithTokenRotation uses a token before checking whether its file changed
+var tokenFileCheckInterval = 5 * time.Second
+
+// tokenFileRoundTripper authenticates requests with the bearer token in a file, checking at most once per checkInterval whether
+// the modification time of the file changed and reloading it if so. Requests authenticated otherwise than with a bearer token are left as is
+type tokenFileRoundTripper struct {
+	tokenFile     string
+	checkInterval time.Duration
+	next          http.RoundTripper
+
+	mu      sync.RWMutex
+	token   string
+	modTime time.Time
+	checked time.Time
+}
+
+func newTokenFileRoundTripper(tokenFile string, checkInterval time.Duration, next http.RoundTripper) http.RoundTripper {
+	return &tokenFileRoundTripper{tokenFile: tokenFile, checkInterval: checkInterval, next: next}
+}
+
+func (rt *tokenFileRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if auth := req.Header.Get("Authorization"); auth != "" && !strings.HasPrefix(auth, "Bearer ") {
+		return rt.next.RoundTrip(req)
+	}
+	token, err := rt.loadToken()
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+	return rt.next.RoundTrip(req)
+}
+
+// loadToken returns the token in the token file, reading the file again only if it changed since it was last read.
+// While the file cannot be read, e.g. in the middle of a rotation, the last token read is returned
+func (rt *tokenFileRoundTripper) loadToken() (string, error) {
+	// the file is not checked again within the check interval, so most requests only take the read lock
+	rt.mu.RLock()
+	token, fresh := rt.token, time.Since(rt.checked) < rt.checkInterval
+	rt.mu.RUnlock()
+	if token != "" && fresh {
+		return token, nil
+	}
+
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	if rt.token != "" && time.Since(rt.checked) < rt.checkInterval {
+		return rt.token, nil
+	}
+	rt.checked = time.Now()
+
+	// os.Stat follows symlinks, so a rotation swapping the symlinked directory of a projected volume is seen as a change
+	info, err := os.Stat(rt.tokenFile)
+	if err == nil && rt.token != "" && info.ModTime().Equal(rt.modTime) {
+		return rt.token, nil
+	}
+	if err == nil {
+		var content []byte
+		if content, err = os.ReadFile(rt.tokenFile); err == nil {
+			if token := strings.TrimSpace(string(content)); token != "" {
+				rt.token, rt.modTime = token, info.ModTime()
+				return rt.token, nil
+			}
+			err = fmt.Errorf("token file is empty")
+		}
+	}
+	if rt.token != "" {
+		return rt.token, nil
+	}
+	return "", fmt.Errorf("failed to read token file: '%s', reason: %s", rt.tokenFile, err.Error())
+}
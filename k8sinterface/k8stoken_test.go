@@ -0,0 +1,143 @@
+package k8sinterface
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	restclient "k8s.io/client-go/rest"
+)
+
+// tokenRotationHarness serves requests recording their Authorization header, from a client reading a token file
+// laid out like a projected service account token: a "token" symlink into a "..data" symlink to a timestamped directory
+type tokenRotationHarness struct {
+	t         *testing.T
+	dir       string
+	tokenFile string
+	rotations int
+
+	server *httptest.Server
+	client *http.Client
+	auth   string
+}
+
+func newTokenRotationHarness(t *testing.T, token string) *tokenRotationHarness {
+	dir := t.TempDir()
+	h := &tokenRotationHarness{t: t, dir: dir, tokenFile: filepath.Join(dir, "token")}
+	h.rotate(token)
+	assert.NoError(t, os.Symlink(filepath.Join("..data", "token"), h.tokenFile))
+
+	h.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.auth = r.Header.Get("Authorization")
+	}))
+	t.Cleanup(h.server.Close)
+	h.client = &http.Client{Transport: newTokenFileRoundTripper(h.tokenFile, 0, http.DefaultTransport)}
+	return h
+}
+
+// rotate writes a new token the way the kubelet does: into a new directory, then swaps the "..data" symlink to it
+func (h *tokenRotationHarness) rotate(token string) {
+	h.rotations++
+	dataDir := filepath.Join(h.dir, "..data_"+time.Now().Format("2006_01_02_15_04_05")+"_"+string(rune('a'+h.rotations)))
+	assert.NoError(h.t, os.Mkdir(dataDir, 0o755))
+	assert.NoError(h.t, os.WriteFile(filepath.Join(dataDir, "token"), []byte(token+"\n"), 0o600))
+	// make sure the rotated file is seen as modified, whatever the resolution of the file system clock
+	modTime := time.Now().Add(time.Duration(h.rotations) * time.Second)
+	assert.NoError(h.t, os.Chtimes(filepath.Join(dataDir, "token"), modTime, modTime))
+
+	tmpLink := filepath.Join(h.dir, "..data_tmp")
+	assert.NoError(h.t, os.Symlink(filepath.Base(dataDir), tmpLink))
+	assert.NoError(h.t, os.Rename(tmpLink, filepath.Join(h.dir, "..data")))
+}
+
+// request makes a request, and returns the Authorization header the server received
+func (h *tokenRotationHarness) request() (string, error) {
+	h.auth = ""
+	resp, err := h.client.Get(h.server.URL)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	return h.auth, nil
+}
+
+func TestTokenRotation(t *testing.T) {
+	h := newTokenRotationHarness(t, "token-1")
+
+	auth, err := h.request()
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer token-1", auth)
+
+	h.rotate("token-2")
+	auth, err = h.request()
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer token-2", auth)
+
+	// while the token file is missing, the last token read is used
+	assert.NoError(t, os.Remove(filepath.Join(h.dir, "..data")))
+	auth, err = h.request()
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer token-2", auth)
+
+	// bearer tokens set from a stale read of the file are replaced, other credentials are left as is
+	for auth, want := range map[string]string{"Bearer token-1": "Bearer token-2", "Basic YWRtaW46YWRtaW4=": "Basic YWRtaW46YWRtaW4="} {
+		req, err := http.NewRequest(http.MethodGet, h.server.URL, nil)
+		assert.NoError(t, err)
+		req.Header.Set("Authorization", auth)
+		resp, err := h.client.Do(req)
+		assert.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, want, h.auth)
+	}
+}
+
+func TestTokenRotationCheckInterval(t *testing.T) {
+	h := newTokenRotationHarness(t, "token-1")
+	h.client = &http.Client{Transport: newTokenFileRoundTripper(h.tokenFile, time.Hour, http.DefaultTransport)}
+
+	auth, err := h.request()
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer token-1", auth)
+
+	// the file is not checked again within the check interval
+	h.rotate("token-2")
+	auth, err = h.request()
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer token-1", auth)
+}
+
+func TestTokenRotationMissingFile(t *testing.T) {
+	client := &http.Client{Transport: newTokenFileRoundTripper(filepath.Join(t.TempDir(), "token"), 0, http.DefaultTransport)}
+	_, err := client.Get("http://127.0.0.1:1")
+	assert.ErrorContains(t, err, "failed to read token file")
+}
+
+func TestWithTokenRotation(t *testing.T) {
+	restConfig := &restclient.Config{Host: "https://127.0.0.1:6443", BearerTokenFile: "/var/run/secrets/kubernetes.io/serviceaccount/token"}
+
+	rotating := WithTokenRotation(restConfig, restConfig.BearerTokenFile)
+	assert.NotNil(t, rotating.WrapTransport)
+	assert.Equal(t, restConfig.BearerTokenFile, rotating.BearerTokenFile)
+	assert.Nil(t, restConfig.WrapTransport)
+
+	// through the client-go transport, which caches the token of the file for a minute
+	defer func(interval time.Duration) { tokenFileCheckInterval = interval }(tokenFileCheckInterval)
+	tokenFileCheckInterval = 0
+	h := newTokenRotationHarness(t, "token-1")
+	httpClient, err := restclient.HTTPClientFor(WithTokenRotation(&restclient.Config{Host: h.server.URL, BearerTokenFile: h.tokenFile}, h.tokenFile))
+	assert.NoError(t, err)
+	h.client = httpClient
+
+	auth, err := h.request()
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer token-1", auth)
+
+	h.rotate("token-2")
+	auth, err = h.request()
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer token-2", auth)
+}
@@ -0,0 +1,159 @@
+package k8sinterface
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the name of the tracer of the spans of the requests to the API server
+const tracerName = "github.com/kubescape/k8s-interface/k8sinterface"
+
+// tracingRoundTripper records a client span per request to the API server, with its verb, resource, namespace and response code,
+// and propagates the trace context to the API server
+type tracingRoundTripper struct {
+	// tracerProvider provides the tracer, the global one if nil, so that a provider installed with otel.SetTracerProvider later on is used
+	tracerProvider trace.TracerProvider
+	next           http.RoundTripper
+}
+
+func newTracingRoundTripper(tracerProvider trace.TracerProvider, next http.RoundTripper) *tracingRoundTripper {
+	return &tracingRoundTripper{tracerProvider: tracerProvider, next: next}
+}
+
+func (rt *tracingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	tracerProvider := rt.tracerProvider
+	if tracerProvider == nil {
+		tracerProvider = otel.GetTracerProvider()
+	}
+	info := parseRequestInfo(req)
+	attributes := []attribute.KeyValue{
+		attribute.String("http.method", req.Method),
+		attribute.String("http.url", req.URL.Redacted()),
+		attribute.String("k8s.verb", info.Verb),
+	}
+	if info.Resource != "" {
+		attributes = append(attributes,
+			attribute.String("k8s.group", info.Group),
+			attribute.String("k8s.version", info.Version),
+			attribute.String("k8s.resource", info.Resource),
+		)
+	}
+	if info.Subresource != "" {
+		attributes = append(attributes, attribute.String("k8s.subresource", info.Subresource))
+	}
+	if info.Namespace != "" {
+		attributes = append(attributes, attribute.String("k8s.namespace", info.Namespace))
+	}
+
+	ctx, span := tracerProvider.Tracer(tracerName).Start(req.Context(), info.spanName(), trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attributes...))
+	defer span.End()
+	req = req.Clone(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := rt.next.RoundTrip(req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return resp, err
+	}
+	span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+	return resp, nil
+}
+
+// requestInfo is the Kubernetes verb and resource of a request to the API server.
+// The resource is empty for non-resource requests, e.g. /version, whose path is set instead
+type requestInfo struct {
+	Path        string
+	Verb        string
+	Group       string
+	Version     string
+	Resource    string
+	Subresource string
+	Namespace   string
+	Name        string
+}
+
+// spanName returns the name of the span of the request, e.g. "list apps/v1 deployments"
+func (info requestInfo) spanName() string {
+	if info.Resource == "" {
+		return info.Verb + " " + info.Path
+	}
+	resource := info.Resource
+	if info.Subresource != "" {
+		resource += "/" + info.Subresource
+	}
+	if info.Group == "" {
+		return fmt.Sprintf("%s %s %s", info.Verb, info.Version, resource)
+	}
+	return fmt.Sprintf("%s %s/%s %s", info.Verb, info.Group, info.Version, resource)
+}
+
+// namespaceSubresources are the subresources of namespaces, not to be mistaken for the resources of a namespace
+var namespaceSubresources = map[string]bool{"status": true, "finalize": true}
+
+// parseRequestInfo returns the verb and resource of a request to the API server, parsed from its method and path the way the API server does,
+// e.g. GET /apis/apps/v1/namespaces/default/deployments is the list verb of the apps/v1 deployments in the default namespace
+func parseRequestInfo(req *http.Request) requestInfo {
+	info := requestInfo{Verb: strings.ToLower(req.Method)}
+	parts := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	switch {
+	case len(parts) >= 3 && parts[0] == "api":
+		info.Version, parts = parts[1], parts[2:]
+	case len(parts) >= 4 && parts[0] == "apis":
+		info.Group, info.Version, parts = parts[1], parts[2], parts[3:]
+	default:
+		info.Path = req.URL.Path
+		return info
+	}
+
+	if parts[0] == "namespaces" && len(parts) > 1 {
+		info.Namespace = parts[1]
+		if len(parts) > 2 && !namespaceSubresources[parts[2]] {
+			parts = parts[2:]
+		}
+	}
+	info.Resource = parts[0]
+	if len(parts) > 1 {
+		info.Name = parts[1]
+	}
+	if len(parts) > 2 {
+		info.Subresource = parts[2]
+	}
+	if info.Resource == "namespaces" {
+		info.Namespace = ""
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		switch {
+		case req.URL.Query().Get("watch") == "true" || req.URL.Query().Get("watch") == "1":
+			info.Verb = "watch"
+		case info.Name == "":
+			info.Verb = "list"
+		default:
+			info.Verb = "get"
+		}
+	case http.MethodPost:
+		info.Verb = "create"
+	case http.MethodPut:
+		info.Verb = "update"
+	case http.MethodPatch:
+		info.Verb = "patch"
+	case http.MethodDelete:
+		info.Verb = "delete"
+		if info.Name == "" {
+			info.Verb = "deletecollection"
+		}
+	}
+	return info
+}
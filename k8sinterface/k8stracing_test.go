@@ -0,0 +1,147 @@
+package k8sinterface
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	restclient "k8s.io/client-go/rest"
+)
+
+func TestParseRequestInfo(t *testing.T) {
+	tests := []struct {
+		method   string
+		url      string
+		want     requestInfo
+		spanName string
+	}{
+		{
+			method:   http.MethodGet,
+			url:      "/api/v1/pods",
+			want:     requestInfo{Verb: "list", Version: "v1", Resource: "pods"},
+			spanName: "list v1 pods",
+		},
+		{
+			method:   http.MethodGet,
+			url:      "/apis/apps/v1/namespaces/default/deployments?watch=true",
+			want:     requestInfo{Verb: "watch", Group: "apps", Version: "v1", Resource: "deployments", Namespace: "default"},
+			spanName: "watch apps/v1 deployments",
+		},
+		{
+			method:   http.MethodPut,
+			url:      "/apis/apps/v1/namespaces/default/deployments/nginx/scale",
+			want:     requestInfo{Verb: "update", Group: "apps", Version: "v1", Resource: "deployments", Subresource: "scale", Namespace: "default", Name: "nginx"},
+			spanName: "update apps/v1 deployments/scale",
+		},
+		{
+			method:   http.MethodGet,
+			url:      "/api/v1/namespaces/kube-system",
+			want:     requestInfo{Verb: "get", Version: "v1", Resource: "namespaces", Name: "kube-system"},
+			spanName: "get v1 namespaces",
+		},
+		{
+			method:   http.MethodPut,
+			url:      "/api/v1/namespaces/kube-system/finalize",
+			want:     requestInfo{Verb: "update", Version: "v1", Resource: "namespaces", Subresource: "finalize", Name: "kube-system"},
+			spanName: "update v1 namespaces/finalize",
+		},
+		{
+			method:   http.MethodDelete,
+			url:      "/api/v1/namespaces/default/pods",
+			want:     requestInfo{Verb: "deletecollection", Version: "v1", Resource: "pods", Namespace: "default"},
+			spanName: "deletecollection v1 pods",
+		},
+		{
+			method:   http.MethodGet,
+			url:      "/version",
+			want:     requestInfo{Verb: "get", Path: "/version"},
+			spanName: "get /version",
+		},
+		{
+			method:   http.MethodGet,
+			url:      "/apis/apps/v1",
+			want:     requestInfo{Verb: "get", Path: "/apis/apps/v1"},
+			spanName: "get /apis/apps/v1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.url, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.url, nil)
+			info := parseRequestInfo(req)
+			assert.Equal(t, tt.want, info)
+			assert.Equal(t, tt.spanName, info.spanName())
+		})
+	}
+}
+
+func TestTracing(t *testing.T) {
+	defer tearDown()
+
+	var lock sync.Mutex
+	traceparents := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		traceparents = append(traceparents, r.Header.Get("Traceparent"))
+		lock.Unlock()
+		w.Header().Set("Content-Type", runtime.ContentTypeJSON)
+		if r.URL.Path == "/api/v1/namespaces/kube-system/secrets" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"Forbidden","code":403}`))
+			return
+		}
+		w.Write([]byte(`{"kind":"PodList","apiVersion":"v1","items":[]}`))
+	}))
+	defer server.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	k8sAPI, err := NewKubernetesApiForConfig(&restclient.Config{Host: server.URL}, ClientConfig{TracerProvider: tracerProvider})
+	assert.NoError(t, err)
+
+	ctx, parent := tracerProvider.Tracer("test").Start(context.Background(), "scan")
+	_, err = k8sAPI.KubernetesClient.CoreV1().Pods("default").List(ctx, metav1.ListOptions{})
+	assert.NoError(t, err)
+	_, err = k8sAPI.KubernetesClient.CoreV1().Secrets("kube-system").List(ctx, metav1.ListOptions{})
+	assert.Error(t, err)
+	parent.End()
+
+	spans := recorder.Ended()
+	if !assert.Len(t, spans, 3) {
+		return
+	}
+
+	listPods := spans[0]
+	assert.Equal(t, "list v1 pods", listPods.Name())
+	assert.Equal(t, trace.SpanKindClient, listPods.SpanKind())
+	assert.Equal(t, parent.SpanContext().SpanID(), listPods.Parent().SpanID())
+	assert.Equal(t, codes.Unset, listPods.Status().Code)
+	assert.Subset(t, listPods.Attributes(), []attribute.KeyValue{
+		attribute.String("http.method", http.MethodGet),
+		attribute.String("k8s.verb", "list"),
+		attribute.String("k8s.group", ""),
+		attribute.String("k8s.version", "v1"),
+		attribute.String("k8s.resource", "pods"),
+		attribute.String("k8s.namespace", "default"),
+		attribute.Int("http.status_code", http.StatusOK),
+	})
+
+	listSecrets := spans[1]
+	assert.Equal(t, "list v1 secrets", listSecrets.Name())
+	assert.Equal(t, codes.Error, listSecrets.Status().Code)
+	assert.Contains(t, listSecrets.Attributes(), attribute.Int("http.status_code", http.StatusForbidden))
+
+	// the trace context is propagated to the API server only if a propagator is set with otel.SetTextMapPropagator
+	lock.Lock()
+	defer lock.Unlock()
+	assert.Equal(t, []string{"", ""}, traceparents)
+}
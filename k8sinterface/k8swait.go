@@ -0,0 +1,184 @@
+package k8sinterface
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kubescape/k8s-interface/workloadinterface"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// WaitPollInterval is how often WaitForResourceCondition gets the awaited object, in case the watch misses a change or cannot be established
+var WaitPollInterval = 2 * time.Second
+
+// ResourcePredicate reports whether an object meets a condition. It returns an error if the object can no longer meet it, e.g. a failed Job
+type ResourcePredicate func(workload IWorkload) (bool, error)
+
+// WaitForResourceCondition waits until a given object of a given resource meets a given predicate, and returns the object.
+// Set a timeout on the context to bound the wait
+//
+// The object is watched, and polled every WaitPollInterval in case the watch misses a change or cannot be established, e.g. if watching is forbidden.
+// The object may not exist yet, it is awaited too
+func (k8sAPI *KubernetesApi) WaitForResourceCondition(ctx context.Context, groupVersionResource *schema.GroupVersionResource, name, namespace string, predicate ResourcePredicate) (IWorkload, error) {
+	resourceInterface := k8sAPI.ResourceInterface(groupVersionResource, namespace)
+	waitErr := func(err error) error {
+		return fmt.Errorf("failed to wait for resource condition, resource: '%s', namespace: '%s', name: '%s', reason: %w", groupVersionResource.String(), namespace, name, err)
+	}
+	startWatch := func() watch.Interface {
+		watcher, err := resourceInterface.Watch(ctx, metav1.ListOptions{FieldSelector: fields.OneTermEqualSelector("metadata.name", name).String()})
+		if err != nil {
+			return nil
+		}
+		return watcher
+	}
+	check := func() (IWorkload, bool, error) {
+		obj, err := resourceInterface.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, false, nil
+			}
+			return nil, false, err
+		}
+		workload := workloadinterface.NewWorkloadObj(obj.Object)
+		met, err := predicate(workload)
+		return workload, met, err
+	}
+
+	// the watch is started before the first check, so that no change is missed in between
+	watcher := startWatch()
+	defer func() {
+		if watcher != nil {
+			watcher.Stop()
+		}
+	}()
+	workload, met, err := check()
+	if err != nil {
+		return workload, waitErr(err)
+	}
+	if met {
+		return workload, nil
+	}
+
+	ticker := time.NewTicker(WaitPollInterval)
+	defer ticker.Stop()
+	for {
+		var events <-chan watch.Event
+		if watcher != nil {
+			events = watcher.ResultChan()
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, waitErr(ctx.Err())
+		case <-ticker.C:
+			if watcher == nil {
+				watcher = startWatch()
+			}
+			workload, met, err := check()
+			if err != nil {
+				return workload, waitErr(err)
+			}
+			if met {
+				return workload, nil
+			}
+		case event, ok := <-events:
+			// a closed or failed watch is restarted on the next poll
+			if !ok || event.Type == watch.Error {
+				watcher.Stop()
+				watcher = nil
+				continue
+			}
+			if event.Type != watch.Added && event.Type != watch.Modified {
+				continue
+			}
+			obj, ok := event.Object.(*unstructured.Unstructured)
+			if !ok || obj.GetName() != name {
+				continue
+			}
+			workload := workloadinterface.NewWorkloadObj(obj.Object)
+			met, err := predicate(workload)
+			if err != nil {
+				return workload, waitErr(err)
+			}
+			if met {
+				return workload, nil
+			}
+		}
+	}
+}
+
+// PodReady is met once a Pod is ready. It fails once the Pod terminated
+func PodReady(workload IWorkload) (bool, error) {
+	phase, _, _ := unstructured.NestedString(workload.GetObject(), "status", "phase")
+	if phase == "Succeeded" || phase == "Failed" {
+		return false, fmt.Errorf("pod '%s' terminated, phase: '%s'", workload.GetName(), phase)
+	}
+	status, _ := resourceCondition(workload, "Ready")
+	return status == string(metav1.ConditionTrue), nil
+}
+
+// DeploymentAvailable is met once a Deployment is available, as of its latest generation. It fails once its rollout exceeded its progress deadline
+func DeploymentAvailable(workload IWorkload) (bool, error) {
+	if status, reason := resourceCondition(workload, "Progressing"); status == string(metav1.ConditionFalse) && reason == "ProgressDeadlineExceeded" {
+		return false, fmt.Errorf("deployment '%s' exceeded its progress deadline", workload.GetName())
+	}
+	if resourceInt64(workload, "status", "observedGeneration") < resourceInt64(workload, "metadata", "generation") {
+		return false, nil
+	}
+	status, _ := resourceCondition(workload, "Available")
+	return status == string(metav1.ConditionTrue), nil
+}
+
+// JobComplete is met once a Job completed. It fails once the Job failed
+func JobComplete(workload IWorkload) (bool, error) {
+	if status, reason := resourceCondition(workload, "Failed"); status == string(metav1.ConditionTrue) {
+		return false, fmt.Errorf("job '%s' failed, reason: %s", workload.GetName(), reason)
+	}
+	status, _ := resourceCondition(workload, "Complete")
+	return status == string(metav1.ConditionTrue), nil
+}
+
+// CRDEstablished is met once a CustomResourceDefinition is established, i.e. its custom resources are served.
+// It fails once its names are rejected, e.g. if they conflict with another CustomResourceDefinition
+func CRDEstablished(workload IWorkload) (bool, error) {
+	if status, reason := resourceCondition(workload, "NamesAccepted"); status == string(metav1.ConditionFalse) {
+		return false, fmt.Errorf("custom resource definition '%s' names not accepted, reason: %s", workload.GetName(), reason)
+	}
+	status, _ := resourceCondition(workload, "Established")
+	return status == string(metav1.ConditionTrue), nil
+}
+
+// resourceCondition returns the status and reason of the status condition of a given type of an object, empty if it has none
+func resourceCondition(workload IWorkload, conditionType string) (string, string) {
+	conditions, _, _ := unstructured.NestedSlice(workload.GetObject(), "status", "conditions")
+	for i := range conditions {
+		condition, ok := conditions[i].(map[string]interface{})
+		if !ok || condition["type"] != conditionType {
+			continue
+		}
+		status, _ := condition["status"].(string)
+		reason, _ := condition["reason"].(string)
+		return status, reason
+	}
+	return "", ""
+}
+
+// resourceInt64 returns the integer field of an object at a given path, decoded from JSON as a float64 or not, 0 if it has none
+func resourceInt64(workload IWorkload, fields ...string) int64 {
+	v, _ := workloadinterface.InspectWorkload(workload.GetObject(), fields...)
+	switch n := v.(type) {
+	case float64:
+		return int64(n)
+	case int64:
+		return n
+	case int:
+		return int64(n)
+	}
+	return 0
+}
@@ -0,0 +1,236 @@
+package k8sinterface
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kubescape/k8s-interface/workloadinterface"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newWaitObject(apiVersion, kind, name string, status map[string]interface{}, conditions ...map[string]interface{}) map[string]interface{} {
+	if status == nil {
+		status = map[string]interface{}{}
+	}
+	if len(conditions) != 0 {
+		list := make([]interface{}, len(conditions))
+		for i := range conditions {
+			list[i] = conditions[i]
+		}
+		status["conditions"] = list
+	}
+	return map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata": map[string]interface{}{
+			"name":       name,
+			"namespace":  "default",
+			"generation": int64(2),
+		},
+		"status": status,
+	}
+}
+
+func waitCondition(conditionType, status, reason string) map[string]interface{} {
+	return map[string]interface{}{"type": conditionType, "status": status, "reason": reason}
+}
+
+func TestResourcePredicates(t *testing.T) {
+	tests := []struct {
+		name      string
+		predicate ResourcePredicate
+		obj       map[string]interface{}
+		want      bool
+		wantErr   bool
+	}{
+		{
+			name:      "Pod ready",
+			predicate: PodReady,
+			obj:       newWaitObject("v1", "Pod", "nginx", map[string]interface{}{"phase": "Running"}, waitCondition("Ready", "True", "")),
+			want:      true,
+		},
+		{
+			name:      "Pod not ready",
+			predicate: PodReady,
+			obj:       newWaitObject("v1", "Pod", "nginx", map[string]interface{}{"phase": "Running"}, waitCondition("Ready", "False", "ContainersNotReady")),
+		},
+		{
+			name:      "Pod failed",
+			predicate: PodReady,
+			obj:       newWaitObject("v1", "Pod", "nginx", map[string]interface{}{"phase": "Failed"}),
+			wantErr:   true,
+		},
+		{
+			name:      "Deployment available",
+			predicate: DeploymentAvailable,
+			obj:       newWaitObject("apps/v1", "Deployment", "nginx", map[string]interface{}{"observedGeneration": int64(2)}, waitCondition("Available", "True", "MinimumReplicasAvailable")),
+			want:      true,
+		},
+		{
+			name:      "Deployment available as of a previous generation",
+			predicate: DeploymentAvailable,
+			obj:       newWaitObject("apps/v1", "Deployment", "nginx", map[string]interface{}{"observedGeneration": int64(1)}, waitCondition("Available", "True", "MinimumReplicasAvailable")),
+		},
+		{
+			name:      "Deployment decoded from JSON",
+			predicate: DeploymentAvailable,
+			obj: func() map[string]interface{} {
+				workload, _ := workloadinterface.NewWorkload([]byte(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"nginx","generation":2},"status":{"observedGeneration":1,"conditions":[{"type":"Available","status":"True"}]}}`))
+				return workload.GetObject()
+			}(),
+		},
+		{
+			name:      "Deployment progress deadline exceeded",
+			predicate: DeploymentAvailable,
+			obj:       newWaitObject("apps/v1", "Deployment", "nginx", map[string]interface{}{"observedGeneration": int64(2)}, waitCondition("Progressing", "False", "ProgressDeadlineExceeded")),
+			wantErr:   true,
+		},
+		{
+			name:      "Job complete",
+			predicate: JobComplete,
+			obj:       newWaitObject("batch/v1", "Job", "migrate", nil, waitCondition("Complete", "True", "")),
+			want:      true,
+		},
+		{
+			name:      "Job running",
+			predicate: JobComplete,
+			obj:       newWaitObject("batch/v1", "Job", "migrate", map[string]interface{}{"active": int64(1)}),
+		},
+		{
+			name:      "Job failed",
+			predicate: JobComplete,
+			obj:       newWaitObject("batch/v1", "Job", "migrate", nil, waitCondition("Failed", "True", "BackoffLimitExceeded")),
+			wantErr:   true,
+		},
+		{
+			name:      "CRD established",
+			predicate: CRDEstablished,
+			obj:       newWaitObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "widgets.example.com", nil, waitCondition("NamesAccepted", "True", "NoConflicts"), waitCondition("Established", "True", "InitialNamesAccepted")),
+			want:      true,
+		},
+		{
+			name:      "CRD names not accepted",
+			predicate: CRDEstablished,
+			obj:       newWaitObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "widgets.example.com", nil, waitCondition("NamesAccepted", "False", "PluralConflict")),
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.predicate(workloadinterface.NewWorkloadObj(tt.obj))
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWaitForResourceCondition(t *testing.T) {
+	podsGVR := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	defer func(interval time.Duration) { WaitPollInterval = interval }(WaitPollInterval)
+
+	notReady := func() *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: newWaitObject("v1", "Pod", "nginx", map[string]interface{}{"phase": "Pending"})}
+	}
+	ready := func() *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: newWaitObject("v1", "Pod", "nginx", map[string]interface{}{"phase": "Running"}, waitCondition("Ready", "True", ""))}
+	}
+	failed := func() *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: newWaitObject("v1", "Pod", "nginx", map[string]interface{}{"phase": "Failed"})}
+	}
+
+	tests := []struct {
+		name         string
+		pollInterval time.Duration
+		watchErr     error
+		initial      []runtime.Object
+		update       func(client *dynamicfake.FakeDynamicClient) error
+		wantErr      error
+	}{
+		{
+			name:         "Already met",
+			pollInterval: time.Minute,
+			initial:      []runtime.Object{ready()},
+		},
+		{
+			name:         "Met once watched",
+			pollInterval: time.Minute,
+			initial:      []runtime.Object{notReady()},
+			update: func(client *dynamicfake.FakeDynamicClient) error {
+				return client.Tracker().Update(podsGVR, ready(), "default")
+			},
+		},
+		{
+			name:         "Created once watched",
+			pollInterval: time.Minute,
+			update: func(client *dynamicfake.FakeDynamicClient) error {
+				return client.Tracker().Create(podsGVR, ready(), "default")
+			},
+		},
+		{
+			name:         "Met once polled, watching forbidden",
+			pollInterval: 10 * time.Millisecond,
+			watchErr:     errors.New("forbidden"),
+			initial:      []runtime.Object{notReady()},
+			update: func(client *dynamicfake.FakeDynamicClient) error {
+				return client.Tracker().Update(podsGVR, ready(), "default")
+			},
+		},
+		{
+			name:         "Predicate fails",
+			pollInterval: time.Minute,
+			initial:      []runtime.Object{notReady()},
+			update: func(client *dynamicfake.FakeDynamicClient) error {
+				return client.Tracker().Update(podsGVR, failed(), "default")
+			},
+			wantErr: errors.New("pod 'nginx' terminated"),
+		},
+		{
+			name:         "Timeout",
+			pollInterval: 10 * time.Millisecond,
+			initial:      []runtime.Object{notReady()},
+			wantErr:      context.DeadlineExceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			WaitPollInterval = tt.pollInterval
+			k8sAPI := NewKubernetesApiMock()
+			client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), tt.initial...)
+			if tt.watchErr != nil {
+				client.PrependWatchReactor("pods", func(action k8stesting.Action) (bool, watch.Interface, error) {
+					return true, nil, tt.watchErr
+				})
+			}
+			k8sAPI.DynamicClient = client
+			if tt.update != nil {
+				time.AfterFunc(50*time.Millisecond, func() { assert.NoError(t, tt.update(client)) })
+			}
+
+			timeout := 5 * time.Second
+			if tt.wantErr == context.DeadlineExceeded {
+				timeout = 100 * time.Millisecond
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+			workload, err := k8sAPI.WaitForResourceCondition(ctx, &podsGVR, "nginx", "default", PodReady)
+			if tt.wantErr == context.DeadlineExceeded {
+				assert.ErrorIs(t, err, context.DeadlineExceeded)
+				return
+			}
+			if tt.wantErr != nil {
+				assert.ErrorContains(t, err, tt.wantErr.Error())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "nginx", workload.GetName())
+		})
+	}
+}
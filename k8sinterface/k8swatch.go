@@ -0,0 +1,142 @@
+package k8sinterface
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kubescape/k8s-interface/workloadinterface"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+const (
+	watchInitialBackoff = time.Second
+	watchMaxBackoff     = 30 * time.Second
+)
+
+// WatchEvent is a change to a watched resource: ADDED, MODIFIED or DELETED
+type WatchEvent struct {
+	Type   watch.EventType
+	Object IWorkload
+}
+
+// WatchResources watches a given resource in a given namespace, or in all namespaces if empty, scoped to a given label selector
+func (k8sAPI *KubernetesApi) WatchResources(groupVersionResource *schema.GroupVersionResource, namespace, labelSelector string) (<-chan WatchEvent, error) {
+	return k8sAPI.WatchResourcesWithContext(k8sAPI.Context, groupVersionResource, namespace, labelSelector)
+}
+
+// WatchResourcesWithContext works like WatchResources, and stops watching once the context is done
+//
+// Existing objects are first sent as ADDED events. When the watch is closed by the server, it is resumed from the last seen resource version,
+// kept up to date by bookmark events, with an exponential backoff between failed attempts. If that resource version is too old,
+// the watch restarts from the current state, sending existing objects as ADDED events again.
+// The returned channel is closed once the context is done. If the first watch cannot be established, it returns an error
+func (k8sAPI *KubernetesApi) WatchResourcesWithContext(ctx context.Context, groupVersionResource *schema.GroupVersionResource, namespace, labelSelector string) (<-chan WatchEvent, error) {
+	if _, err := labels.Parse(labelSelector); err != nil {
+		return nil, fmt.Errorf("invalid label selector '%s', reason: %s", labelSelector, err.Error())
+	}
+	watcher, err := k8sAPI.watch(ctx, groupVersionResource, namespace, labelSelector, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to WATCH resources, resource: '%s', namespace: '%s', reason: %s", groupVersionResource.String(), namespace, err.Error())
+	}
+
+	events := make(chan WatchEvent)
+	go func() {
+		defer close(events)
+
+		resourceVersion := ""
+		backoff := watchInitialBackoff
+		for {
+			if watcher != nil {
+				var received bool
+				resourceVersion, received = forwardWatchEvents(ctx, watcher, resourceVersion, events)
+				if received {
+					backoff = watchInitialBackoff
+				}
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			k8sAPI.GetLogger().Debug("watch closed, watching again", "resource", groupVersionResource.String(), "namespace", namespace,
+				"resourceVersion", resourceVersion, "delay", backoff.String())
+
+			timer := time.NewTimer(backoff)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+			backoff = min(backoff*2, watchMaxBackoff)
+
+			k8sAPI.metrics.watchReconnected(groupVersionResource)
+			watcher, err = k8sAPI.watch(ctx, groupVersionResource, namespace, labelSelector, resourceVersion)
+			if err != nil {
+				k8sAPI.GetLogger().Warning("failed to watch resources again", "resource", groupVersionResource.String(), "namespace", namespace, "error", err)
+				if isResourceVersionTooOld(err) {
+					resourceVersion = ""
+				}
+			}
+		}
+	}()
+	return events, nil
+}
+
+func (k8sAPI *KubernetesApi) watch(ctx context.Context, groupVersionResource *schema.GroupVersionResource, namespace, labelSelector, resourceVersion string) (watch.Interface, error) {
+	return k8sAPI.ResourceInterface(groupVersionResource, namespace).Watch(ctx, metav1.ListOptions{
+		LabelSelector:       labelSelector,
+		ResourceVersion:     resourceVersion,
+		AllowWatchBookmarks: true,
+	})
+}
+
+// forwardWatchEvents sends the events of a watch to a channel until the watch is closed or the context is done
+//
+// It returns the resource version to resume the watch from, and whether any event was received
+func forwardWatchEvents(ctx context.Context, watcher watch.Interface, resourceVersion string, events chan<- WatchEvent) (string, bool) {
+	defer watcher.Stop()
+
+	received := false
+	for {
+		select {
+		case <-ctx.Done():
+			return resourceVersion, received
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return resourceVersion, received
+			}
+			if event.Type == watch.Error {
+				if isResourceVersionTooOld(apierrors.FromObject(event.Object)) {
+					resourceVersion = ""
+				}
+				return resourceVersion, received
+			}
+
+			obj, ok := event.Object.(*unstructured.Unstructured)
+			if !ok {
+				continue
+			}
+			received = true
+			resourceVersion = obj.GetResourceVersion()
+			if event.Type == watch.Bookmark {
+				continue
+			}
+
+			select {
+			case events <- WatchEvent{Type: event.Type, Object: workloadinterface.NewWorkloadObj(obj.Object)}:
+			case <-ctx.Done():
+				return resourceVersion, received
+			}
+		}
+	}
+}
+
+// isResourceVersionTooOld reports whether a watch failed because its resource version was compacted away
+func isResourceVersionTooOld(err error) bool {
+	return apierrors.IsResourceExpired(err) || apierrors.IsGone(err)
+}
@@ -0,0 +1,89 @@
+package k8sinterface
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func TestWatchResources(t *testing.T) {
+	k8sAPI := NewKubernetesApiMock()
+	podsGVR := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+
+	_, err := k8sAPI.WatchResources(&podsGVR, "default", "app in (nginx")
+	assert.Error(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := k8sAPI.WatchResourcesWithContext(ctx, &podsGVR, "default", "")
+	assert.NoError(t, err)
+
+	pod := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata": map[string]interface{}{
+			"name":      "nginx",
+			"namespace": "default",
+		},
+	}}
+	_, err = k8sAPI.DynamicClient.Resource(podsGVR).Namespace("default").Create(ctx, pod, metav1.CreateOptions{})
+	assert.NoError(t, err)
+
+	select {
+	case event := <-events:
+		assert.Equal(t, watch.Added, event.Type)
+		assert.Equal(t, "nginx", event.Object.GetName())
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the ADDED event")
+	}
+
+	cancel()
+	select {
+	case _, ok := <-events:
+		assert.False(t, ok)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the events channel to close")
+	}
+}
+
+func TestIsResourceVersionTooOld(t *testing.T) {
+	podsGR := schema.GroupResource{Resource: "pods"}
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "Expired resource version",
+			err:  apierrors.NewResourceExpired("too old resource version: 1 (2)"),
+			want: true,
+		},
+		{
+			name: "Gone",
+			err:  apierrors.NewGone("gone"),
+			want: true,
+		},
+		{
+			name: "Other API errors are not",
+			err:  apierrors.NewNotFound(podsGR, "nginx"),
+			want: false,
+		},
+		{
+			name: "No error",
+			err:  nil,
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isResourceVersionTooOld(tt.err))
+		})
+	}
+}
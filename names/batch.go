@@ -0,0 +1,32 @@
+package names
+
+// ImageInfo is an image and its hash, the inputs of an image slug
+type ImageInfo struct {
+	Image     string
+	ImageHash string
+}
+
+// Result is the outcome of generating a single slug in a batch
+type Result struct {
+	Slug string
+	Err  error
+}
+
+// ImageInfosToSlugs returns the slugs for a given batch of image information, generated according to the given options
+//
+// The results are in the same order as the inputs, each with its own error, so that a failing image does not fail the batch.
+// The returned error aggregates the errors of all items, or is nil if every slug was generated.
+// Options are resolved once for the whole batch and the results are allocated up front, which matters for batches of thousands of images
+func ImageInfosToSlugs(infos []ImageInfo, opts ...Option) ([]Result, error) {
+	options := resolveOptions(opts)
+
+	results := make([]Result, len(infos))
+	var errs []error
+	for i, info := range infos {
+		results[i].Slug, results[i].Err = imageInfoToSlug(info.Image, info.ImageHash, "", options)
+		if results[i].Err != nil {
+			errs = append(errs, results[i].Err)
+		}
+	}
+	return results, AggregateErrors(errs)
+}
@@ -0,0 +1,56 @@
+package names
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageInfosToSlugs(t *testing.T) {
+	imageHash := "f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac8c"
+	infos := []ImageInfo{
+		{Image: "docker.io/nginx:latest", ImageHash: imageHash},
+		{Image: "", ImageHash: imageHash},
+		{Image: "quay.io/kubescape/kubevuln:v0.2.108", ImageHash: imageHash},
+	}
+
+	results, err := ImageInfosToSlugs(infos)
+
+	assert.ErrorIs(t, err, ErrInvalidSlug)
+	assert.Len(t, results, len(infos))
+	assert.Equal(t, Result{Slug: "docker.io-nginx-latest-a3ac8c"}, results[0])
+	assert.Equal(t, "", results[1].Slug)
+	assert.ErrorIs(t, results[1].Err, ErrInvalidSlug)
+	assert.Equal(t, Result{Slug: "quay.io-kubescape-kubevuln-v0.2.108-a3ac8c"}, results[2])
+}
+
+func TestImageInfosToSlugsMatchesSingleCalls(t *testing.T) {
+	imageHash := "f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac8c"
+	infos := []ImageInfo{
+		{Image: "docker.io/nginx:latest", ImageHash: imageHash},
+		{Image: "docker.io/redis:7", ImageHash: imageHash},
+	}
+
+	results, err := ImageInfosToSlugs(infos, WithHideDefaultRegistry())
+
+	assert.NoError(t, err)
+	for i, info := range infos {
+		expected, err := ImageInfoToSlugWithOptions(info.Image, info.ImageHash, WithHideDefaultRegistry())
+		assert.NoError(t, err)
+		assert.Equal(t, expected, results[i].Slug)
+	}
+}
+
+func BenchmarkImageInfosToSlugs(b *testing.B) {
+	imageHash := "f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac8c"
+	infos := make([]ImageInfo, 1000)
+	for i := range infos {
+		infos[i] = ImageInfo{Image: fmt.Sprintf("quay.io/kubescape/kubevuln:v0.2.%d", i), ImageHash: imageHash}
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = ImageInfosToSlugs(infos)
+	}
+}
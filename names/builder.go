@@ -0,0 +1,119 @@
+package names
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SegmentType identifies what a segment of a slug built with SlugBuilder describes
+type SegmentType string
+
+const (
+	SegmentGroup     SegmentType = "group"
+	SegmentKind      SegmentType = "kind"
+	SegmentNamespace SegmentType = "namespace"
+	SegmentName      SegmentType = "name"
+	SegmentContainer SegmentType = "container"
+	SegmentImage     SegmentType = "image"
+)
+
+// segmentOrder is the order in which segments of known types appear in a slug, segments of other types follow them
+var segmentOrder = map[SegmentType]int{
+	SegmentGroup:     0,
+	SegmentKind:      1,
+	SegmentNamespace: 2,
+	SegmentName:      3,
+	SegmentContainer: 4,
+	SegmentImage:     5,
+}
+
+type segment struct {
+	segmentType SegmentType
+	value       string
+}
+
+// SlugBuilder builds a slug segment by segment, for cases the positional-argument functions do not cover
+//
+// Segments are ordered by their type: group, kind, namespace, name, container and image, followed by segments of any other type
+// in the order they were added. The hash, if set, is always kept last
+type SlugBuilder struct {
+	segments []segment
+	hash     string
+	options  Options
+}
+
+// NewSlugBuilder returns an empty SlugBuilder
+//
+// The slug honours the strict labels of the given options, the other options do not apply
+func NewSlugBuilder(opts ...Option) *SlugBuilder {
+	return &SlugBuilder{options: resolveOptions(opts)}
+}
+
+// AddSegment adds a segment of a given type to the slug
+func (b *SlugBuilder) AddSegment(segmentType SegmentType, value string) *SlugBuilder {
+	b.segments = append(b.segments, segment{segmentType: segmentType, value: value})
+	return b
+}
+
+// SetHash sets the hash suffix of the slug, which is used as is, e.g. "a3ac8c" or "1ba5-4aaf"
+func (b *SlugBuilder) SetHash(hash string) *SlugBuilder {
+	b.hash = hash
+	return b
+}
+
+// Build returns the slug made of the added segments and the hash
+//
+// Every segment is sanitized into a DNS Subdomain name and the result is truncated to fit one, keeping the hash intact.
+// If there are no segments, a segment sanitizes to nothing or the hash is not valid, it returns an appropriate error
+func (b *SlugBuilder) Build() (string, error) {
+	if len(b.segments) == 0 {
+		return "", fmt.Errorf("%w: no segments", ErrInvalidSlug)
+	}
+	if b.hash != "" && (!IsValidDNSLabelName(b.hash) || len(b.hash) > maxDNSSubdomainLength-2) {
+		return "", ErrInvalidHash
+	}
+
+	ordered := make([]segment, len(b.segments))
+	copy(ordered, b.segments)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return segmentRank(ordered[i].segmentType) < segmentRank(ordered[j].segmentType)
+	})
+
+	values := make([]string, 0, len(ordered))
+	for _, s := range ordered {
+		value, err := ToValidDNSSubdomainName(s.value)
+		if err != nil {
+			return "", fmt.Errorf("%w: invalid %s segment %q", ErrInvalidSlug, s.segmentType, s.value)
+		}
+		values = append(values, value)
+	}
+
+	maxLength := maxDNSSubdomainLength
+	if b.hash != "" {
+		maxLength -= len(b.hash) + len(slugSeparator)
+	}
+	slug := strings.Join(values, slugSeparator)
+	if len(slug) > maxLength {
+		slug = strings.TrimFunc(slug[:maxLength], isNonAlphanumeric)
+	}
+	if b.hash != "" {
+		slug = fmt.Sprintf(slugFormat, slug, b.hash)
+	}
+
+	if !IsValidSlug(slug) {
+		return "", ErrInvalidSlug
+	}
+	if err := b.options.checkLabels(slug, "segments"); err != nil {
+		return "", err
+	}
+	return slug, nil
+}
+
+// segmentRank returns the position of segments of a given type in a slug
+func segmentRank(segmentType SegmentType) int {
+	if rank, ok := segmentOrder[segmentType]; ok {
+		return rank
+	}
+	return len(segmentOrder)
+}
@@ -0,0 +1,106 @@
+package names
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlugBuilderMatchesInstanceIDToSlug(t *testing.T) {
+	hashedID := "1ba506b28f9ee9c7e8a0c98840fe5a1fe21142d225ecc526fbb535d0d6344aaf"
+	expected, err := InstanceIDToSlug("reverse-proxy", "Pod", "nginx", hashedID)
+	assert.NoError(t, err)
+
+	got, err := NewSlugBuilder().
+		AddSegment(SegmentContainer, "nginx").
+		AddSegment(SegmentName, "reverse-proxy").
+		AddSegment(SegmentKind, "Pod").
+		SetHash(hashedID[:4] + "-" + hashedID[len(hashedID)-4:]).
+		Build()
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, got)
+}
+
+func TestSlugBuilderMatchesImageInfoToSlug(t *testing.T) {
+	imageHash := "f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac8c"
+	expected, err := ImageInfoToSlug("docker.io/nginx:latest", imageHash)
+	assert.NoError(t, err)
+
+	got, err := NewSlugBuilder().
+		AddSegment(SegmentImage, "docker.io/nginx:latest").
+		SetHash(imageHash[len(imageHash)-6:]).
+		Build()
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, got)
+}
+
+func TestSlugBuilder(t *testing.T) {
+	tt := []struct {
+		name    string
+		builder *SlugBuilder
+		want    string
+		wantErr error
+	}{
+		{
+			name: "segments are ordered by type",
+			builder: NewSlugBuilder().
+				AddSegment("revision", "3").
+				AddSegment(SegmentName, "webapp").
+				AddSegment(SegmentNamespace, "default").
+				AddSegment(SegmentGroup, "apps").
+				AddSegment(SegmentKind, "Deployment"),
+			want: "apps-deployment-default-webapp-3",
+		},
+		{
+			name: "segments are sanitized",
+			builder: NewSlugBuilder().
+				AddSegment(SegmentName, "Web_App").
+				SetHash("a3ac8c"),
+			want: "web-app-a3ac8c",
+		},
+		{
+			name: "long segments are truncated keeping the hash",
+			builder: NewSlugBuilder().
+				AddSegment(SegmentName, strings.Repeat("a", 300)).
+				SetHash("a3ac8c"),
+			want: strings.Repeat("a", 246) + "-a3ac8c",
+		},
+		{
+			name:    "no segments produce matching error",
+			builder: NewSlugBuilder().SetHash("a3ac8c"),
+			wantErr: ErrInvalidSlug,
+		},
+		{
+			name:    "segment that sanitizes to nothing produces matching error",
+			builder: NewSlugBuilder().AddSegment(SegmentName, "///"),
+			wantErr: ErrInvalidSlug,
+		},
+		{
+			name:    "invalid hash produces matching error",
+			builder: NewSlugBuilder().AddSegment(SegmentName, "webapp").SetHash("A3AC8C"),
+			wantErr: ErrInvalidHash,
+		},
+		{
+			name:    "strict labels reject a long label",
+			builder: NewSlugBuilder(WithStrictLabels()).AddSegment(SegmentName, strings.Repeat("a", 70)).SetHash("a3ac8c"),
+			wantErr: ErrNameTooLong,
+		},
+		{
+			name:    "strict labels accept short labels",
+			builder: NewSlugBuilder(WithStrictLabels()).AddSegment(SegmentImage, "docker.io/nginx").SetHash("a3ac8c"),
+			want:    "docker.io-nginx-a3ac8c",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.builder.Build()
+
+			assert.Equal(t, tc.want, got)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
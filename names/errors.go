@@ -1,7 +1,87 @@
 package names
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	ErrInvalidSlug = errors.New("Current inputs produce an invalid slug")
+	// ErrNameTooLong is returned when the requested length limit cannot fit a valid slug
+	ErrNameTooLong = fmt.Errorf("%w: requested length cannot fit the slug", ErrInvalidSlug)
+	// ErrInvalidHash is returned when a hash is malformed or cannot provide the requested hash-based identifiers
+	ErrInvalidHash = fmt.Errorf("%w: invalid hash", ErrInvalidSlug)
+	// ErrUnparseableSlug is returned when a slug does not have the shape of any generated slug
+	ErrUnparseableSlug = errors.New("unparseable slug")
+	// ErrInvalidImageReference is returned when an image reference has a malformed component
+	ErrInvalidImageReference = errors.New("invalid image reference")
+	// ErrInvalidPlatform is returned when an image platform is malformed
+	ErrInvalidPlatform = errors.New("invalid platform")
+	// ErrUnknownKind is returned when a string does not match any known Kubernetes kind
+	ErrUnknownKind = errors.New("unknown kind")
+	// ErrInvalidName is returned when a name is not valid for the Kubernetes field it is used in
+	ErrInvalidName = errors.New("invalid name")
+	// ErrAnnotationsTooLarge is returned when annotations exceed the total size Kubernetes accepts
+	ErrAnnotationsTooLarge = errors.New("annotations too large")
+	// ErrNameCollision is returned when no generated name is free
+	ErrNameCollision = errors.New("name collision")
+	// ErrInvalidNameTemplate is returned when a name template layout cannot be parsed or executed
+	ErrInvalidNameTemplate = errors.New("invalid name template")
 )
+
+// InvalidInputReason describes why an input cannot produce a slug
+type InvalidInputReason string
+
+const (
+	// ReasonEmpty means that the input is empty
+	ReasonEmpty InvalidInputReason = "empty"
+	// ReasonTooShort means that the input is too short, such as a hash shorter than the requested hash suffix
+	ReasonTooShort InvalidInputReason = "too short"
+	// ReasonTooLong means that the input produces a slug, or a part of it, that is too long
+	ReasonTooLong InvalidInputReason = "too long"
+	// ReasonInvalidCharacters means that the input has characters that cannot appear in a slug
+	ReasonInvalidCharacters InvalidInputReason = "invalid characters"
+)
+
+// InvalidInputError describes which input of a slug generator is invalid and why
+//
+// It wraps the sentinel error the generator used to return, such as ErrInvalidSlug or ErrNameTooLong, so errors.Is keeps working,
+// while errors.As lets callers tell e.g. a hash that is too short from invalid characters in the image
+type InvalidInputError struct {
+	// Field is the name of the invalid input, e.g. "image", "imageHash" or "maxLength"
+	Field string
+	// Reason is why the input is invalid
+	Reason InvalidInputReason
+	// Err is the sentinel error the error wraps
+	Err error
+}
+
+// newInvalidInputError returns an InvalidInputError wrapping a given sentinel error
+func newInvalidInputError(err error, field string, reason InvalidInputReason) error {
+	return &InvalidInputError{Field: field, Reason: reason, Err: err}
+}
+
+func (e *InvalidInputError) Error() string {
+	return fmt.Sprintf("%v: %s: %s", e.Err, e.Field, e.Reason)
+}
+
+func (e *InvalidInputError) Unwrap() error {
+	return e.Err
+}
+
+// FirstError returns the first non-nil error of a given slice of per-item errors, or nil if there is none
+func FirstError(errs []error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AggregateErrors returns the non-nil errors of a given slice of per-item errors joined together, or nil if there is none
+//
+// The result matches every joined error with errors.Is, e.g. ErrInvalidSlug for slugs that could not be generated
+func AggregateErrors(errs []error) error {
+	return errors.Join(errs...)
+}
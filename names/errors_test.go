@@ -0,0 +1,104 @@
+package names
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFirstError(t *testing.T) {
+	assert.NoError(t, FirstError(nil))
+	assert.NoError(t, FirstError([]error{nil, nil}))
+	assert.Equal(t, ErrNameTooLong, FirstError([]error{nil, ErrNameTooLong, ErrUnknownKind}))
+}
+
+func TestAggregateErrors(t *testing.T) {
+	assert.NoError(t, AggregateErrors(nil))
+	assert.NoError(t, AggregateErrors([]error{nil, nil}))
+
+	_, slugErr := ImageInfoToSlug("", "f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac8c")
+	err := AggregateErrors([]error{nil, slugErr, nil, ErrUnknownKind})
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrInvalidSlug))
+	assert.True(t, errors.Is(err, ErrUnknownKind))
+}
+
+func TestInvalidInputError(t *testing.T) {
+	imageHash := "f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac8c"
+	tt := []struct {
+		name       string
+		generate   func() (string, error)
+		wantField  string
+		wantReason InvalidInputReason
+		wantErr    error
+	}{
+		{
+			name:       "Empty image",
+			generate:   func() (string, error) { return ImageInfoToSlug("", imageHash) },
+			wantField:  "image",
+			wantReason: ReasonEmpty,
+			wantErr:    ErrInvalidSlug,
+		},
+		{
+			name:       "Image hash too short",
+			generate:   func() (string, error) { return ImageInfoToSlug("nginx", "3ac8c") },
+			wantField:  "imageHash",
+			wantReason: ReasonTooShort,
+			wantErr:    ErrInvalidSlug,
+		},
+		{
+			name:       "Invalid characters in the image hash",
+			generate:   func() (string, error) { return ImageInfoToSlug("nginx", imageHash[:60]+"a$8c") },
+			wantField:  "imageHash",
+			wantReason: ReasonInvalidCharacters,
+			wantErr:    ErrInvalidSlug,
+		},
+		{
+			name:       "Invalid characters in the image",
+			generate:   func() (string, error) { return ImageInfoToSlug("nginx$", imageHash) },
+			wantField:  "image",
+			wantReason: ReasonInvalidCharacters,
+			wantErr:    ErrInvalidSlug,
+		},
+		{
+			name: "Strict labels",
+			generate: func() (string, error) {
+				return ImageInfoToSlugWithOptions(strings.Repeat("a", 70), imageHash, WithStrictLabels())
+			},
+			wantField:  "image",
+			wantReason: ReasonTooLong,
+			wantErr:    ErrNameTooLong,
+		},
+		{
+			name:       "Invalid characters in the instance ID name",
+			generate:   func() (string, error) { return InstanceIDToSlug("web app", "Pod", "nginx", imageHash) },
+			wantField:  "name",
+			wantReason: ReasonInvalidCharacters,
+			wantErr:    ErrInvalidSlug,
+		},
+		{
+			name: "Length that cannot fit the instance ID hashes",
+			generate: func() (string, error) {
+				return InstanceIDToSlugWithOptions("webapp", "Pod", "nginx", imageHash, WithMaxLen(10))
+			},
+			wantField:  "maxLength",
+			wantReason: ReasonTooShort,
+			wantErr:    ErrNameTooLong,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.generate()
+
+			var inputErr *InvalidInputError
+			assert.True(t, errors.As(err, &inputErr))
+			assert.Equal(t, tc.wantField, inputErr.Field)
+			assert.Equal(t, tc.wantReason, inputErr.Reason)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
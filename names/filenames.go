@@ -0,0 +1,46 @@
+package names
+
+import "strings"
+
+// windowsReservedNames are the device names Windows reserves, which cannot be used as file names, even with an extension
+var windowsReservedNames = map[string]bool{
+	"con": true, "prn": true, "aux": true, "nul": true,
+	"com1": true, "com2": true, "com3": true, "com4": true, "com5": true, "com6": true, "com7": true, "com8": true, "com9": true,
+	"lpt1": true, "lpt2": true, "lpt3": true, "lpt4": true, "lpt5": true, "lpt6": true, "lpt7": true, "lpt8": true, "lpt9": true,
+}
+
+// ImageInfoToFileName returns a name for a given image information that is safe to use as a file or directory name on Linux and Windows
+//
+// It is the image slug, which is lowercase, so names never collide on case-insensitive filesystems, and only has
+// alphanumeric characters, hyphens and periods, see ToFileName
+func ImageInfoToFileName(image, imageHash string) (string, error) {
+	slug, err := ImageInfoToSlug(image, imageHash)
+	if err != nil {
+		return "", err
+	}
+	return ToFileName(slug), nil
+}
+
+// InstanceIDToFileName returns a name for a given description of an instance ID that is safe to use as a file or directory name on Linux and Windows
+//
+// It is the instance ID slug, made safe the same way as ImageInfoToFileName
+func InstanceIDToFileName(name, kind, containerName, hashedID string) (string, error) {
+	slug, err := InstanceIDToSlug(name, kind, containerName, hashedID)
+	if err != nil {
+		return "", err
+	}
+	return ToFileName(slug), nil
+}
+
+// ToFileName turns a given slug into a name that is safe to use as a file or directory name on Linux and Windows
+//
+// Slugs are already made of lowercase alphanumeric characters, hyphens and periods, and fit the 255 byte file name limit,
+// but Windows reserves device names such as "con" or "nul", also when followed by an extension, e.g. "nul.tar-a3ac8c".
+// Such slugs are prefixed with an underscore, any other slug is returned as is
+func ToFileName(slug string) string {
+	stem, _, _ := strings.Cut(slug, ".")
+	if windowsReservedNames[stem] {
+		return "_" + slug
+	}
+	return slug
+}
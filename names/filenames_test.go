@@ -0,0 +1,86 @@
+package names
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageInfoToFileName(t *testing.T) {
+	imageHash := "f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac8c"
+	tt := []struct {
+		name    string
+		image   string
+		want    string
+		wantErr error
+	}{
+		{
+			name:  "Registry, port and tag separators are replaced",
+			image: "localhost:5000/Kubescape/kubevuln:v0.2.108",
+			want:  "localhost-5000-kubescape-kubevuln-v0.2.108-a3ac8c",
+		},
+		{
+			name:  "Windows device names are prefixed",
+			image: "nul.tar",
+			want:  "_nul.tar-a3ac8c",
+		},
+		{
+			name:    "Invalid image produces matching error",
+			image:   "",
+			wantErr: ErrInvalidSlug,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ImageInfoToFileName(tc.image, imageHash)
+
+			assert.Equal(t, tc.want, got)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestInstanceIDToFileName(t *testing.T) {
+	hashedID := "1ba506b28f9ee9c7e8a0c98840fe5a1fe21142d225ecc526fbb535d0d6344aaf"
+
+	got, err := InstanceIDToFileName("reverse-proxy", "Pod", "nginx", hashedID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "pod-reverse-proxy-nginx-1ba5-4aaf", got)
+}
+
+func TestToFileName(t *testing.T) {
+	tt := []struct {
+		name string
+		slug string
+		want string
+	}{
+		{
+			name: "Regular slug is returned as is",
+			slug: "nginx-latest-a3ac8c",
+			want: "nginx-latest-a3ac8c",
+		},
+		{
+			name: "Device name",
+			slug: "con",
+			want: "_con",
+		},
+		{
+			name: "Device name with an extension",
+			slug: "com1.io-a3ac8c",
+			want: "_com1.io-a3ac8c",
+		},
+		{
+			name: "Device name as a prefix of a longer name is safe",
+			slug: "console-a3ac8c",
+			want: "console-a3ac8c",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, ToFileName(tc.slug))
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package names
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+const (
+	// randomSuffixLength is the length of the random suffix Kubernetes appends to generated names
+	randomSuffixLength = 5
+	// randomSuffixAlphabet is the alphabet of random suffixes, without vowels and confusable characters just like in Kubernetes
+	randomSuffixAlphabet = "bcdfghjklmnpqrstvwxz2456789"
+	// maxGeneratedNameBaseLength is the longest base Kubernetes keeps when generating a name from GenerateName
+	maxGeneratedNameBaseLength = maxDNSLabelLength - randomSuffixLength
+	// maxCronJobNameLength is the longest CronJob name Kubernetes accepts, leaving room for the "-<timestamp>" suffix of its Jobs
+	maxCronJobNameLength = 52
+	// maxGenerateNameAttempts is how many random suffixes GenerateUniqueName tries before giving up
+	maxGenerateNameAttempts = 8
+)
+
+// GenerateEndpointSliceName returns a name for an EndpointSlice of a given Service: the Service name followed by a random suffix
+//
+// The Service name is truncated as needed, so the result is always a valid DNS label name
+func GenerateEndpointSliceName(serviceName string) (string, error) {
+	return GenerateEndpointSliceNameWithRand(serviceName, nil)
+}
+
+// GenerateEndpointSliceNameWithRand works like GenerateEndpointSliceName, but draws the random suffix from a given source
+//
+// It is meant for tests that need reproducible names. A nil source uses the default one
+func GenerateEndpointSliceNameWithRand(serviceName string, rnd *rand.Rand) (string, error) {
+	if !IsValidDNSLabelName(serviceName) {
+		return "", fmt.Errorf("%w: %q is not a valid Service name", ErrInvalidName, serviceName)
+	}
+
+	return truncateForSuffix(serviceName, maxDNSLabelLength) + slugSeparator + randomSuffix(rnd), nil
+}
+
+// GenerateNameDeterministic returns a name generated from a given base the way Kubernetes handles GenerateName,
+// but with a suffix derived from the SHA-256 hash of the base and a given seed instead of a random one
+//
+// The same inputs always produce the same name, which suits idempotent reconcilers and tests. Just like in Kubernetes,
+// the base is kept as is, so it usually ends with a separator, and is truncated to leave room for the suffix
+func GenerateNameDeterministic(base, seed string) (string, error) {
+	suffix := hashSuffix(base + seed)
+
+	if len(base) > maxGeneratedNameBaseLength {
+		base = base[:maxGeneratedNameBaseLength]
+	}
+	name := base + suffix
+	if !isValidObjectName(name, maxDNSSubdomainLength) {
+		return "", fmt.Errorf("%w: %q is not a valid base for a generated name", ErrInvalidName, base)
+	}
+	return name, nil
+}
+
+// GenerateUniqueName returns a name generated from a given base the way Kubernetes handles GenerateName: the base followed by a random suffix
+//
+// Just like in Kubernetes, the base is kept as is, so it usually ends with a separator, and is truncated to leave room for the suffix.
+// Names for which exists returns true are skipped and a new suffix is drawn, up to 8 times, after which it returns ErrNameCollision
+func GenerateUniqueName(base string, exists func(name string) bool) (string, error) {
+	return GenerateUniqueNameWithRand(base, exists, nil)
+}
+
+// GenerateUniqueNameWithRand works like GenerateUniqueName, but draws the random suffixes from a given source
+//
+// It is meant for tests that need reproducible names. A nil source uses the default one
+func GenerateUniqueNameWithRand(base string, exists func(name string) bool, rnd *rand.Rand) (string, error) {
+	if len(base) > maxGeneratedNameBaseLength {
+		base = base[:maxGeneratedNameBaseLength]
+	}
+	// every suffix is made of lowercase alphanumeric characters, so any of them tells whether the base is valid
+	if !isValidObjectName(base+randomSuffixAlphabet[:randomSuffixLength], maxDNSSubdomainLength) {
+		return "", fmt.Errorf("%w: %q is not a valid base for a generated name", ErrInvalidName, base)
+	}
+
+	for attempt := 0; attempt < maxGenerateNameAttempts; attempt++ {
+		if name := base + randomSuffix(rnd); !exists(name) {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("%w: no free name found for base %q after %d attempts", ErrNameCollision, base, maxGenerateNameAttempts)
+}
+
+// GenerateCronJobName returns a valid CronJob name derived from a given base, such as a slug
+//
+// The base is sanitized into a DNS Subdomain name and truncated to 52 characters, so that the Jobs of the CronJob,
+// named after it with a "-<timestamp>" suffix, still have valid names
+func GenerateCronJobName(base string) (string, error) {
+	name, err := ToValidDNSSubdomainName(base)
+	if err != nil {
+		return "", fmt.Errorf("%w: %q cannot be turned into a CronJob name", ErrInvalidName, base)
+	}
+
+	if len(name) > maxCronJobNameLength {
+		name = strings.TrimRight(name[:maxCronJobNameLength], "-.")
+	}
+	return name, nil
+}
+
+// IsValidCronJobName returns true if a given name is a valid CronJob name: a DNS Subdomain name of at most 52 characters
+func IsValidCronJobName(name string) bool {
+	return isValidObjectName(name, maxCronJobNameLength)
+}
+
+// truncateForSuffix truncates a given base so that a separator and a random suffix still fit in maxLength
+func truncateForSuffix(base string, maxLength int) string {
+	maxBaseLength := maxLength - randomSuffixLength - len(slugSeparator)
+	if len(base) <= maxBaseLength {
+		return base
+	}
+	return strings.TrimRight(base[:maxBaseLength], slugSeparator)
+}
+
+// randomSuffix returns a random suffix drawn from a given source, or from the default one if nil
+func randomSuffix(rnd *rand.Rand) string {
+	intn := rand.Intn
+	if rnd != nil {
+		intn = rnd.Intn
+	}
+
+	suffix := make([]byte, randomSuffixLength)
+	for i := range suffix {
+		suffix[i] = randomSuffixAlphabet[intn(len(randomSuffixAlphabet))]
+	}
+	return string(suffix)
+}
+
+// hashSuffix returns a suffix in the random suffix alphabet derived from the SHA-256 hash of a given string
+func hashSuffix(s string) string {
+	sum := sha256.Sum256([]byte(s))
+
+	suffix := make([]byte, randomSuffixLength)
+	for i := range suffix {
+		suffix[i] = randomSuffixAlphabet[int(sum[i])%len(randomSuffixAlphabet)]
+	}
+	return string(suffix)
+}
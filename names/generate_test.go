@@ -0,0 +1,221 @@
+package names
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateEndpointSliceName(t *testing.T) {
+	tt := []struct {
+		name         string
+		serviceName  string
+		expectedBase string
+		wantErr      error
+	}{
+		{
+			name:         "Short Service name is kept",
+			serviceName:  "webapp",
+			expectedBase: "webapp",
+		},
+		{
+			name:         "Service name at the label limit is truncated to fit the suffix",
+			serviceName:  strings.Repeat("a", 63),
+			expectedBase: strings.Repeat("a", 57),
+		},
+		{
+			name:         "Service name that fits exactly is kept",
+			serviceName:  strings.Repeat("a", 57),
+			expectedBase: strings.Repeat("a", 57),
+		},
+		{
+			name:         "Truncation does not leave a trailing hyphen",
+			serviceName:  strings.Repeat("a", 56) + "-bbbbbb",
+			expectedBase: strings.Repeat("a", 56),
+		},
+		{
+			name:        "Invalid Service name returns an error",
+			serviceName: "web.app",
+			wantErr:     ErrInvalidName,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := GenerateEndpointSliceNameWithRand(tc.serviceName, rand.New(rand.NewSource(1)))
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				assert.Equal(t, "", got)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.True(t, IsValidDNSLabelName(got), got)
+			assert.True(t, strings.HasPrefix(got, tc.expectedBase+"-"), got)
+			assert.Len(t, got, len(tc.expectedBase)+1+randomSuffixLength)
+		})
+	}
+}
+
+func TestGenerateEndpointSliceNameWithRandIsReproducible(t *testing.T) {
+	first, err := GenerateEndpointSliceNameWithRand("webapp", rand.New(rand.NewSource(42)))
+	assert.NoError(t, err)
+	second, err := GenerateEndpointSliceNameWithRand("webapp", rand.New(rand.NewSource(42)))
+	assert.NoError(t, err)
+
+	assert.Equal(t, first, second)
+}
+
+func TestGenerateNameDeterministic(t *testing.T) {
+	tt := []struct {
+		name         string
+		base         string
+		expectedBase string
+		wantErr      error
+	}{
+		{
+			name:         "Base with a trailing separator is kept",
+			base:         "webapp-",
+			expectedBase: "webapp-",
+		},
+		{
+			name:         "Long base is truncated to fit the suffix",
+			base:         strings.Repeat("a", 70),
+			expectedBase: strings.Repeat("a", 58),
+		},
+		{
+			name:    "Invalid base returns an error",
+			base:    "Web_App-",
+			wantErr: ErrInvalidName,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := GenerateNameDeterministic(tc.base, "seed")
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				assert.Equal(t, "", got)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.True(t, IsValidSlug(got), got)
+			assert.True(t, strings.HasPrefix(got, tc.expectedBase), got)
+			assert.Len(t, got, len(tc.expectedBase)+randomSuffixLength)
+		})
+	}
+}
+
+func TestGenerateNameDeterministicIsReproducible(t *testing.T) {
+	first, err := GenerateNameDeterministic("webapp-", "cluster-a")
+	assert.NoError(t, err)
+	second, err := GenerateNameDeterministic("webapp-", "cluster-a")
+	assert.NoError(t, err)
+	other, err := GenerateNameDeterministic("webapp-", "cluster-b")
+	assert.NoError(t, err)
+
+	assert.Equal(t, first, second)
+	assert.NotEqual(t, first, other)
+}
+
+func TestGenerateUniqueName(t *testing.T) {
+	taken := map[string]bool{}
+	exists := func(name string) bool { return taken[name] }
+
+	rnd := rand.New(rand.NewSource(1))
+	first, err := GenerateUniqueNameWithRand("webapp-", exists, rnd)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(first, "webapp-"), first)
+	assert.Len(t, first, len("webapp-")+randomSuffixLength)
+	assert.True(t, IsValidDNSSubdomainName(first), first)
+
+	// the same source would draw the same suffix first, so the taken name is skipped
+	taken[first] = true
+	second, err := GenerateUniqueNameWithRand("webapp-", exists, rand.New(rand.NewSource(1)))
+	assert.NoError(t, err)
+	assert.NotEqual(t, first, second)
+	assert.True(t, strings.HasPrefix(second, "webapp-"), second)
+}
+
+func TestGenerateUniqueNameTruncatesBase(t *testing.T) {
+	got, err := GenerateUniqueName(strings.Repeat("a", 70), func(string) bool { return false })
+
+	assert.NoError(t, err)
+	assert.Len(t, got, maxDNSLabelLength)
+	assert.True(t, strings.HasPrefix(got, strings.Repeat("a", 58)), got)
+}
+
+func TestGenerateUniqueNameErrors(t *testing.T) {
+	_, err := GenerateUniqueName("Web_App-", func(string) bool { return false })
+	assert.ErrorIs(t, err, ErrInvalidName)
+
+	attempts := 0
+	_, err = GenerateUniqueName("webapp-", func(string) bool {
+		attempts++
+		return true
+	})
+	assert.ErrorIs(t, err, ErrNameCollision)
+	assert.Equal(t, maxGenerateNameAttempts, attempts)
+}
+
+func TestGenerateCronJobName(t *testing.T) {
+	tt := []struct {
+		name    string
+		base    string
+		want    string
+		wantErr error
+	}{
+		{
+			name: "Short base is kept",
+			base: "nginx-latest-a3ac8c",
+			want: "nginx-latest-a3ac8c",
+		},
+		{
+			name: "Base at the limit is kept",
+			base: strings.Repeat("a", 52),
+			want: strings.Repeat("a", 52),
+		},
+		{
+			name: "Base over the limit is truncated",
+			base: strings.Repeat("a", 53),
+			want: strings.Repeat("a", 52),
+		},
+		{
+			name: "Truncation does not leave a trailing separator",
+			base: strings.Repeat("a", 51) + "-bbbb",
+			want: strings.Repeat("a", 51),
+		},
+		{
+			name: "Base is sanitized",
+			base: "Scan_Nginx",
+			want: "scan-nginx",
+		},
+		{
+			name:    "Base that sanitizes to nothing returns an error",
+			base:    "///",
+			wantErr: ErrInvalidName,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := GenerateCronJobName(tc.base)
+
+			assert.Equal(t, tc.want, got)
+			assert.ErrorIs(t, err, tc.wantErr)
+			if tc.wantErr == nil {
+				assert.True(t, IsValidCronJobName(got), got)
+			}
+		})
+	}
+}
+
+func TestIsValidCronJobName(t *testing.T) {
+	assert.True(t, IsValidCronJobName(strings.Repeat("a", 52)))
+	assert.False(t, IsValidCronJobName(strings.Repeat("a", 53)))
+	assert.False(t, IsValidCronJobName("Nginx"))
+	assert.False(t, IsValidCronJobName(""))
+}
@@ -0,0 +1,174 @@
+package names
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// dockerHubLibrary is the namespace of official images on Docker Hub, which references may leave out
+	dockerHubLibrary = "library"
+	// legacyDockerHubRegistry is the legacy host of Docker Hub, equivalent to defaultImageRegistry
+	legacyDockerHubRegistry = "index.docker.io"
+)
+
+var (
+	// registryHostRegexp matches registry hosts: DNS names, IPv4 addresses and bracketed IPv6 addresses
+	registryHostRegexp = regexp.MustCompile(`^([a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?)*|\[[a-fA-F0-9:]+\])$`)
+	// registryPortRegexp matches registry ports
+	registryPortRegexp = regexp.MustCompile(`^[0-9]{1,5}$`)
+	// repositoryComponentRegexp matches a path component of a repository as defined by the OCI distribution spec
+	repositoryComponentRegexp = regexp.MustCompile(`^[a-z0-9]+((\.|_|__|-+)[a-z0-9]+)*$`)
+	// tagRegexp matches tags as defined by the OCI distribution spec
+	tagRegexp = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9._-]{0,127}$`)
+	// digestRegexp matches digests as defined by the OCI image spec
+	digestRegexp = regexp.MustCompile(`^[a-z0-9]+([+._-][a-z0-9]+)*:[a-zA-Z0-9=_-]+$`)
+)
+
+// ImageReference holds the components of an OCI image reference
+type ImageReference struct {
+	// Registry is the host of the registry, including its port if any, e.g. "docker.io" or "localhost:5000"
+	Registry string
+	// Repository is the path of the image in the registry, e.g. "library/nginx"
+	Repository string
+	// Tag is the tag of the image, e.g. "latest", empty if the reference does not have one
+	Tag string
+	// Digest is the digest the reference is pinned to, including its algorithm, e.g. "sha256:<hex>", empty if it is not pinned
+	Digest string
+}
+
+// Host returns the host of the registry, without its port
+func (r ImageReference) Host() string {
+	host, _ := r.splitRegistry()
+	return host
+}
+
+// Port returns the port of the registry, empty if the reference does not name one
+func (r ImageReference) Port() string {
+	_, port := r.splitRegistry()
+	return port
+}
+
+// splitRegistry splits the registry into its host and port, minding the colons of bracketed IPv6 addresses
+func (r ImageReference) splitRegistry() (host, port string) {
+	if i := strings.LastIndex(r.Registry, ":"); i > strings.LastIndex(r.Registry, "]") {
+		return r.Registry[:i], r.Registry[i+1:]
+	}
+	return r.Registry, ""
+}
+
+// String returns the reference in its canonical form, e.g. "docker.io/library/nginx:latest@sha256:<hex>"
+func (r ImageReference) String() string {
+	var b strings.Builder
+	if r.Registry != "" {
+		b.WriteString(r.Registry)
+		b.WriteString("/")
+	}
+	b.WriteString(r.Repository)
+	if r.Tag != "" {
+		b.WriteString(":")
+		b.WriteString(r.Tag)
+	}
+	if r.Digest != "" {
+		b.WriteString("@")
+		b.WriteString(r.Digest)
+	}
+	return b.String()
+}
+
+// Platform identifies the platform an image manifest of a multi-platform image is built for
+type Platform struct {
+	// OS is the operating system, e.g. "linux"
+	OS string
+	// Architecture is the CPU architecture, e.g. "arm64"
+	Architecture string
+	// Variant is the variant of the CPU architecture, e.g. "v8", empty if there is none
+	Variant string
+}
+
+// ParsePlatform parses a given platform in the "os/arch[/variant]" format, e.g. "linux/arm64/v8"
+//
+// If the platform does not have an OS and an architecture, it returns an error wrapping ErrInvalidPlatform
+func ParsePlatform(platform string) (Platform, error) {
+	parts := strings.Split(platform, "/")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return Platform{}, fmt.Errorf("%w: %q is not in the os/arch[/variant] format", ErrInvalidPlatform, platform)
+	}
+
+	p := Platform{OS: parts[0], Architecture: parts[1]}
+	if len(parts) == 3 {
+		if parts[2] == "" {
+			return Platform{}, fmt.Errorf("%w: %q has an empty variant", ErrInvalidPlatform, platform)
+		}
+		p.Variant = parts[2]
+	}
+	return p, nil
+}
+
+// String returns the platform in the "os/arch[/variant]" format
+func (p Platform) String() string {
+	if p.Variant == "" {
+		return p.OS + "/" + p.Architecture
+	}
+	return p.OS + "/" + p.Architecture + "/" + p.Variant
+}
+
+// ParseImageReference parses a given image reference into its registry, repository, tag and digest, and validates each of them
+//
+// References are normalized the way container runtimes do: images without a registry are hosted on "docker.io",
+// and official Docker Hub images, such as "nginx", are placed in the "library" namespace. A transport prefix, such as "docker-pullable://", is dropped.
+// The first path component is considered the registry if it looks like a host, that is, it contains a period or a port or it is "localhost".
+// If any component is invalid, it returns an error wrapping ErrInvalidImageReference
+func ParseImageReference(image string) (ImageReference, error) {
+	var ref ImageReference
+	image = normalizeImage(image)
+	if _, withoutTransport, found := strings.Cut(image, "://"); found {
+		image = withoutTransport
+	}
+	image, ref.Digest, _ = strings.Cut(image, "@")
+	if first, rest, found := strings.Cut(image, "/"); found && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		ref.Registry, image = first, rest
+	}
+	// a colon after the last slash separates the tag
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image, ref.Tag = image[:i], image[i+1:]
+	}
+	ref.Repository = image
+
+	if ref.Registry == "" || ref.Registry == legacyDockerHubRegistry {
+		ref.Registry = defaultImageRegistry
+	}
+	if ref.Registry == defaultImageRegistry && !strings.Contains(ref.Repository, "/") {
+		ref.Repository = dockerHubLibrary + "/" + ref.Repository
+	}
+
+	if host, port := ref.splitRegistry(); !registryHostRegexp.MatchString(host) || (port != "" && !registryPortRegexp.MatchString(port)) {
+		return ImageReference{}, fmt.Errorf("%w: invalid registry %q", ErrInvalidImageReference, ref.Registry)
+	}
+	for _, component := range strings.Split(ref.Repository, "/") {
+		if !repositoryComponentRegexp.MatchString(component) {
+			return ImageReference{}, fmt.Errorf("%w: invalid repository %q", ErrInvalidImageReference, ref.Repository)
+		}
+	}
+	if ref.Tag != "" && !tagRegexp.MatchString(ref.Tag) {
+		return ImageReference{}, fmt.Errorf("%w: invalid tag %q", ErrInvalidImageReference, ref.Tag)
+	}
+	if ref.Digest != "" && !isValidDigest(ref.Digest) {
+		return ImageReference{}, fmt.Errorf("%w: invalid digest %q", ErrInvalidImageReference, ref.Digest)
+	}
+
+	return ref, nil
+}
+
+// isValidDigest returns true if a given digest is well-formed, and a full hex-encoded digest for SHA-256
+func isValidDigest(digest string) bool {
+	if !digestRegexp.MatchString(digest) {
+		return false
+	}
+	if strings.HasPrefix(digest, sha256DigestPrefix) {
+		hex := digestHex(digest)
+		return len(hex) == sha256HexLength && isLowerHexOfLength(hex, sha256HexLength)
+	}
+	return true
+}
@@ -0,0 +1,161 @@
+package names
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseImageReference(t *testing.T) {
+	digest := "sha256:f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac8c"
+	tt := []struct {
+		name    string
+		image   string
+		want    ImageReference
+		wantErr error
+	}{
+		{
+			name:  "official image is normalized",
+			image: "nginx",
+			want:  ImageReference{Registry: "docker.io", Repository: "library/nginx"},
+		},
+		{
+			name:  "Docker Hub image is placed in its registry",
+			image: "kubescape/kubevuln:v0.2.108",
+			want:  ImageReference{Registry: "docker.io", Repository: "kubescape/kubevuln", Tag: "v0.2.108"},
+		},
+		{
+			name:  "legacy Docker Hub host is normalized",
+			image: "index.docker.io/nginx:latest",
+			want:  ImageReference{Registry: "docker.io", Repository: "library/nginx", Tag: "latest"},
+		},
+		{
+			name:  "registry with a port",
+			image: "localhost:5000/nginx:1.25",
+			want:  ImageReference{Registry: "localhost:5000", Repository: "nginx", Tag: "1.25"},
+		},
+		{
+			name:  "digest-pinned image",
+			image: "quay.io/kubescape/kubevuln@" + digest,
+			want:  ImageReference{Registry: "quay.io", Repository: "kubescape/kubevuln", Digest: digest},
+		},
+		{
+			name:  "tag and digest with a transport prefix",
+			image: "docker-pullable://nginx:1.25@" + digest,
+			want:  ImageReference{Registry: "docker.io", Repository: "library/nginx", Tag: "1.25", Digest: digest},
+		},
+		{
+			name:    "uppercase repository is invalid",
+			image:   "docker.io/myorg/MyApp:latest",
+			wantErr: ErrInvalidImageReference,
+		},
+		{
+			name:    "invalid tag",
+			image:   "nginx:-latest",
+			wantErr: ErrInvalidImageReference,
+		},
+		{
+			name:    "truncated digest",
+			image:   "nginx@sha256:f4e3b6",
+			wantErr: ErrInvalidImageReference,
+		},
+		{
+			name:    "invalid port",
+			image:   "localhost:http/nginx",
+			wantErr: ErrInvalidImageReference,
+		},
+		{
+			name:    "empty reference",
+			image:   "",
+			wantErr: ErrInvalidImageReference,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseImageReference(tc.image)
+
+			assert.Equal(t, tc.want, got)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestImageReferenceRegistry(t *testing.T) {
+	tt := []struct {
+		registry string
+		wantHost string
+		wantPort string
+	}{
+		{registry: "docker.io", wantHost: "docker.io"},
+		{registry: "localhost:5000", wantHost: "localhost", wantPort: "5000"},
+		{registry: "[::1]:5000", wantHost: "[::1]", wantPort: "5000"},
+		{registry: "[::1]", wantHost: "[::1]"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.registry, func(t *testing.T) {
+			ref := ImageReference{Registry: tc.registry}
+
+			assert.Equal(t, tc.wantHost, ref.Host())
+			assert.Equal(t, tc.wantPort, ref.Port())
+		})
+	}
+}
+
+func TestImageReferenceString(t *testing.T) {
+	digest := "sha256:f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac8c"
+
+	ref, err := ParseImageReference("nginx:latest@" + digest)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "docker.io/library/nginx:latest@"+digest, ref.String())
+}
+
+func TestParsePlatform(t *testing.T) {
+	tt := []struct {
+		name     string
+		platform string
+		want     Platform
+		wantErr  bool
+	}{
+		{
+			name:     "OS and architecture",
+			platform: "linux/amd64",
+			want:     Platform{OS: "linux", Architecture: "amd64"},
+		},
+		{
+			name:     "OS, architecture and variant",
+			platform: "linux/arm64/v8",
+			want:     Platform{OS: "linux", Architecture: "arm64", Variant: "v8"},
+		},
+		{
+			name:     "Missing architecture",
+			platform: "linux",
+			wantErr:  true,
+		},
+		{
+			name:     "Empty variant",
+			platform: "linux/arm/",
+			wantErr:  true,
+		},
+		{
+			name:     "Too many components",
+			platform: "linux/arm/v7/extra",
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParsePlatform(tc.platform)
+			if tc.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidPlatform)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+			assert.Equal(t, tc.platform, got.String())
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package names
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Kind is a Kubernetes resource kind, as used in instance ID slugs
+type Kind string
+
+const (
+	KindPod                Kind = "Pod"
+	KindService            Kind = "Service"
+	KindDeployment         Kind = "Deployment"
+	KindReplicaSet         Kind = "ReplicaSet"
+	KindStatefulSet        Kind = "StatefulSet"
+	KindDaemonSet          Kind = "DaemonSet"
+	KindJob                Kind = "Job"
+	KindCronJob            Kind = "CronJob"
+	KindNamespace          Kind = "Namespace"
+	KindNode               Kind = "Node"
+	KindConfigMap          Kind = "ConfigMap"
+	KindSecret             Kind = "Secret"
+	KindServiceAccount     Kind = "ServiceAccount"
+	KindRole               Kind = "Role"
+	KindRoleBinding        Kind = "RoleBinding"
+	KindClusterRole        Kind = "ClusterRole"
+	KindClusterRoleBinding Kind = "ClusterRoleBinding"
+)
+
+// knownKinds maps the lowercase form of every known kind to its canonical form
+var knownKinds = func() map[string]Kind {
+	kinds := []Kind{
+		KindPod, KindService, KindDeployment, KindReplicaSet, KindStatefulSet, KindDaemonSet, KindJob, KindCronJob,
+		KindNamespace, KindNode, KindConfigMap, KindSecret, KindServiceAccount,
+		KindRole, KindRoleBinding, KindClusterRole, KindClusterRoleBinding,
+	}
+	byName := make(map[string]Kind, len(kinds))
+	for _, kind := range kinds {
+		byName[strings.ToLower(string(kind))] = kind
+	}
+	return byName
+}()
+
+// String returns the kind as a string
+func (k Kind) String() string {
+	return string(k)
+}
+
+// IsKnown returns true if the kind is one of the kinds defined by this package
+func (k Kind) IsKnown() bool {
+	known, ok := knownKinds[strings.ToLower(string(k))]
+	return ok && known == k
+}
+
+// ParseKind returns the known kind matching a given string regardless of its capitalization
+//
+// If the string does not match any known kind, it returns ErrUnknownKind
+func ParseKind(s string) (Kind, error) {
+	if kind, ok := knownKinds[strings.ToLower(s)]; ok {
+		return kind, nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrUnknownKind, s)
+}
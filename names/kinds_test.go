@@ -0,0 +1,54 @@
+package names
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseKind(t *testing.T) {
+	tt := []struct {
+		name    string
+		input   string
+		want    Kind
+		wantErr error
+	}{
+		{
+			name:  "Lowercase kind is parsed",
+			input: "pod",
+			want:  KindPod,
+		},
+		{
+			name:  "Uppercase kind is parsed",
+			input: "POD",
+			want:  KindPod,
+		},
+		{
+			name:  "Mixed case kind is parsed",
+			input: "clusterRoleBinding",
+			want:  KindClusterRoleBinding,
+		},
+		{
+			name:    "Unknown kind returns an error",
+			input:   "Widget",
+			want:    "",
+			wantErr: ErrUnknownKind,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseKind(tc.input)
+
+			assert.Equal(t, tc.want, got)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestKindIsKnown(t *testing.T) {
+	assert.True(t, KindDeployment.IsKnown())
+	assert.False(t, Kind("deployment").IsKnown())
+	assert.False(t, Kind("Widget").IsKnown())
+	assert.Equal(t, "Deployment", KindDeployment.String())
+}
@@ -0,0 +1,247 @@
+package names
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Options holds the settings used when generating slugs
+type Options struct {
+	// MaxLength is the maximum length of a generated slug
+	MaxLength int
+	// HideDefaultRegistry drops the registry from image slugs when it matches DefaultRegistry
+	HideDefaultRegistry bool
+	// DefaultRegistry is the registry considered the default one for images
+	DefaultRegistry string
+	// StrictLabels rejects slugs with a period-separated label longer than a DNS label
+	StrictLabels bool
+	// EmptyPlaceholder is returned by sanitizers for inputs that sanitize to nothing, which are rejected if it is empty
+	EmptyPlaceholder string
+	// HashFromFront takes the hash suffix of image slugs from the beginning of the hash instead of its end
+	HashFromFront bool
+	// Separator separates the segments of a slug, "-" unless set
+	Separator string
+	// HashSuffixLength is the length of the hash suffix of image slugs and of each hash segment of instance ID slugs,
+	// 6 and 4 respectively unless set
+	HashSuffixLength int
+	// HashSuffixFunc derives the hash suffix from the full hash, replacing the hash suffix of image slugs
+	// and both hash segments of instance ID slugs, unless nil
+	HashSuffixFunc func(hash string) (string, error)
+}
+
+// Config is a snapshot of the effective settings that produce a slug, meant for logging and debugging
+type Config struct {
+	// Separator separates the segments of a slug
+	Separator string
+	// ImageHashLength is the length of the hash suffix of image slugs
+	ImageHashLength int
+	// InstanceHashLength is the length of each of the two hash segments of instance ID slugs
+	InstanceHashLength int
+	// MaxLength is the maximum length of a slug
+	MaxLength int
+	// MaxLabelLength is the maximum length of a DNS label
+	MaxLabelLength int
+}
+
+// DefaultConfig returns the settings used to generate slugs when no options are given
+func DefaultConfig() Config {
+	return defaultOptions().Config()
+}
+
+// Config returns the effective settings the options resolve to
+func (o Options) Config() Config {
+	return Config{
+		Separator:          o.separator(),
+		ImageHashLength:    o.imageHashLength(),
+		InstanceHashLength: o.instanceHashLength(),
+		MaxLength:          o.MaxLength,
+		MaxLabelLength:     maxDNSLabelLength,
+	}
+}
+
+// separator returns the separator of slug segments, falling back to the default one
+func (o Options) separator() string {
+	if o.Separator == "" {
+		return slugSeparator
+	}
+	return o.Separator
+}
+
+// imageHashLength returns the length of the hash suffix of image slugs, falling back to the default one
+func (o Options) imageHashLength() int {
+	if o.HashSuffixLength == 0 {
+		return imageIDSlugHashLength
+	}
+	return o.HashSuffixLength
+}
+
+// instanceHashLength returns the length of each hash segment of instance ID slugs, falling back to the default one
+func (o Options) instanceHashLength() int {
+	if o.HashSuffixLength == 0 {
+		return slugHashLength
+	}
+	return o.HashSuffixLength
+}
+
+// hashSuffix returns the hash suffix that HashSuffixFunc derives from a given hash, lowercased and validated
+func (o Options) hashSuffix(hash string) (string, error) {
+	suffix, err := o.HashSuffixFunc(hash)
+	if err != nil {
+		return "", fmt.Errorf("%w: hash suffix func: %w", ErrInvalidHash, err)
+	}
+	// unlike DNS labels, suffixes are not limited to 63 characters, so a full SHA-256 hash fits
+	switch suffix = strings.ToLower(suffix); {
+	case suffix == "":
+		return "", newInvalidInputError(ErrInvalidHash, "hashSuffix", ReasonEmpty)
+	case len(suffix) > maxDNSSubdomainLength:
+		return "", newInvalidInputError(ErrInvalidHash, "hashSuffix", ReasonTooLong)
+	case !IsValidDNSLabelNameMax(suffix, maxDNSSubdomainLength):
+		return "", newInvalidInputError(ErrInvalidHash, "hashSuffix", ReasonInvalidCharacters)
+	}
+	return suffix, nil
+}
+
+// checkLabels returns ErrNameTooLong for a given field if labels are strict and a period-separated label of a given slug is longer than a DNS label
+func (o Options) checkLabels(slug, field string) error {
+	if !o.StrictLabels {
+		return nil
+	}
+	if longest, _ := LongestLabelLength(slug); longest > maxDNSLabelLength {
+		return newInvalidInputError(ErrNameTooLong, field, ReasonTooLong)
+	}
+	return nil
+}
+
+// Option configures how slugs are generated
+type Option func(*Options)
+
+// WithMaxLen overrides the maximum length of a generated slug
+//
+// Slugs that would overflow n are truncated so that the hash suffix is preserved.
+// The default is the DNS Subdomain name limit of 253 characters
+func WithMaxLen(n int) Option {
+	return func(o *Options) {
+		o.MaxLength = n
+	}
+}
+
+// WithHideDefaultRegistry drops the registry from image slugs when it is the default registry
+//
+// For example, "docker.io/nginx:latest" produces "nginx-latest-a3ac8c" instead of "docker.io-nginx-latest-a3ac8c",
+// while images from other registries are kept intact. Since the registry is no longer part of the slug,
+// the original image can only be recovered from such a slug if the default registry is known
+func WithHideDefaultRegistry() Option {
+	return func(o *Options) {
+		o.HideDefaultRegistry = true
+	}
+}
+
+// WithDefaultRegistry sets the registry considered the default one, "docker.io" unless set
+func WithDefaultRegistry(registry string) Option {
+	return func(o *Options) {
+		o.DefaultRegistry = registry
+	}
+}
+
+// WithStrictLabels rejects slugs in which any period-separated label is longer than 63 characters with ErrNameTooLong
+//
+// Such slugs are valid DNS Subdomain names as a whole, but not valid hostnames, so they are caught at generation time.
+// It applies to image and instance ID slugs, to NameTemplate and to SlugBuilder
+func WithStrictLabels() Option {
+	return func(o *Options) {
+		o.StrictLabels = true
+	}
+}
+
+// WithHashFromFront takes the hash suffix of image slugs from the first characters of the hash instead of the last ones
+//
+// It matches systems that key images by the beginning of their digest, e.g. "f4e3b6" rather than "a3ac8c"
+// for a digest of "f4e3b6...a3ac8c". Slugs generated with and without it differ, so it must be used consistently
+func WithHashFromFront() Option {
+	return func(o *Options) {
+		o.HashFromFront = true
+	}
+}
+
+// WithSeparator overrides the separator of slug segments, such as "_"
+//
+// The separator must not contain alphanumeric characters. Slugs are validated as if their separators were hyphens,
+// so a separator other than "-" or "." produces slugs that are no longer valid Kubernetes names
+func WithSeparator(separator string) Option {
+	return func(o *Options) {
+		o.Separator = separator
+	}
+}
+
+// WithHashSuffixLen overrides the length of the hash suffix of image slugs and of each hash segment of instance ID slugs
+//
+// Shorter hashes fit more of the name into length-constrained slugs, such as 63 character labels, at the cost of more collisions
+func WithHashSuffixLen(n int) Option {
+	return func(o *Options) {
+		o.HashSuffixLength = n
+	}
+}
+
+// WithHashSuffixFunc derives the hash suffix of slugs from the full hash with a given function, e.g. to use the full hash or a base36-encoded one
+//
+// The suffix replaces the hash suffix of image slugs and both hash segments of instance ID slugs, so WithHashSuffixLen and WithHashFromFront no longer apply.
+// It is lowercased and must follow the DNS label name rules, except for the length. Slugs are still validated and truncated to keep the suffix intact.
+// If the function fails or returns an invalid suffix, slug generation returns an error wrapping ErrInvalidHash
+func WithHashSuffixFunc(hashSuffixFunc func(hash string) (string, error)) Option {
+	return func(o *Options) {
+		o.HashSuffixFunc = hashSuffixFunc
+	}
+}
+
+// DefaultEmptyPlaceholder is the suggested placeholder for inputs that sanitize to nothing
+const DefaultEmptyPlaceholder = "unknown"
+
+// WithEmptyPlaceholder makes sanitizers return a given placeholder instead of an error when an input sanitizes to nothing
+//
+// For example, a tag of "///" sanitizes to nothing and would leave a double separator when joined with other segments.
+// The placeholder must be a valid DNS label name itself, an empty placeholder standing for DefaultEmptyPlaceholder
+func WithEmptyPlaceholder(placeholder string) Option {
+	if placeholder == "" {
+		placeholder = DefaultEmptyPlaceholder
+	}
+	return func(o *Options) {
+		o.EmptyPlaceholder = placeholder
+	}
+}
+
+// defaultOptions returns the options used when none are given
+func defaultOptions() Options {
+	return Options{
+		MaxLength:       maxDNSSubdomainLength,
+		DefaultRegistry: defaultImageRegistry,
+	}
+}
+
+// resolveOptions applies the given options on top of the defaults
+func resolveOptions(opts []Option) Options {
+	// without options, skip the options variable below, which escapes to the heap
+	if len(opts) == 0 {
+		return defaultOptions()
+	}
+	options := defaultOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// ResolveOptions returns the options the given ones resolve to when applied on top of the defaults
+//
+// The result can be used as a shared baseline and passed on with FromOptions
+func ResolveOptions(opts ...Option) Options {
+	return resolveOptions(opts)
+}
+
+// FromOptions starts from the given resolved options, so that a shared baseline can be reused and overridden per call
+//
+// It replaces all settings, so it should come before any options that override the baseline
+func FromOptions(base Options) Option {
+	return func(o *Options) {
+		*o = base
+	}
+}
@@ -0,0 +1,51 @@
+package names
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultConfig(t *testing.T) {
+	expected := Config{
+		Separator:          "-",
+		ImageHashLength:    6,
+		InstanceHashLength: 4,
+		MaxLength:          253,
+		MaxLabelLength:     63,
+	}
+
+	assert.Equal(t, expected, DefaultConfig())
+}
+
+func TestOptionsConfig(t *testing.T) {
+	options := resolveOptions([]Option{WithMaxLen(128)})
+
+	config := options.Config()
+
+	assert.Equal(t, 128, config.MaxLength)
+	assert.Equal(t, DefaultConfig().Separator, config.Separator)
+}
+
+func TestResolveOptionsFromBaseline(t *testing.T) {
+	baseline := ResolveOptions(WithHideDefaultRegistry(), WithDefaultRegistry("quay.io"), WithMaxLen(128))
+
+	options := ResolveOptions(FromOptions(baseline), WithMaxLen(64))
+
+	assert.Equal(t, 64, options.MaxLength)
+	assert.True(t, options.HideDefaultRegistry)
+	assert.Equal(t, "quay.io", options.DefaultRegistry)
+	assert.Equal(t, 128, baseline.MaxLength)
+}
+
+func TestResolveOptionsDefaults(t *testing.T) {
+	assert.Equal(t, defaultOptions(), ResolveOptions())
+}
+
+func TestOptionsConfigWithSeparatorAndHashSuffixLen(t *testing.T) {
+	config := ResolveOptions(WithSeparator("_"), WithHashSuffixLen(8)).Config()
+
+	assert.Equal(t, "_", config.Separator)
+	assert.Equal(t, 8, config.ImageHashLength)
+	assert.Equal(t, 8, config.InstanceHashLength)
+}
@@ -0,0 +1,45 @@
+package names
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OwnerRef identifies an object in an ownership chain, such as a Deployment owning a ReplicaSet
+type OwnerRef struct {
+	Kind string
+	Name string
+}
+
+// SlugFromOwnerChain returns a human-friendly representation of an object given its ownership chain
+//
+// The chain is ordered from the top owner to the object itself, e.g. Deployment, ReplicaSet, Pod.
+// The slug contains the kind and name of the top owner followed by those of the object and the hash-based identifiers,
+// and is truncated to fit a DNS Subdomain name. The hash-based identifiers are always present, even for a chain of a single object.
+// If the chain is empty or any of its objects has an invalid kind or name, it returns an appropriate error
+func SlugFromOwnerChain(chain []OwnerRef, hashedID string) (string, error) {
+	if len(chain) == 0 {
+		return "", fmt.Errorf("%w: empty owner chain", ErrInvalidSlug)
+	}
+	for _, ref := range chain {
+		if !IsValidDNSLabelName(strings.ToLower(ref.Kind)) || !isValidObjectName(ref.Name, maxDNSSubdomainLength) {
+			return "", fmt.Errorf("%w: invalid owner %s/%s", ErrInvalidSlug, ref.Kind, ref.Name)
+		}
+	}
+
+	top, leaf := chain[0], chain[len(chain)-1]
+	if len(chain) == 1 {
+		if len(hashedID) < slugHashLength {
+			return "", newInvalidInputError(ErrInvalidSlug, "hashedID", ReasonTooShort)
+		}
+		// the name of the object takes the place of the container, so the hash is present as well
+		hashParts := []string{hashedID[:slugHashLength], hashedID[len(hashedID)-slugHashLength:]}
+		slug := buildInstanceIDSlug([]string{top.Kind}, top.Name, hashParts, slugSeparator, maxDNSSubdomainLength)
+		if !IsValidSlug(slug) {
+			return "", fmt.Errorf("%w: invalid owner %s/%s", ErrInvalidSlug, top.Kind, top.Name)
+		}
+		return slug, nil
+	}
+	// the object itself takes the place of the container, so the hash is always present
+	return InstanceIDToSlug(top.Name, top.Kind, fmt.Sprintf(instanceIDSlugHashlessFormat, leaf.Kind, leaf.Name), hashedID)
+}
@@ -0,0 +1,77 @@
+package names
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlugFromOwnerChain(t *testing.T) {
+	hashedID := "1ba506b28f9ee9c7e8a0c98840fe5a1fe21142d225ecc526fbb535d0d6344aaf"
+	tt := []struct {
+		name    string
+		chain   []OwnerRef
+		want    string
+		wantErr error
+	}{
+		{
+			name: "two-level chain contains the owner and the object",
+			chain: []OwnerRef{
+				{Kind: "ReplicaSet", Name: "nginx-7c5ddbdf54"},
+				{Kind: "Pod", Name: "nginx-7c5ddbdf54-x2v9q"},
+			},
+			want: "replicaset-nginx-7c5ddbdf54-pod-nginx-7c5ddbdf54-x2v9q-1ba5-4aaf",
+		},
+		{
+			name: "intermediate owners are skipped",
+			chain: []OwnerRef{
+				{Kind: "Deployment", Name: "nginx"},
+				{Kind: "ReplicaSet", Name: "nginx-7c5ddbdf54"},
+				{Kind: "Pod", Name: "nginx-7c5ddbdf54-x2v9q"},
+			},
+			want: "deployment-nginx-pod-nginx-7c5ddbdf54-x2v9q-1ba5-4aaf",
+		},
+		{
+			name:  "single object contains the hash",
+			chain: []OwnerRef{{Kind: "Deployment", Name: "web"}},
+			want:  "deployment-web-1ba5-4aaf",
+		},
+		{
+			name:  "long single object is truncated to a valid slug",
+			chain: []OwnerRef{{Kind: "Pod", Name: strings.Repeat("a", 253)}},
+			want:  "pod-" + strings.Repeat("a", 239) + "-1ba5-4aaf",
+		},
+		{
+			name: "long chain is truncated to a valid slug",
+			chain: []OwnerRef{
+				{Kind: "Deployment", Name: strings.Repeat("a", 200)},
+				{Kind: "Pod", Name: strings.Repeat("b", 200)},
+			},
+			want: "deployment-" + strings.Repeat("a", 200) + "-pod-" + strings.Repeat("b", 27) + "-1ba5-4aaf",
+		},
+		{
+			name:    "empty chain produces matching error",
+			chain:   nil,
+			wantErr: ErrInvalidSlug,
+		},
+		{
+			name:    "invalid name produces matching error",
+			chain:   []OwnerRef{{Kind: "Pod", Name: "Nginx_1"}},
+			wantErr: ErrInvalidSlug,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := SlugFromOwnerChain(tc.chain, hashedID)
+
+			assert.Equal(t, tc.want, got)
+			assert.ErrorIs(t, err, tc.wantErr)
+			if tc.wantErr == nil {
+				assert.True(t, IsValidSlug(got))
+				assert.LessOrEqual(t, len(got), maxDNSSubdomainLength)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package names
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// firstDisambiguator is the number appended to the second slug issued for the same name
+const firstDisambiguator = 2
+
+// NameRegistry keeps track of issued slugs and the sources they were generated from, disambiguating slugs that collide
+//
+// When a slug is already issued for a different source, the registry appends an incrementing segment, e.g. "nginx-a3ac8c-2",
+// so the outcome only depends on the order of registration. Slugs too long for the segment are truncated before their hash suffix,
+// which is kept intact. It is safe for concurrent use
+type NameRegistry struct {
+	mu       sync.Mutex
+	bySlug   map[string]string
+	bySource map[string]string
+}
+
+// NewNameRegistry returns an empty NameRegistry
+func NewNameRegistry() *NameRegistry {
+	return &NameRegistry{
+		bySlug:   make(map[string]string),
+		bySource: make(map[string]string),
+	}
+}
+
+// Register issues a given slug for a given source, such as the full image or instance ID it was generated from, and returns the issued slug
+//
+// Registering a source again returns the slug issued for it before. If the slug is issued for another source,
+// a disambiguated slug is issued instead. If the slug is not valid, it returns ErrInvalidSlug
+func (r *NameRegistry) Register(source, slug string) (string, error) {
+	if !IsValidSlug(slug) {
+		return "", ErrInvalidSlug
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if issued, ok := r.bySource[source]; ok {
+		return issued, nil
+	}
+
+	issued := slug
+	for n := firstDisambiguator; ; n++ {
+		if _, taken := r.bySlug[issued]; !taken {
+			break
+		}
+		var err error
+		if issued, err = disambiguate(slug, n); err != nil {
+			return "", err
+		}
+	}
+
+	r.bySlug[issued] = source
+	r.bySource[source] = issued
+	return issued, nil
+}
+
+// disambiguate returns a given slug with a given disambiguator appended, truncating the slug before its hash suffix to fit if needed
+//
+// Slugs without a hash suffix, see ClassifySlug, are truncated from their end
+func disambiguate(slug string, n int) (string, error) {
+	hashSegments := 0
+	if kind, err := ClassifySlug(slug); err == nil {
+		hashSegments = 1
+		if kind == InstanceSlug {
+			hashSegments = 2
+		}
+	}
+
+	segments := strings.Split(slug, slugSeparator)
+	prefix := strings.Join(segments[:len(segments)-hashSegments], slugSeparator)
+	suffix := strings.Join(append(segments[len(segments)-hashSegments:], strconv.Itoa(n)), slugSeparator)
+	return AppendSuffix(prefix, suffix)
+}
+
+// Lookup returns the source a given slug was issued for, and false if it was not issued
+func (r *NameRegistry) Lookup(slug string) (source string, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	source, ok = r.bySlug[slug]
+	return source, ok
+}
+
+// Release forgets a given issued slug, so that it can be issued again
+func (r *NameRegistry) Release(slug string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if source, ok := r.bySlug[slug]; ok {
+		delete(r.bySlug, slug)
+		delete(r.bySource, source)
+	}
+}
+
+// Len returns the number of issued slugs
+func (r *NameRegistry) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.bySlug)
+}
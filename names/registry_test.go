@@ -0,0 +1,114 @@
+package names
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNameRegistry(t *testing.T) {
+	registry := NewNameRegistry()
+
+	first, err := registry.Register("docker.io/library/nginx:latest", "nginx-latest-a3ac8c")
+	assert.NoError(t, err)
+	assert.Equal(t, "nginx-latest-a3ac8c", first)
+
+	second, err := registry.Register("nginx:latest", "nginx-latest-a3ac8c")
+	assert.NoError(t, err)
+	assert.Equal(t, "nginx-latest-a3ac8c-2", second)
+
+	third, err := registry.Register("docker.io/nginx:latest", "nginx-latest-a3ac8c")
+	assert.NoError(t, err)
+	assert.Equal(t, "nginx-latest-a3ac8c-3", third)
+
+	again, err := registry.Register("nginx:latest", "nginx-latest-a3ac8c")
+	assert.NoError(t, err)
+	assert.Equal(t, second, again)
+
+	source, ok := registry.Lookup(second)
+	assert.True(t, ok)
+	assert.Equal(t, "nginx:latest", source)
+	assert.Equal(t, 3, registry.Len())
+
+	registry.Release(second)
+	_, ok = registry.Lookup(second)
+	assert.False(t, ok)
+	assert.Equal(t, 2, registry.Len())
+
+	reissued, err := registry.Register("quay.io/nginx:latest", "nginx-latest-a3ac8c")
+	assert.NoError(t, err)
+	assert.Equal(t, second, reissued)
+}
+
+func TestNameRegistryKeepsHashOfLongSlugs(t *testing.T) {
+	tt := []struct {
+		name       string
+		slug       string
+		hashSuffix string
+	}{
+		{
+			name:       "Image slug",
+			slug:       strings.Repeat("a", 246) + "-a3ac8c",
+			hashSuffix: "-a3ac8c",
+		},
+		{
+			name:       "Instance ID slug",
+			slug:       "pod-" + strings.Repeat("a", 239) + "-1ba5-4aaf",
+			hashSuffix: "-1ba5-4aaf",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Len(t, tc.slug, maxDNSSubdomainLength)
+			registry := NewNameRegistry()
+
+			first, err := registry.Register("first", tc.slug)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.slug, first)
+
+			issued := map[string]bool{first: true}
+			for i := 2; i <= 11; i++ {
+				got, err := registry.Register(fmt.Sprintf("source-%d", i), tc.slug)
+				assert.NoError(t, err)
+				assert.LessOrEqual(t, len(got), maxDNSSubdomainLength)
+				assert.True(t, IsValidSlug(got), got)
+				assert.True(t, strings.HasSuffix(got, fmt.Sprintf("%s-%d", tc.hashSuffix, i)), got)
+				assert.False(t, issued[got], got)
+				issued[got] = true
+			}
+		})
+	}
+}
+
+func TestNameRegistryRejectsInvalidSlugs(t *testing.T) {
+	registry := NewNameRegistry()
+
+	_, err := registry.Register("nginx:latest", "nginx:latest")
+
+	assert.ErrorIs(t, err, ErrInvalidSlug)
+	assert.Equal(t, 0, registry.Len())
+}
+
+func TestNameRegistryIsConcurrencySafe(t *testing.T) {
+	registry := NewNameRegistry()
+
+	var wg sync.WaitGroup
+	issued := make([]string, 50)
+	for i := range issued {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			slug, err := registry.Register(fmt.Sprintf("source-%d", i), "nginx-latest-a3ac8c")
+			assert.NoError(t, err)
+			issued[i] = slug
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, len(issued), registry.Len())
+	assert.Empty(t, FindSlugCollisions(issued))
+}
@@ -0,0 +1,91 @@
+package names
+
+import (
+	"strings"
+	"unicode"
+)
+
+// transliterations maps common non-ASCII letters to their closest ASCII spelling
+//
+// Letters with diacritics that are not listed here are stripped of their diacritics when possible, any other rune is replaced
+var transliterations = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'Æ': "ae", 'œ': "oe", 'Œ': "oe", 'ø': "o", 'Ø': "o", 'ł': "l", 'Ł': "l",
+	'đ': "d", 'Đ': "d", 'ð': "d", 'Ð': "d", 'þ': "th", 'Þ': "th", 'ı': "i",
+}
+
+// diacriticBases maps letters with diacritics to the letter they are based on
+var diacriticBases = func() map[rune]rune {
+	bases := make(map[rune]rune)
+	for base, variants := range map[rune]string{
+		'a': "àáâãäåāăąÀÁÂÃÄÅĀĂĄ",
+		'c': "çćĉċčÇĆĈĊČ",
+		'e': "èéêëēĕėęěÈÉÊËĒĔĖĘĚ",
+		'g': "ĝğġģĜĞĠĢ",
+		'i': "ìíîïĩīĭįÌÍÎÏĨĪĬĮİ",
+		'n': "ñńņňÑŃŅŇ",
+		'o': "òóôõöōŏőÒÓÔÕÖŌŎŐ",
+		'r': "ŕŗřŔŖŘ",
+		's': "śŝşšŚŜŞŠ",
+		't': "ţťŢŤ",
+		'u': "ùúûüũūŭůűųÙÚÛÜŨŪŬŮŰŲ",
+		'y': "ýÿŷÝŸŶ",
+		'z': "źżžŹŻŽ",
+	} {
+		for _, variant := range variants {
+			bases[variant] = base
+		}
+	}
+	return bases
+}()
+
+// SanitizeToDNSSubdomain transforms arbitrary input, such as user-provided strings, into a valid DNS Subdomain name
+//
+// Unlike ToValidDNSSubdomainName, it transliterates common non-ASCII letters, e.g. "Café" becomes "cafe", before
+// lowercasing, replacing invalid characters with hyphens and trimming, and it truncates every label to 63 characters,
+// so the result passes IsValidDNSSubdomainName. If nothing is left, it returns an error
+func SanitizeToDNSSubdomain(input string) (string, error) {
+	name, err := ToValidDNSSubdomainName(transliterate(input))
+	if err != nil {
+		return "", err
+	}
+	return truncateDNSSubdomainLabels(name), nil
+}
+
+// truncateDNSSubdomainLabels truncates every period-separated label of a given valid object name to the length of a DNS label
+func truncateDNSSubdomainLabels(name string) string {
+	labels := strings.Split(name, ".")
+	for i, label := range labels {
+		if len(label) > maxDNSLabelLength {
+			// truncation must not leave a hyphen at the end of the label
+			labels[i] = strings.TrimRight(label[:maxDNSLabelLength], "-")
+		}
+	}
+	return strings.Join(labels, ".")
+}
+
+// SanitizeToDNSLabel transforms arbitrary input, such as user-provided strings, into a valid DNS label name
+//
+// It works like SanitizeToDNSSubdomain, but periods are replaced too and the result is at most 63 characters long.
+// The given options are passed on to ToValidDNSLabelName, e.g. WithEmptyPlaceholder
+func SanitizeToDNSLabel(input string, opts ...Option) (string, error) {
+	return ToValidDNSLabelName(transliterate(input), opts...)
+}
+
+// transliterate replaces non-ASCII letters of a given input with their closest ASCII spelling, leaving other runes untouched
+func transliterate(input string) string {
+	var b strings.Builder
+	b.Grow(len(input))
+	for _, r := range input {
+		switch {
+		case r <= unicode.MaxASCII:
+			b.WriteRune(r)
+		case transliterations[r] != "":
+			b.WriteString(transliterations[r])
+		case diacriticBases[r] != 0:
+			b.WriteRune(diacriticBases[r])
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
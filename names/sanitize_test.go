@@ -0,0 +1,118 @@
+package names
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitizeToDNSSubdomain(t *testing.T) {
+	tt := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "valid name is unchanged",
+			input: "docker.io",
+			want:  "docker.io",
+		},
+		{
+			name:  "uppercase, slashes and colons are sanitized",
+			input: "Docker.io/Library/Nginx:Latest",
+			want:  "docker.io-library-nginx-latest",
+		},
+		{
+			name:  "letters with diacritics are transliterated",
+			input: "Café Crème",
+			want:  "cafe-creme",
+		},
+		{
+			name:  "ligatures and special letters are transliterated",
+			input: "Straße-Æsir-Øresund",
+			want:  "strasse-aesir-oresund",
+		},
+		{
+			name:  "other unicode runes are replaced",
+			input: "app-日本",
+			want:  "app",
+		},
+		{
+			name:  "labels over 63 characters are truncated",
+			input: strings.Repeat("a", 62) + "-b" + ".io/" + strings.Repeat("c", 70),
+			want:  strings.Repeat("a", 62) + ".io-" + strings.Repeat("c", 60),
+		},
+		{
+			name:    "input without any usable rune returns an error",
+			input:   "日本",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := SanitizeToDNSSubdomain(tc.input)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+			assert.True(t, IsValidDNSSubdomainName(got), got)
+		})
+	}
+}
+
+func TestSanitizeToDNSLabel(t *testing.T) {
+	tt := []struct {
+		name    string
+		input   string
+		opts    []Option
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "periods are replaced",
+			input: "Docker.io/Nginx",
+			want:  "docker-io-nginx",
+		},
+		{
+			name:  "letters with diacritics are transliterated",
+			input: "Zürich",
+			want:  "zurich",
+		},
+		{
+			name:  "long input is truncated",
+			input: strings.Repeat("é", 70),
+			want:  strings.Repeat("e", 63),
+		},
+		{
+			name:    "input without any usable rune returns an error",
+			input:   "///",
+			wantErr: true,
+		},
+		{
+			name:  "input without any usable rune returns the placeholder",
+			input: "///",
+			opts:  []Option{WithEmptyPlaceholder(DefaultEmptyPlaceholder)},
+			want:  DefaultEmptyPlaceholder,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := SanitizeToDNSLabel(tc.input, tc.opts...)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+			assert.True(t, IsValidDNSLabelName(got), got)
+		})
+	}
+}
@@ -4,8 +4,11 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"hash/crc32"
+	"math"
 	"regexp"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/kubescape/k8s-interface/workloadinterface"
 )
@@ -21,27 +24,50 @@ const (
 	slugHashesLength        = slugHashLength*2 + 2
 	maxHashlessStringLength = maxDNSSubdomainLength - slugHashesLength
 
+	// slugSeparator separates the segments of a slug
+	slugSeparator = "-"
+
 	// imageIDSlugFormat is a format of the Image ID slug
 	imageIDSlugFormat     = "%s-%s"
 	imageIDSlugHashLength = 6
+	// defaultImageRegistry is the registry images are pulled from when none is given
+	defaultImageRegistry = "docker.io"
 
 	maxDNSSubdomainLength = 253
-	maxImageNameLength    = maxDNSSubdomainLength - imageIDSlugHashLength - 1
+	maxDNSLabelLength     = 63
+	maxLabelValueLength   = 63
+	// minShortHashLength and maxShortHashLength bound the length of precomputed short hashes
+	minShortHashLength = 4
+	maxShortHashLength = 12
+	// sha256HexLength is the length of a hex-encoded SHA-256 digest
+	sha256HexLength = 64
+	// maxFullIdentifierLength bounds full image identifiers, which are way longer than slugs but still kept reasonable for annotations
+	maxFullIdentifierLength = 1024
+	// sha256DigestPrefix is the algorithm prefix of SHA-256 image digests
+	sha256DigestPrefix = "sha256:"
+	// checksumLength is the length of the checksum segment of checksummed slugs
+	checksumLength = 4
+	// redactedMarker replaces redacted segments of slugs
+	redactedMarker = "***"
 )
 
 // imageToDNSSubdomainReplacer is a replacer that can replace a valid, well-formed container image string to a valid DNS Subdomain
 var imageToDNSSubdomainReplacer = strings.NewReplacer("://", "-", ":", "-", "/", "-", "_", "-", "@", "-")
 
 var (
-	dnsSubdomainRegexp         = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]{0,251}[a-z0-9]$`)
+	dnsSubdomainRegexp         = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,61}[a-z0-9])?(\.[a-z0-9]([-a-z0-9]{0,61}[a-z0-9])?)*$`)
 	nonDnsSubdomainCharsRegexp = regexp.MustCompile(`[^a-zA-Z0-9\-\.]`)
-	dnsLabelRegexp             = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,61}[a-z0-9]$`)
+	nonDnsLabelCharsRegexp     = regexp.MustCompile(`[^a-zA-Z0-9\-]`)
 	labelValueRegexp           = regexp.MustCompile(`^$|^[a-zA-Z0-9]([-_.a-zA-Z0-9]{0,61}[a-zA-Z0-9])?$`)
 	nonLabelValueCharsRegexp   = regexp.MustCompile(`[^a-zA-Z0-9\-_.]`)
 )
 
+// ToValidDNSSubdomainName transforms a given input into a valid name for Kubernetes objects, such as a slug
+//
+// Like IsValidSlug, it follows the rules Kubernetes applies to object names, which do not limit the labels on their own,
+// so a long single-label input is truncated to 253 characters rather than split. Use SanitizeToDNSSubdomain to also limit the labels
 func ToValidDNSSubdomainName(input string) (string, error) {
-	if IsValidDNSSubdomainName(input) {
+	if isValidObjectName(input, maxDNSSubdomainLength) {
 		return input, nil
 	}
 
@@ -59,6 +85,9 @@ func ToValidDNSSubdomainName(input string) (string, error) {
 		dnsCompatible = dnsCompatible[:253]
 	}
 
+	// Ensure that every label is non-empty and starts and ends with an alphanumeric character.
+	dnsCompatible = trimDNSSubdomainLabels(dnsCompatible)
+
 	// Ensure that the name starts and ends with an alphanumeric character.
 	dnsCompatible = strings.TrimFunc(dnsCompatible, isNonAlphanumeric)
 	if len(dnsCompatible) == 0 {
@@ -68,6 +97,77 @@ func ToValidDNSSubdomainName(input string) (string, error) {
 	return dnsCompatible, nil
 }
 
+// trimDNSSubdomainLabels trims non-alphanumeric characters from both ends of every period-separated label and drops empty labels
+func trimDNSSubdomainLabels(name string) string {
+	labels := strings.Split(name, ".")
+	nonEmpty := labels[:0]
+	for _, label := range labels {
+		if label = strings.TrimFunc(label, isNonAlphanumeric); label != "" {
+			nonEmpty = append(nonEmpty, label)
+		}
+	}
+	return strings.Join(nonEmpty, ".")
+}
+
+// ToValidDNSLabelName transforms a given input into a valid DNS label name
+//
+// If nothing is left of the input once sanitized, it returns an error, unless a placeholder is set with WithEmptyPlaceholder
+func ToValidDNSLabelName(input string, opts ...Option) (string, error) {
+	if IsValidDNSLabelName(input) {
+		return input, nil
+	}
+
+	// Replace non-DNS label characters with hyphens.
+	dnsCompatible := nonDnsLabelCharsRegexp.ReplaceAllString(input, "-")
+
+	// Ensure that the result is in lowercase.
+	dnsCompatible = strings.ToLower(dnsCompatible)
+
+	// Trim leading and trailing hyphens.
+	dnsCompatible = strings.Trim(dnsCompatible, "-")
+
+	// Limit the length to 63 characters as required.
+	if len(dnsCompatible) > maxDNSLabelLength {
+		dnsCompatible = dnsCompatible[:maxDNSLabelLength]
+	}
+
+	// Ensure that the name starts and ends with an alphanumeric character.
+	dnsCompatible = strings.TrimFunc(dnsCompatible, isNonAlphanumeric)
+	if len(dnsCompatible) == 0 {
+		if placeholder := resolveOptions(opts).EmptyPlaceholder; IsValidDNSLabelName(placeholder) {
+			return placeholder, nil
+		}
+		return "", fmt.Errorf("cannot transform input into a valid DNS label name: %s", input)
+	}
+
+	return dnsCompatible, nil
+}
+
+// ToValidDNSLabelNameRFC1035 transforms a given input into a valid RFC 1035 label name, as required e.g. by Service names
+//
+// It works like ToValidDNSLabelName, but also trims any leading characters that are not letters, so "1-nginx" becomes "nginx".
+// If nothing is left of the input once sanitized, it returns an error, unless a placeholder is set with WithEmptyPlaceholder
+func ToValidDNSLabelNameRFC1035(input string, opts ...Option) (string, error) {
+	if IsValidDNSLabelNameRFC1035(input) {
+		return input, nil
+	}
+
+	dnsCompatible, err := ToValidDNSLabelName(input)
+	// Ensure that the name starts with a letter.
+	dnsCompatible = strings.TrimLeftFunc(dnsCompatible, func(r rune) bool { return r < 'a' || r > 'z' })
+	if err != nil || len(dnsCompatible) == 0 {
+		if placeholder := resolveOptions(opts).EmptyPlaceholder; IsValidDNSLabelNameRFC1035(placeholder) {
+			return placeholder, nil
+		}
+		return "", fmt.Errorf("cannot transform input into a valid RFC 1035 label name: %s", input)
+	}
+
+	return dnsCompatible, nil
+}
+
+// ToValidLabelValue transforms a given input into a valid label value, e.g. to store a slug in a label
+//
+// Unlike the DNS name sanitizers, it never fails: a label value may be empty, so input that sanitizes to nothing returns an empty value
 func ToValidLabelValue(input string) string {
 	if IsValidLabelValue(input) {
 		return input
@@ -83,8 +183,8 @@ func ToValidLabelValue(input string) string {
 	labelValueCompatible = strings.Trim(labelValueCompatible, "-")
 
 	// Limit the length to 63 characters as required.
-	if len(labelValueCompatible) > 63 {
-		labelValueCompatible = labelValueCompatible[:63]
+	if len(labelValueCompatible) > maxLabelValueLength {
+		labelValueCompatible = labelValueCompatible[:maxLabelValueLength]
 	}
 
 	// Ensure that the label value begins and ends with an alphanumeric character.
@@ -94,20 +194,120 @@ func ToValidLabelValue(input string) string {
 }
 
 // IsValidDNSSubdomainName returns true if a given string is a valid DNS Subdomain name as defined in the Kubernetes docs
+//
+// The name must be 1 to 253 characters long and every period-separated label 1 to 63 characters long, as hostnames require.
+// Kubernetes does not limit the labels of object names on their own, so slugs are validated with IsValidSlug instead
 func IsValidDNSSubdomainName(s string) bool {
-	return dnsSubdomainRegexp.MatchString(s)
+	return isValidDNSName(s, true, maxDNSSubdomainLength, maxDNSLabelLength)
+}
+
+// IsValidDNSSubdomainBytes returns true if a given byte slice is a valid DNS Subdomain name, without converting it to a string
+func IsValidDNSSubdomainBytes(b []byte) bool {
+	return isValidDNSName(b, true, maxDNSSubdomainLength, maxDNSLabelLength)
+}
+
+// IsValidDNSSubdomainNameMax returns true if a given string follows the DNS Subdomain name rules with a custom maximum length
+//
+// It is meant for sinks with their own identifier limits: the character, boundary and label rules are the same as IsValidDNSSubdomainName's
+func IsValidDNSSubdomainNameMax(s string, max int) bool {
+	return isValidDNSName(s, true, max, maxDNSLabelLength)
+}
+
+// DNSSubdomainRegexp returns a compiled regular expression that matches the same names as IsValidDNSSubdomainName, except for the length limit
+//
+// Regular expressions cannot express the length limit of 253 characters along with the label rules, so the regular expression
+// also matches longer names and callers must check the length separately.
+// Every call returns a new copy, so callers are free to change it, e.g. with Longest
+func DNSSubdomainRegexp() *regexp.Regexp {
+	return regexp.MustCompile(dnsSubdomainRegexp.String())
 }
 
 // IsValidDNSLabelName returns true if a given string is a valid DNS label name as defined in the Kubernetes docs
 func IsValidDNSLabelName(s string) bool {
-	return dnsLabelRegexp.MatchString(s)
+	return isValidDNSName(s, false, maxDNSLabelLength, maxDNSLabelLength)
+}
+
+// IsValidDNSLabelNameRFC1035 returns true if a given string is a valid RFC 1035 label name as defined in the Kubernetes docs
+//
+// RFC 1035 labels follow the DNS label name rules of IsValidDNSLabelName, but must start with a letter.
+// Kubernetes requires them for some fields, such as Service names
+func IsValidDNSLabelNameRFC1035(s string) bool {
+	return IsValidDNSLabelName(s) && s[0] >= 'a' && s[0] <= 'z'
 }
 
-// isValidLabelValue checks if a string is a valid Kubernetes label value as defined in the Kubernetes docs
+// IsValidDNSLabelNameMax returns true if a given string follows the DNS label name rules with a custom maximum length
+//
+// The character and boundary rules are the same as IsValidDNSLabelName's
+func IsValidDNSLabelNameMax(s string, max int) bool {
+	return isValidDNSName(s, false, max, max)
+}
+
+// IsValidDNSLabelBytes returns true if a given byte slice is a valid DNS label name, without converting it to a string
+func IsValidDNSLabelBytes(b []byte) bool {
+	return isValidDNSName(b, false, maxDNSLabelLength, maxDNSLabelLength)
+}
+
+// isValidObjectName returns true if a given string can be used as the name of a Kubernetes object at most maxLength characters long
+//
+// Object names follow the DNS Subdomain name rules, except that their labels are not limited on their own
+func isValidObjectName(s string, maxLength int) bool {
+	return isValidDNSName(s, true, maxLength, maxLength)
+}
+
+// isValidDNSName scans a given name for the DNS name rules: lowercase alphanumeric characters and hyphens (and periods, if allowed),
+// starting and ending with an alphanumeric character, at most maxLength long and with labels at most maxLabelLength long
+//
+// Single-character names and labels, such as "a" or "a.b.c", are valid per RFC 1123.
+// Periods must separate non-empty labels that start and end with an alphanumeric character, so "a..b" and "a.-b" are invalid.
+// It accepts both strings and byte slices so neither has to be converted, which would allocate
+func isValidDNSName[T ~string | ~[]byte](name T, allowPeriods bool, maxLength, maxLabelLength int) bool {
+	if len(name) == 0 || len(name) > maxLength {
+		return false
+	}
+	if !isLowerAlphanumericByte(name[0]) || !isLowerAlphanumericByte(name[len(name)-1]) {
+		return false
+	}
+	labelStart := 0
+	for i := 1; i < len(name)-1; i++ {
+		c := name[i]
+		if isLowerAlphanumericByte(c) || c == '-' {
+			continue
+		}
+		// a period separates labels, which must be non-empty and start and end with an alphanumeric character
+		if !allowPeriods || c != '.' || !isLowerAlphanumericByte(name[i-1]) || !isLowerAlphanumericByte(name[i+1]) {
+			return false
+		}
+		if i-labelStart > maxLabelLength {
+			return false
+		}
+		labelStart = i + 1
+	}
+	return len(name)-labelStart <= maxLabelLength
+}
+
+func isLowerAlphanumericByte(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9')
+}
+
+// IsValidLabelValue returns true if a given string is a valid label value as defined in the Kubernetes docs
+//
+// A label value is empty or at most 63 alphanumeric characters, hyphens, underscores and periods, starting and ending with an alphanumeric character
 func IsValidLabelValue(value string) bool {
 	return labelValueRegexp.MatchString(value)
 }
 
+// NeedsSanitizationDNSSubdomain returns true if ToValidDNSSubdomainName would change a given name
+func NeedsSanitizationDNSSubdomain(name string) bool {
+	// ToValidDNSSubdomainName returns valid names untouched and any other input is changed or rejected
+	return !isValidObjectName(name, maxDNSSubdomainLength)
+}
+
+// NeedsSanitizationDNSLabel returns true if ToValidDNSLabelName would change a given name
+func NeedsSanitizationDNSLabel(name string) bool {
+	// ToValidDNSLabelName returns valid names untouched and any other input is changed or rejected
+	return !IsValidDNSLabelName(name)
+}
+
 func isAlphanumeric(r rune) bool {
 	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
 }
@@ -118,38 +318,251 @@ func isNonAlphanumeric(r rune) bool {
 
 // IsValidSlug returns true if a given string is a valid slug, else false
 //
-// A string is considered a valid slug if it can be used as a name of a Kubernetes resource
+// A string is considered a valid slug if it can be used as a name of a Kubernetes resource.
+// Like Kubernetes, it does not limit the period-separated labels on their own, see WithStrictLabels
 func IsValidSlug(s string) bool {
-	return IsValidDNSSubdomainName(s)
+	return isValidObjectName(s, maxDNSSubdomainLength)
 }
 
-// sanitizeImage returns a sanitized image string safe for use with K8s names
+// normalizeImage prepares a given image for slug generation
+//
+// Surrounding whitespace, a common leftover of parsing manifests, is dropped rather than rejected.
+// Backslashes, which leak from Windows-style image references such as "mcr.microsoft.com\windows\nanoserver",
+// are treated as the path separators they stand for
+func normalizeImage(image string) string {
+	return strings.ReplaceAll(strings.TrimSpace(image), `\`, "/")
+}
+
+// NormalizeImageTag normalizes a given image reference so that the image slug generators always accept it, e.g. for user-provided image strings
+//
+// The image is lowercased, characters that cannot appear in an image slug are replaced with hyphens, periods that would not end up between
+// alphanumeric characters are dropped and so are any leading and trailing characters that are not alphanumeric.
+// Separators, such as the ":" of a registry port or a tag, are kept. Valid lowercase image references are returned as is.
+// It returns an empty string if the image has no alphanumeric characters
+func NormalizeImageTag(image string) string {
+	image = strings.ToLower(normalizeImage(image))
+
+	var b strings.Builder
+	b.Grow(len(image))
+	for i, r := range image {
+		switch {
+		case r < utf8.RuneSelf && (isLowerAlphanumericByte(byte(r)) || strings.ContainsRune("-_/:@", r)):
+			b.WriteRune(r)
+		case r == '.':
+			// periods separate DNS labels, so they must sit between alphanumeric characters
+			if i > 0 && i < len(image)-1 && isLowerAlphanumericByte(image[i-1]) && isLowerAlphanumericByte(image[i+1]) {
+				b.WriteRune(r)
+			}
+		default:
+			b.WriteByte('-')
+		}
+	}
+	return strings.TrimFunc(b.String(), isNonAlphanumeric)
+}
+
+// digestHex returns the hex-encoded part of a given digest, dropping its algorithm
+func digestHex(digest string) string {
+	if _, hex, found := strings.Cut(digest, ":"); found {
+		return hex
+	}
+	return digest
+}
+
+// trimRegistry returns a given image without its registry, if the image is hosted on that registry
+func trimRegistry(image, registry string) string {
+	prefix := registry + "/"
+	if registry == "" || len(image) <= len(prefix) || !strings.EqualFold(image[:len(prefix)], prefix) {
+		return image
+	}
+	return image[len(prefix):]
+}
+
+// TrimSlug returns a given slug with leading and trailing separators removed and runs of separators collapsed into a single one
+//
+// It is meant as a cleanup step for user-provided names before they are validated
+func TrimSlug(name string) string {
+	segments := strings.Split(name, slugSeparator)
+	nonEmpty := segments[:0]
+	for _, segment := range segments {
+		if segment != "" {
+			nonEmpty = append(nonEmpty, segment)
+		}
+	}
+	return strings.Join(nonEmpty, slugSeparator)
+}
+
+// AppendSuffix returns a given name with a short qualifier appended, such as "-ro", truncating the name rather than the suffix to stay a valid slug
+//
+// The suffix, without its leading separator, must be a valid DNS label name.
+// If the suffix is invalid or the result would not be a valid slug, it returns ErrInvalidSlug
+func AppendSuffix(name, suffix string) (string, error) {
+	suffix = strings.TrimPrefix(suffix, slugSeparator)
+	if !IsValidDNSLabelName(suffix) {
+		return "", fmt.Errorf("%w: %q is not a valid suffix", ErrInvalidSlug, suffix)
+	}
+
+	maxNameLength := maxDNSSubdomainLength - len(suffix) - len(slugSeparator)
+	if len(name) > maxNameLength {
+		name = name[:maxNameLength]
+	}
+	// truncation must not leave a separator or a period right before the suffix
+	name = strings.TrimRight(name, "-.")
+
+	result := fmt.Sprintf(slugFormat, name, suffix)
+	if len(name) == 0 || !IsValidSlug(result) {
+		return "", ErrInvalidSlug
+	}
+	return result, nil
+}
+
+// sanitizeImage returns a sanitized image string safe for use with K8s names, truncated to maxLength
 //
 // It expects a valid image name string
-func sanitizeImage(image string) string {
-	sanitized := imageToDNSSubdomainReplacer.Replace(image)
+func sanitizeImage(image string, maxLength int) string {
+	return sanitizeImageWithSeparator(image, slugSeparator, maxLength)
+}
 
-	if len(image) >= maxImageNameLength {
-		sanitized = sanitized[:maxImageNameLength]
+// sanitizeImageWithSeparator works like sanitizeImage, but separates the segments of the image with a given separator
+func sanitizeImageWithSeparator(image, separator string, maxLength int) string {
+	replacer := imageToDNSSubdomainReplacer
+	if separator != slugSeparator {
+		replacer = strings.NewReplacer("://", separator, ":", separator, "/", separator, "_", separator, "@", separator)
+	}
+	sanitized := replacer.Replace(image)
+
+	if len(sanitized) > maxLength {
+		// truncation must not leave a period right before the hash suffix
+		sanitized = strings.TrimRight(sanitized[:maxLength], ".")
 	}
 	return sanitized
 }
 
+// imageToSlug returns the lowercased image slug made of a given image, sanitized and truncated to maxImageLength, and a given hash suffix
+//
+// It writes the slug in a single pass into a buffer sized up front, lowercasing ASCII letters on the way,
+// so strings.ToLower only has work left, and allocates, for images with uppercase non-ASCII characters
+func imageToSlug(image, imageHashStub, separator string, maxImageLength int) string {
+	var b strings.Builder
+	b.Grow(min(len(image)*len(separator), maxImageLength) + len(separator) + len(imageHashStub))
+
+	truncated := false
+	for i := 0; i < len(image) && !truncated; i++ {
+		switch c := image[i]; {
+		case strings.HasPrefix(image[i:], "://"):
+			i += len("://") - 1
+			truncated = writeLowerTruncated(&b, separator, maxImageLength)
+		case c == ':' || c == '/' || c == '_' || c == '@':
+			truncated = writeLowerTruncated(&b, separator, maxImageLength)
+		default:
+			truncated = writeLowerTruncated(&b, image[i:i+1], maxImageLength)
+		}
+	}
+
+	// truncation must not leave a period right before the hash suffix
+	if sanitized := b.String(); truncated && strings.HasSuffix(sanitized, ".") {
+		return strings.ToLower(strings.TrimRight(sanitized, ".") + separator + imageHashStub)
+	}
+	writeLowerTruncated(&b, separator, math.MaxInt)
+	writeLowerTruncated(&b, imageHashStub, math.MaxInt)
+	return strings.ToLower(b.String())
+}
+
+// writeLowerTruncated writes a given string, with ASCII letters lowercased, into a builder without growing it past maxLength
+//
+// It returns true if the string did not fit whole
+func writeLowerTruncated(b *strings.Builder, s string, maxLength int) bool {
+	for i := 0; i < len(s); i++ {
+		if b.Len() >= maxLength {
+			return true
+		}
+		c := s[i]
+		if 'A' <= c && c <= 'Z' {
+			c += 'a' - 'A'
+		}
+		b.WriteByte(c)
+	}
+	return false
+}
+
 // sanitizeInstanceIDSlug returns a sanitized instance ID slug
 func sanitizeInstanceIDSlug(instanceIDSlug, containerName, hashedID string) string {
-	leadingDigest, trailingDigest := hashedID[:slugHashLength], hashedID[len(hashedID)-slugHashLength:]
+	return sanitizeInstanceIDSlugWithDigests(instanceIDSlug, containerName, hashedID[:slugHashLength], hashedID[len(hashedID)-slugHashLength:])
+}
+
+// sanitizeInstanceIDSlugWithDigests returns a sanitized instance ID slug identified by the given hash-based identifiers
+func sanitizeInstanceIDSlugWithDigests(instanceIDSlug, containerName, leadingDigest, trailingDigest string) string {
+	return buildInstanceIDSlug([]string{instanceIDSlug}, containerName, []string{leadingDigest, trailingDigest}, slugSeparator, maxDNSSubdomainLength)
+}
+
+// buildInstanceIDSlug returns an instance ID slug made of given segments and identified by the given hash-based identifiers, with segments separated by a given separator
+// and truncated to maxLength
+//
+// It writes the slug into a buffer sized up front, lowercasing ASCII letters on the way, so generating a slug only allocates the result
+func buildInstanceIDSlug(hashlessSegments []string, containerName string, hashParts []string, separator string, maxLength int) string {
+	hashPartsLength := 0
+	for _, part := range hashParts {
+		hashPartsLength += len(separator) + len(part)
+	}
+	maxHashlessLength := maxLength - hashPartsLength
 
+	length := len(separator) * (len(hashlessSegments) - 1)
+	for _, segment := range hashlessSegments {
+		length += len(segment)
+	}
 	// if container name is not empty, add it to the slug, and add the hash as well
 	// adding the hash is necessary to avoid collisions between different workloads in different namespaces. This is a workaround until we store the vulnerabilitymanifests objects in a separate namespace
 	if containerName != "" {
-		instanceIDSlug = fmt.Sprintf("%s-%s", instanceIDSlug, containerName)
-		instanceIDSlug = fmt.Sprintf("%s-%s-%s", instanceIDSlug, leadingDigest, trailingDigest)
+		length += len(separator) + len(containerName) + hashPartsLength
+	}
+
+	fits := length < maxHashlessLength
+	limit := math.MaxInt
+	if !fits {
+		limit = maxHashlessLength
+	}
+
+	var b strings.Builder
+	b.Grow(min(length, limit) + hashPartsLength)
+	for i, segment := range hashlessSegments {
+		if i > 0 {
+			writeLowerTruncated(&b, separator, limit)
+		}
+		writeLowerTruncated(&b, segment, limit)
+	}
+	if containerName != "" {
+		writeLowerTruncated(&b, separator, limit)
+		writeLowerTruncated(&b, containerName, limit)
+		for _, part := range hashParts {
+			writeLowerTruncated(&b, separator, limit)
+			writeLowerTruncated(&b, part, limit)
+		}
+	}
+	if fits {
+		return b.String()
+	}
+
+	// truncation must not leave a period right before the hash-based identifiers
+	if hashless := b.String(); strings.HasSuffix(hashless, ".") {
+		return strings.TrimRight(hashless, ".") + separator + strings.Join(hashParts, separator)
 	}
-	if len(instanceIDSlug) < maxHashlessStringLength {
-		return instanceIDSlug
+	for _, part := range hashParts {
+		writeLowerTruncated(&b, separator, math.MaxInt)
+		writeLowerTruncated(&b, part, math.MaxInt)
 	}
-	return fmt.Sprintf("%s-%s-%s", instanceIDSlug[:maxHashlessStringLength], leadingDigest, trailingDigest)
+	return b.String()
+}
 
+// isValidSeparator returns true if a given separator can separate slug segments: it must be non-empty and not alphanumeric
+func isValidSeparator(separator string) bool {
+	return separator != "" && strings.IndexFunc(separator, isAlphanumeric) == -1
+}
+
+// isValidSlugWithSeparator returns true if a given slug would be valid if its segments were separated by hyphens
+func isValidSlugWithSeparator(slug, separator string) bool {
+	if separator != slugSeparator {
+		slug = strings.ReplaceAll(slug, separator, slugSeparator)
+	}
+	return IsValidSlug(slug)
 }
 
 // InstanceIDToSlug retuns a human-friendly representation given a description of an instance ID
@@ -157,38 +570,431 @@ func sanitizeInstanceIDSlug(instanceIDSlug, containerName, hashedID string) stri
 // If the given inputs would produce an invalid slug, it returns an appropriate error
 // Deprecated: use InstanceID.GetSlug instead
 func InstanceIDToSlug(name, kind, containerName, hashedID string) (string, error) {
+	return InstanceIDToSlugWithOptions(name, kind, containerName, hashedID)
+}
 
-	slug := sanitizeInstanceIDSlug(fmt.Sprintf(instanceIDSlugHashlessFormat, kind, name), containerName, hashedID)
+// InstanceIDToSlugWithOptions returns a human-friendly representation given a description of an instance ID, generated according to the given options
+//
+// If the given options leave no room for the hash-based identifiers, it returns ErrNameTooLong
+func InstanceIDToSlugWithOptions(name, kind, containerName, hashedID string, opts ...Option) (string, error) {
+	options := resolveOptions(opts)
+	separator, hashLength := options.separator(), options.instanceHashLength()
+	if !isValidSeparator(separator) {
+		return "", newInvalidInputError(ErrInvalidSlug, "separator", ReasonInvalidCharacters)
+	}
+
+	var hashParts [2]string
+	hashPartCount := len(hashParts)
+	if options.HashSuffixFunc != nil {
+		// a custom hash suffix is a single hash-based identifier
+		suffix, err := options.hashSuffix(hashedID)
+		if err != nil {
+			return "", err
+		}
+		hashParts[0], hashPartCount = suffix, 1
+	} else {
+		if hashLength < 1 {
+			return "", newInvalidInputError(ErrInvalidSlug, "hashSuffixLength", ReasonTooShort)
+		}
+		if len(hashedID) < hashLength {
+			return "", newInvalidInputError(ErrInvalidSlug, "hashedID", ReasonTooShort)
+		}
+		hashParts[0], hashParts[1] = hashedID[:hashLength], hashedID[len(hashedID)-hashLength:]
+	}
+	// the shortest slug fits one character of the instance ID and the hash-based identifiers
+	hashPartsLength := 0
+	for _, part := range hashParts[:hashPartCount] {
+		hashPartsLength += len(part) + len(separator)
+	}
+	if options.MaxLength < hashPartsLength+1 {
+		return "", newInvalidInputError(ErrNameTooLong, "maxLength", ReasonTooShort)
+	}
+
+	slug := buildInstanceIDSlug([]string{kind, name}, containerName, hashParts[:hashPartCount], separator, options.MaxLength)
 
-	var err error
 	slug = strings.ToLower(slug)
+	if !isValidSlugWithSeparator(slug, separator) {
+		field := invalidInputField(separator, "instanceID",
+			[2]string{"kind", kind}, [2]string{"name", name}, [2]string{"containerName", containerName},
+			[2]string{"hashedID", hashParts[0]}, [2]string{"hashedID", hashParts[1]})
+		return "", newInvalidInputError(ErrInvalidSlug, field, ReasonInvalidCharacters)
+	}
+	if err := options.checkLabels(slug, "instanceID"); err != nil {
+		return "", err
+	}
+	return slug, nil
+}
 
+// invalidInputField returns the name of the first of the given name and value pairs with characters that cannot appear in a slug,
+// or a given fallback if there is none, e.g. if the inputs only make an invalid slug together
+func invalidInputField(separator, fallback string, fields ...[2]string) string {
+	for _, field := range fields {
+		for i := 0; i < len(field[1]); i++ {
+			if c := field[1][i]; !isAlphanumeric(rune(c)) && c != '-' && c != '.' && !strings.ContainsRune(separator, rune(c)) {
+				return field[0]
+			}
+		}
+	}
+	return fallback
+}
+
+// InstanceIDToSlugWithHashParts returns a human-friendly representation given a description of an instance ID,
+// using the first prefixLen and the last suffixLen characters of hashedID as the hash-based identifiers
+//
+// If the parts would overlap or are not positive, it returns ErrInvalidHash
+func InstanceIDToSlugWithHashParts(name, kind, containerName, hashedID string, prefixLen, suffixLen int) (string, error) {
+	if prefixLen < 1 || suffixLen < 1 || prefixLen+suffixLen > len(hashedID) {
+		return "", ErrInvalidHash
+	}
+
+	leadingDigest, trailingDigest := hashedID[:prefixLen], hashedID[len(hashedID)-suffixLen:]
+	slug := buildInstanceIDSlug([]string{kind, name}, containerName, []string{leadingDigest, trailingDigest}, slugSeparator, maxDNSSubdomainLength)
+
+	slug = strings.ToLower(slug)
 	if !IsValidSlug(slug) {
-		slug, err = "", ErrInvalidSlug
+		return "", ErrInvalidSlug
 	}
+	return slug, nil
+}
 
-	return strings.ToLower(slug), err
+// InstanceIDToSlugWithSubresource returns a human-friendly representation given a description of an instance ID and a subresource of it
+//
+// The subresource, such as "status" or "logs", must be a valid DNS label and is placed right before the hash-based identifiers.
+// An empty subresource produces the same slug as InstanceIDToSlug
+func InstanceIDToSlugWithSubresource(name, kind, containerName, subresource, hashedID string) (string, error) {
+	if subresource == "" {
+		return InstanceIDToSlug(name, kind, containerName, hashedID)
+	}
+	if !IsValidDNSLabelName(subresource) {
+		return "", ErrInvalidSlug
+	}
+
+	// the subresource follows the container name, and both precede the hash
+	trailingSegment := subresource
+	if containerName != "" {
+		trailingSegment = fmt.Sprintf(slugFormat, containerName, subresource)
+	}
+	return InstanceIDToSlug(name, kind, trailingSegment, hashedID)
+}
+
+// InstanceIDToSlugWithAPIVersion returns a human-friendly representation given a description of a container-scoped instance ID and its API version
+//
+// The API version, such as "apps/v1", is sanitized into a DNS label and placed right before the kind, e.g. "apps-v1-deployment-webapp-nginx-1ba5-4aaf",
+// so the same kind and name in different API groups or versions yield distinct slugs. Truncation and the hash-based identifiers follow InstanceIDToSlug.
+// An empty API version produces the same slug as InstanceIDToSlug
+func InstanceIDToSlugWithAPIVersion(apiVersion, name, kind, containerName, hashedID string) (string, error) {
+	if apiVersion == "" {
+		return InstanceIDToSlug(name, kind, containerName, hashedID)
+	}
+	apiVersionSegment, err := ToValidDNSLabelName(apiVersion)
+	if err != nil {
+		return "", ErrInvalidSlug
+	}
+
+	return InstanceIDToSlug(name, fmt.Sprintf(slugFormat, apiVersionSegment, kind), containerName, hashedID)
 }
 
 // ImageInfoToSlug returns a human-friendly representation for a given image information
 //
+// Backslashes in the image are treated like slashes, so Windows-style image references produce the same slugs as regular ones.
+// The whole image is lowercased, including the repository, so mixed-case repositories like "myorg/MyApp" produce valid slugs.
+// This is lossy: repositories are case-sensitive, so "myorg/MyApp" and "myorg/myapp" share a slug unless their hashes differ.
 // If the given inputs would produce an invalid slug, it returns an appropriate error
 func ImageInfoToSlug(image, imageHash string) (string, error) {
-	if len(image) == 0 || len(imageHash) < imageIDSlugHashLength {
+	return ImageInfoToSlugWithOptions(image, imageHash)
+}
+
+// ImageInfoToSlugWithOptions returns a human-friendly representation for a given image information, generated according to the given options
+//
+// If the image is pinned to a digest, such as "nginx@sha256:<hex>", and no hash is given, the digest of the reference parsed with ParseImageReference
+// is used as the hash. Images that are not valid references are used as given, without a digest.
+// If the given options leave no room for the hash suffix, it returns ErrNameTooLong
+func ImageInfoToSlugWithOptions(image, imageHash string, opts ...Option) (string, error) {
+	return imageInfoToSlug(image, imageHash, "", resolveOptions(opts))
+}
+
+// ImageInfoToSlugWithPlatform returns a human-friendly representation for a given image information and the platform of the image manifest,
+// generated according to the given options
+//
+// The platform, e.g. "linux-arm64-v8", is placed right before the hash suffix, so artifacts for the different architectures of a multi-platform
+// image tag do not collide. The image is truncated to make room for the platform. An empty platform produces the same slug as ImageInfoToSlugWithOptions
+func ImageInfoToSlugWithPlatform(image, imageHash string, platform Platform, opts ...Option) (string, error) {
+	options := resolveOptions(opts)
+	if platform == (Platform{}) {
+		return imageInfoToSlug(image, imageHash, "", options)
+	}
+
+	platformFields := [][2]string{{"os", platform.OS}, {"architecture", platform.Architecture}}
+	if platform.Variant != "" {
+		platformFields = append(platformFields, [2]string{"variant", platform.Variant})
+	}
+	platformSegments := make([]string, len(platformFields))
+	for i, field := range platformFields {
+		platformSegments[i] = strings.ToLower(field[1])
+		if !IsValidDNSLabelName(platformSegments[i]) {
+			return "", newInvalidInputError(fmt.Errorf("%w: %w", ErrInvalidSlug, ErrInvalidPlatform), field[0], platformSegmentReason(platformSegments[i]))
+		}
+	}
+
+	return imageInfoToSlug(image, imageHash, strings.Join(platformSegments, options.separator()), options)
+}
+
+// platformSegmentReason returns why a given lowercased platform segment is not a valid DNS label name
+func platformSegmentReason(segment string) InvalidInputReason {
+	switch {
+	case segment == "":
+		return ReasonEmpty
+	case len(segment) > maxDNSLabelLength:
+		return ReasonTooLong
+	default:
+		return ReasonInvalidCharacters
+	}
+}
+
+// imageInfoToSlug returns a human-friendly representation for a given image information, generated according to the given resolved options
+//
+// A non-empty beforeHashSegment, such as a platform, is placed right before the hash suffix
+func imageInfoToSlug(image, imageHash, beforeHashSegment string, options Options) (string, error) {
+	separator, hashLength := options.separator(), options.imageHashLength()
+	if !isValidSeparator(separator) {
+		return "", newInvalidInputError(ErrInvalidSlug, "separator", ReasonInvalidCharacters)
+	}
+	if hashLength < 1 && options.HashSuffixFunc == nil {
+		return "", newInvalidInputError(ErrInvalidSlug, "hashSuffixLength", ReasonTooShort)
+	}
+	// the shortest slug fits one character of the image, the separator and the hash suffix
+	if options.HashSuffixFunc == nil && options.MaxLength < hashLength+len(separator)+1 {
+		return "", newInvalidInputError(ErrNameTooLong, "maxLength", ReasonTooShort)
+	}
+
+	image, imageHash = imageSlugInputs(image, imageHash)
+	if len(image) == 0 {
+		return "", newInvalidInputError(ErrInvalidSlug, "image", ReasonEmpty)
+	}
+
+	if options.HideDefaultRegistry {
+		image = trimRegistry(image, options.DefaultRegistry)
+	}
+
+	var imageHashStub string
+	switch {
+	case options.HashSuffixFunc != nil:
+		var err error
+		if imageHashStub, err = options.hashSuffix(imageHash); err != nil {
+			return "", err
+		}
+	case len(imageHash) < hashLength:
+		return "", newInvalidInputError(ErrInvalidSlug, "imageHash", ReasonTooShort)
+	case options.HashFromFront:
+		imageHashStub = imageHash[:hashLength]
+	default:
+		imageHashStub = imageHash[len(imageHash)-hashLength:]
+	}
+	if beforeHashSegment != "" {
+		imageHashStub = beforeHashSegment + separator + imageHashStub
+	}
+	if options.MaxLength < len(imageHashStub)+len(separator)+1 {
+		return "", newInvalidInputError(ErrNameTooLong, "maxLength", ReasonTooShort)
+	}
+	slug := imageToSlug(image, imageHashStub, separator, options.MaxLength-len(imageHashStub)-len(separator))
+
+	if !isValidSlugWithSeparator(slug, separator) {
+		field := invalidInputField(separator, "image", [2]string{"imageHash", imageHashStub})
+		return "", newInvalidInputError(ErrInvalidSlug, field, ReasonInvalidCharacters)
+	}
+	if err := options.checkLabels(slug, "image"); err != nil {
+		return "", err
+	}
+
+	return slug, nil
+}
+
+// imageSlugInputs returns a given image and image hash normalized the way the image slug generators use them
+//
+// A digest-pinned image without a separate hash is identified by its digest, which is then left out of the image
+func imageSlugInputs(image, imageHash string) (string, string) {
+	image, imageHash = normalizeImage(image), strings.TrimSpace(imageHash)
+	if imageHash == "" {
+		if ref, err := ParseImageReference(image); err == nil && ref.Digest != "" {
+			image, _, _ = strings.Cut(image, "@")
+			imageHash = digestHex(ref.Digest)
+		}
+	}
+	return image, imageHash
+}
+
+// ImageInfoToSlugWithChecksum returns an image slug followed by a checksum segment, so that corruption in untrusted stores can be detected
+//
+// The checksum is the first 4 hex characters of the CRC32 of the rest of the slug, which is shortened as needed to fit the length limit.
+// Use VerifyChecksum to check such a slug
+func ImageInfoToSlugWithChecksum(image, imageHash string) (string, error) {
+	slug, err := ImageInfoToSlugWithOptions(image, imageHash, WithMaxLen(maxDNSSubdomainLength-checksumLength-len(slugSeparator)))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(slugFormat, slug, slugChecksum(slug)), nil
+}
+
+// VerifyChecksum returns true if the checksum segment of a given slug, as produced by ImageInfoToSlugWithChecksum, matches the rest of it
+func VerifyChecksum(slug string) bool {
+	i := strings.LastIndex(slug, slugSeparator)
+	if i < 1 {
+		return false
+	}
+	return slug[i+1:] == slugChecksum(slug[:i])
+}
+
+// slugChecksum returns the checksum segment of a given slug
+func slugChecksum(slug string) string {
+	return fmt.Sprintf("%08x", crc32.ChecksumIEEE([]byte(slug)))[:checksumLength]
+}
+
+// ImageInfoToFullIdentifier returns an identifier that keeps the full digest of a given image, such as "docker.io/nginx:latest@sha256:<digest>"
+//
+// Unlike a slug, the identifier is not a DNS name and is meant for annotation values, not object names.
+// Any digest already in the image is replaced by the given hash, which must be a SHA-256 digest with or without the "sha256:" prefix
+func ImageInfoToFullIdentifier(image, imageHash string) (string, error) {
+	digest := strings.TrimPrefix(strings.TrimSpace(imageHash), sha256DigestPrefix)
+	switch {
+	case digest == "":
+		return "", newInvalidInputError(ErrInvalidHash, "imageHash", ReasonEmpty)
+	case !isHex(digest):
+		return "", newInvalidInputError(ErrInvalidHash, "imageHash", ReasonInvalidCharacters)
+	case len(digest) < sha256HexLength:
+		return "", newInvalidInputError(ErrInvalidHash, "imageHash", ReasonTooShort)
+	case len(digest) > sha256HexLength:
+		return "", newInvalidInputError(ErrInvalidHash, "imageHash", ReasonTooLong)
+	}
+
+	image, _, _ = strings.Cut(normalizeImage(image), "@")
+	if len(image) == 0 {
 		return "", ErrInvalidSlug
 	}
 
-	var err error
-	imageHashStub := imageHash[len(imageHash)-imageIDSlugHashLength:]
-	sanitizedImage := sanitizeImage(image)
-	slug, err := fmt.Sprintf(imageIDSlugFormat, sanitizedImage, imageHashStub), nil
-	slug = strings.ToLower(slug)
+	identifier := image + "@" + sha256DigestPrefix + strings.ToLower(digest)
+	if len(identifier) > maxFullIdentifierLength {
+		return "", ErrNameTooLong
+	}
+	return identifier, nil
+}
 
+// ImageInfoFromShortHashToSlug returns a human-friendly representation for a given image and a precomputed short hash of it
+//
+// The short hash, 4 to 12 hexadecimal characters, is used as the suffix as is, which spares re-deriving it from the full hash in hot paths.
+// If the short hash is malformed, it returns an error wrapping ErrInvalidHash
+func ImageInfoFromShortHashToSlug(image, shortHash string) (string, error) {
+	switch {
+	case len(shortHash) < minShortHashLength:
+		return "", newInvalidInputError(ErrInvalidHash, "shortHash", ReasonTooShort)
+	case len(shortHash) > maxShortHashLength:
+		return "", newInvalidInputError(ErrInvalidHash, "shortHash", ReasonTooLong)
+	case !isHex(shortHash):
+		return "", newInvalidInputError(ErrInvalidHash, "shortHash", ReasonInvalidCharacters)
+	}
+
+	image = normalizeImage(image)
+	if len(image) == 0 {
+		return "", ErrInvalidSlug
+	}
+
+	sanitizedImage := sanitizeImage(image, maxDNSSubdomainLength-len(shortHash)-1)
+	slug := strings.ToLower(fmt.Sprintf(imageIDSlugFormat, sanitizedImage, shortHash))
 	if !IsValidSlug(slug) {
-		slug, err = "", ErrInvalidSlug
+		return "", ErrInvalidSlug
+	}
+	return slug, nil
+}
+
+// isHex returns true if a given string consists of hexadecimal characters only
+func isHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !((c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')) {
+			return false
+		}
+	}
+	return true
+}
+
+// VerifyImageSlug returns true if a given slug is the one ImageInfoToSlugWithOptions produces for the given image information and options
+//
+// Slugs generated with options, e.g. truncated to a shorter length with WithMaxLen, only match when verified with the same options
+func VerifyImageSlug(slug, image, imageHash string, opts ...Option) bool {
+	expected, err := ImageInfoToSlugWithOptions(image, imageHash, opts...)
+	return err == nil && slug == expected
+}
+
+// LongestLabelLength returns the longest period-separated label of a given name and its length
+//
+// It is meant for diagnostics, to point at the label that makes a name fail validation. On ties, the first longest label is returned
+func LongestLabelLength(name string) (length int, label string) {
+	for _, l := range strings.Split(name, ".") {
+		if len(l) > length {
+			length, label = len(l), l
+		}
+	}
+	return length, label
+}
+
+// WillTruncate returns how many characters the image slug for a given image information would exceed the length limit by, if it was not truncated
+//
+// It returns 0 for images whose slug fits, and an error for inputs that would not produce a slug at all
+func WillTruncate(image, imageHash string) (overBy int, err error) {
+	image, imageHash = imageSlugInputs(image, imageHash)
+	if len(image) == 0 || len(imageHash) < imageIDSlugHashLength {
+		return 0, ErrInvalidSlug
 	}
 
-	return slug, err
+	untruncatedLength := len(imageToDNSSubdomainReplacer.Replace(image)) + len(slugSeparator) + imageIDSlugHashLength
+	return max(untruncatedLength-maxDNSSubdomainLength, 0), nil
+}
+
+// ImageInfoToSlugDetailed returns a human-friendly representation for a given image information along with the components it is made of
+//
+// The slug and the components are made of the same normalized image, which the components are parsed from with ParseImageReference
+// before they are sanitized or truncated to fit in the slug, e.g. "docker.io" and "library/nginx" for "nginx".
+// Like the slug, the components are lowercased, so "myorg/MyApp" has the repository "myorg/myapp".
+// If the image is not a valid reference, it returns an error wrapping ErrInvalidImageReference
+func ImageInfoToSlugDetailed(image, imageHash string) (slug, registry, repository, tag, hashSuffix string, err error) {
+	image, imageHash = imageSlugInputs(image, imageHash)
+	slug, err = ImageInfoToSlug(image, imageHash)
+	if err != nil {
+		return "", "", "", "", "", err
+	}
+
+	ref, err := ParseImageReference(strings.ToLower(image))
+	if err != nil {
+		return "", "", "", "", "", err
+	}
+	registry, repository, tag = ref.Registry, ref.Repository, ref.Tag
+	hashSuffix = strings.ToLower(imageHash[len(imageHash)-imageIDSlugHashLength:])
+	return slug, registry, repository, tag, hashSuffix, nil
+}
+
+// ImageInfoToSlugWithCluster returns a human-friendly representation for a given image information, prefixed with the given cluster
+//
+// The cluster ID is sanitized into a DNS label, so the same image yields distinct slugs in different clusters
+func ImageInfoToSlugWithCluster(clusterID, image, imageHash string) (string, error) {
+	clusterSegment, err := ToValidDNSLabelName(clusterID)
+	if err != nil {
+		return "", ErrInvalidSlug
+	}
+
+	imageSlug, err := ImageInfoToSlugWithOptions(image, imageHash, WithMaxLen(maxDNSSubdomainLength-len(clusterSegment)-len(slugSeparator)))
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(slugFormat, clusterSegment, imageSlug), nil
+}
+
+// EscapeForLabelSelector returns a representation of a given value that is safe to use in a label selector equality expression, such as "app=<value>"
+//
+// Label selectors have no escaping syntax, so a value is safe exactly when it is a valid label value, which never contains
+// selector operators like "=", "!", ",", "(" or whitespace. Valid values, including all slugs that fit a label, are returned unchanged,
+// while any other value is sanitized with ToValidLabelValue
+func EscapeForLabelSelector(value string) string {
+	return ToValidLabelValue(value)
 }
 
 func SanitizeLabelValues(labels map[string]string) {
@@ -197,6 +1003,142 @@ func SanitizeLabelValues(labels map[string]string) {
 	}
 }
 
+// RedactSlug returns a given image slug with its repository replaced by a marker, e.g. "docker.io-***-latest-a3ac8c", for logging
+//
+// Since slugs join all parts of an image with the same separator, the repository is told apart with heuristics:
+// the slug must end with an image hash segment, start with a registry segment, that is one containing a period or "localhost",
+// and the segment right before the hash is taken as the tag. The repository is whatever lies in between.
+// Slugs without such a clear repository portion, e.g. "nginx-latest-a3ac8c", are returned unchanged.
+// The result is not a valid slug and is only meant for logs
+func RedactSlug(slug string) string {
+	if kind, err := ClassifySlug(slug); err != nil || kind != ImageSlug {
+		return slug
+	}
+
+	segments := strings.Split(slug, slugSeparator)
+	registry := segments[0]
+	if !strings.Contains(registry, ".") && registry != "localhost" {
+		return slug
+	}
+	// registry, repository, tag and hash
+	if len(segments) < 4 {
+		return slug
+	}
+
+	n := len(segments)
+	return strings.Join([]string{registry, redactedMarker, segments[n-2], segments[n-1]}, slugSeparator)
+}
+
+// CountSegments returns the number of separator-separated segments of a given slug, or 0 for an empty one
+//
+// It is a cheap structural check to run before fuller parsing, such as ClassifySlug
+func CountSegments(slug string) int {
+	if slug == "" {
+		return 0
+	}
+	return strings.Count(slug, slugSeparator) + 1
+}
+
+// SlugKind is what a slug represents, as told by the shape of its hash suffix
+type SlugKind string
+
+const (
+	// ImageSlug is a slug of an image, which ends with a single hash segment
+	ImageSlug SlugKind = "image"
+	// InstanceSlug is a slug of an instance ID, which ends with two hash segments
+	InstanceSlug SlugKind = "instance"
+)
+
+// ClassifySlug returns whether a given slug is an image slug or an instance ID slug, based on the shape of its hash suffix
+//
+// Image slugs end with a 6-character hex segment and instance ID slugs with two 4-character hex segments.
+// Names with neither suffix, such as instance ID slugs generated without hashes, return ErrUnparseableSlug
+func ClassifySlug(slug string) (SlugKind, error) {
+	segments := strings.Split(slug, slugSeparator)
+	n := len(segments)
+
+	switch {
+	case n > 2 && isLowerHexOfLength(segments[n-2], slugHashLength) && isLowerHexOfLength(segments[n-1], slugHashLength):
+		return InstanceSlug, nil
+	case n > 1 && isLowerHexOfLength(segments[n-1], imageIDSlugHashLength):
+		return ImageSlug, nil
+	default:
+		return "", fmt.Errorf("%w: %q has no hash suffix", ErrUnparseableSlug, slug)
+	}
+}
+
+// isLowerHexOfLength returns true if a given string consists of exactly length lowercase hexadecimal characters
+func isLowerHexOfLength(s string, length int) bool {
+	return len(s) == length && isHex(s) && strings.ToLower(s) == s
+}
+
+// ImageSlugInfo holds the components an image slug is made of
+type ImageSlugInfo struct {
+	// Image is the sanitized image segment, e.g. "docker.io-nginx-latest" for the "docker.io/nginx:latest" image
+	Image string
+	// HashSuffix is the hash suffix, e.g. "a3ac8c"
+	HashSuffix string
+}
+
+// InstanceIDSlugInfo holds the components an instance ID slug with hash-based identifiers is made of
+type InstanceIDSlugInfo struct {
+	// InstanceID is the segment describing the instance ID, e.g. "pod-reverse-proxy-nginx"
+	InstanceID string
+	// LeadingHash is the leading part of the hashed ID, e.g. "1ba5"
+	LeadingHash string
+	// TrailingHash is the trailing part of the hashed ID, e.g. "4aaf"
+	TrailingHash string
+}
+
+// SlugToImageInfo parses a given image slug back into its components
+//
+// Sanitization is lossy, so the image segment cannot be turned back into the exact original image.
+// If the slug is not an image slug, it returns ErrUnparseableSlug
+func SlugToImageInfo(slug string) (ImageSlugInfo, error) {
+	if kind, err := ClassifySlug(slug); err != nil || kind != ImageSlug || !IsValidSlug(slug) {
+		return ImageSlugInfo{}, fmt.Errorf("%w: %q is not an image slug", ErrUnparseableSlug, slug)
+	}
+
+	i := strings.LastIndex(slug, slugSeparator)
+	return ImageSlugInfo{Image: slug[:i], HashSuffix: slug[i+1:]}, nil
+}
+
+// SlugToInstanceID parses a given instance ID slug back into its components
+//
+// Only slugs with hash-based identifiers, that is slugs of containers or truncated ones, can be parsed.
+// If the slug is not such an instance ID slug, it returns ErrUnparseableSlug
+func SlugToInstanceID(slug string) (InstanceIDSlugInfo, error) {
+	if kind, err := ClassifySlug(slug); err != nil || kind != InstanceSlug || !IsValidSlug(slug) {
+		return InstanceIDSlugInfo{}, fmt.Errorf("%w: %q is not an instance ID slug", ErrUnparseableSlug, slug)
+	}
+
+	segments := strings.Split(slug, slugSeparator)
+	n := len(segments)
+	return InstanceIDSlugInfo{
+		InstanceID:   strings.Join(segments[:n-2], slugSeparator),
+		LeadingHash:  segments[n-2],
+		TrailingHash: segments[n-1],
+	}, nil
+}
+
+// FindSlugCollisions returns the indices at which every slug that appears more than once in a given slice occurs
+//
+// It is meant for audits: collisions among slugs of distinct inputs mean the hash suffix is too short for the data
+func FindSlugCollisions(slugs []string) map[string][]int {
+	indices := make(map[string][]int, len(slugs))
+	for i, slug := range slugs {
+		indices[slug] = append(indices[slug], i)
+	}
+
+	collisions := make(map[string][]int)
+	for slug, at := range indices {
+		if len(at) > 1 {
+			collisions[slug] = at
+		}
+	}
+	return collisions
+}
+
 // StringToSlug receives any string and returns a human-friendly representation of it as a slug
 //
 // If the given inputs would produce an invalid slug, it returns an appropriate error
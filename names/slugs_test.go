@@ -1,12 +1,26 @@
 package names
 
 import (
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/kubescape/k8s-interface/workloadinterface"
 	"github.com/stretchr/testify/assert"
 )
 
+// errNoHash is the error of failing hash suffix funcs
+var errNoHash = errors.New("no hash")
+
+// hashPrefix12 is a hash suffix func that keeps the first 12 characters of the hash
+func hashPrefix12(hash string) (string, error) {
+	if len(hash) < 12 {
+		return "", errors.New("hash too short")
+	}
+	return hash[:12], nil
+}
+
 func TestImageInfoToFriendlyName(t *testing.T) {
 	tt := []struct {
 		name      string
@@ -64,6 +78,48 @@ func TestImageInfoToFriendlyName(t *testing.T) {
 			"docker.io-kindest-local-path-provisioner-v0.0.23-kind.0-sha256-f2d0a02831ff3a03cf51343226670d5060623b43a4cfc4808bd0875b2c4b9501-4b9501",
 			nil,
 		},
+		{
+			"Digest-pinned image without a hash uses the digest as the hash",
+			"docker.io/library/nginx@sha256:f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac8c",
+			"",
+			"docker.io-library-nginx-a3ac8c",
+			nil,
+		},
+		{
+			"Digest-pinned image with a tag and without a hash keeps the tag",
+			"nginx:1.25@sha256:f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac8c",
+			"",
+			"nginx-1.25-a3ac8c",
+			nil,
+		},
+		{
+			"Image tag with surrounding whitespace returns matching value",
+			" nginx ",
+			"f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac8c",
+			"nginx-a3ac8c",
+			nil,
+		},
+		{
+			"Image tag with trailing newline returns matching value",
+			"nginx:latest\n",
+			"f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac8c\n",
+			"nginx-latest-a3ac8c",
+			nil,
+		},
+		{
+			"Windows-style image reference with backslashes returns matching value",
+			`mcr.microsoft.com\windows\nanoserver:ltsc2022`,
+			"f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac8c",
+			"mcr.microsoft.com-windows-nanoserver-ltsc2022-a3ac8c",
+			nil,
+		},
+		{
+			"Interior whitespace in image tag returns empty value and error",
+			"nginx :latest",
+			"f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac8c",
+			"",
+			ErrInvalidSlug,
+		},
 		{
 			"Empty image name returns empty value and error",
 			"",
@@ -113,90 +169,1550 @@ func TestImageInfoToFriendlyName(t *testing.T) {
 			got, err := ImageInfoToSlug(tc.imageTag, tc.imageHash)
 
 			assert.Equal(t, tc.expected, got)
-			assert.ErrorIs(t, tc.wantErr, err)
+			assert.ErrorIs(t, err, tc.wantErr)
 		})
 	}
 }
 
-func TestInstanceIDToFriendlyName(t *testing.T) {
+func FuzzImageInfoToSlug(f *testing.F) {
+	hash := "f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac8c"
+	f.Add("nginx", hash)
+	f.Add("nginx:latest", hash)
+	f.Add("docker.io/nginx:latest", hash)
+	f.Add("docker-pullable://gcr.io/etcd-development/etcd", hash)
+	f.Add("docker-pullable://GCR.io/etcD-development/Etcd", hash)
+	f.Add("quay.io/matthiasb_1/kubevuln:renaming", "quay.io/matthiasb_1/kubevuln@sha256:85c1b06d541d61ddb46efcd8b316855f544278c9ab27a07ec35bbe81be54fbec")
+	f.Add("docker.io/kindest/local-path-provisioner:v0.0.23-kind.0@sha256:f2d0a02831ff3a03cf51343226670d5060623b43a4cfc4808bd0875b2c4b9501", "docker.io/kindest/local-path-provisioner:v0.0.23-kind.0@sha256:f2d0a02831ff3a03cf51343226670d5060623b43a4cfc4808bd0875b2c4b9501")
+	f.Add("", hash)
+	f.Add("nginx", "")
+	f.Add("nginx", "3ac8c")
+	f.Add("nginx", "f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac:8c")
+	f.Add("nginx", "f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac/8c")
+	f.Add(strings.Repeat("a", 260)+"bc", hash)
+	// separators shrink while being replaced, so the sanitized image is shorter than the input
+	f.Add("docker-pullable://"+strings.Repeat("a", 230), hash)
+	f.Add(strings.Repeat("a", 245)+".io/nginx", hash)
+
+	f.Fuzz(func(t *testing.T, imageTag, imageHash string) {
+		got, err := ImageInfoToSlug(imageTag, imageHash)
+		if err != nil {
+			return
+		}
+		if !IsValidSlug(got) {
+			t.Errorf("ImageInfoToSlug(%q, %q) = %q, which is not a valid slug", imageTag, imageHash, got)
+		}
+		if len(got) > maxDNSSubdomainLength {
+			t.Errorf("ImageInfoToSlug(%q, %q) = %q, which is longer than %d characters", imageTag, imageHash, got, maxDNSSubdomainLength)
+		}
+	})
+}
+
+func TestNormalizeImageTag(t *testing.T) {
 	tt := []struct {
-		name           string
-		inputName      string
-		inputNamespace string
-		inputContainer string
-		inputKind      string
-		inputHashedID  string
-		want           string
-		wantErr        error
+		name  string
+		image string
+		want  string
 	}{
 		{
-			name:           "valid instanceID produces matching display name",
-			inputNamespace: "default",
-			inputKind:      "Pod",
-			inputName:      "reverse-proxy",
-			inputHashedID:  "1ba506b28f9ee9c7e8a0c98840fe5a1fe21142d225ecc526fbb535d0d6344aaf",
-			want:           "pod-reverse-proxy",
-			wantErr:        nil,
+			name:  "Valid image reference is kept",
+			image: "quay.io/kubescape/kubevuln:v0.2.108",
+			want:  "quay.io/kubescape/kubevuln:v0.2.108",
 		},
 		{
-			name:           "valid instanceID produces matching display name",
-			inputNamespace: "default",
-			inputKind:      "Service",
-			inputName:      "webapp",
-			inputHashedID:  "1ba506b28f9ee9c7e8a0c98840fe5a1fe21142d225ecc526fbb535d0d6344aaf",
-			want:           "service-webapp",
-			wantErr:        nil,
+			name:  "Registry port and uppercase tag",
+			image: "Localhost:5000/Nginx:Latest",
+			want:  "localhost:5000/nginx:latest",
 		},
 		{
-			name:           "valid instanceID with container name produces matching display name",
-			inputNamespace: "default",
-			inputContainer: "webapp",
-			inputKind:      "Service",
-			inputName:      "webapp",
-			inputHashedID:  "1ba506b28f9ee9c7e8a0c98840fe5a1fe21142d225ecc526fbb535d0d6344aaf",
-			want:           "service-webapp-webapp-1ba5-4aaf",
-			wantErr:        nil,
+			name:  "Invalid characters are replaced",
+			image: "nginx:latest$ (copy)",
+			want:  "nginx:latest---copy",
 		},
 		{
-			name:           "valid instanceID with different namespace name produces different hash",
-			inputNamespace: "blabla",
-			inputContainer: "webapp",
-			inputKind:      "Service",
-			inputName:      "webapp",
-			inputHashedID:  "000006b28f9ee9c7e8a0c98840fe5a1fe21142d225ecc526fbb535d0d6340000",
-			want:           "service-webapp-webapp-0000-0000",
-			wantErr:        nil,
+			name:  "Periods next to separators are dropped",
+			image: "registry./.nginx:1..2",
+			want:  "registry/nginx:12",
 		},
 		{
-			name:           "instanceID that produces overflowing slugs gets truncated to limit",
-			inputNamespace: "default",
-			inputKind:      "Service",
-			inputName:      "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaab",
-			inputHashedID:  "1ba506b28f9ee9c7e8a0c98840fe5a1fe21142d225ecc526fbb535d0d6344aaf",
-			want:           "service-aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa-1ba5-4aaf",
-			wantErr:        nil,
+			name:  "Surrounding spaces and separators are trimmed",
+			image: "  /nginx:latest/  ",
+			want:  "nginx:latest",
 		},
 		{
-			name:           "invalid instanceID produces matching error",
-			inputNamespace: "default",
-			inputKind:      "Service",
-			inputName:      "web/app",
-			inputHashedID:  "1ba506b28f9ee9c7e8a0c98840fe5a1fe21142d225ecc526fbb535d0d6344aaf",
-			want:           "",
-			wantErr:        ErrInvalidSlug,
+			name:  "Image without alphanumeric characters",
+			image: "./:@",
+			want:  "",
 		},
 	}
 
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
-			got, err := InstanceIDToSlug(tc.inputName, tc.inputKind, tc.inputContainer, tc.inputHashedID)
+			assert.Equal(t, tc.want, NormalizeImageTag(tc.image))
+		})
+	}
+}
+
+func FuzzNormalizeImageTag(f *testing.F) {
+	hash := "f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac8c"
+	f.Add("nginx")
+	f.Add("Localhost:5000/Nginx:Latest")
+	f.Add("docker-pullable://GCR.io/etcD-development/Etcd")
+	f.Add("registry./.nginx:1..2")
+	f.Add("nginx:latest$ (copy)")
+	f.Add("Café/ünïcode:tag")
+	f.Add(strings.Repeat("a.", 200))
+
+	f.Fuzz(func(t *testing.T, image string) {
+		normalized := NormalizeImageTag(image)
+		if normalized == "" {
+			return
+		}
+		if again := NormalizeImageTag(normalized); again != normalized {
+			t.Fatalf("NormalizeImageTag(%q) = %q, which normalizes again to %q", image, normalized, again)
+		}
+
+		got, err := ImageInfoToSlug(normalized, hash)
+		if err != nil {
+			t.Fatalf("ImageInfoToSlug(%q, %q) returned %v for the image normalized from %q", normalized, hash, err, image)
+		}
+		if !IsValidSlug(got) {
+			t.Fatalf("ImageInfoToSlug(%q, %q) = %q, which is not a valid slug", normalized, hash, got)
+		}
+	})
+}
+
+func TestImageInfoToSlugWithMaxLen(t *testing.T) {
+	tt := []struct {
+		name      string
+		imageTag  string
+		imageHash string
+		maxLen    int
+		expected  string
+		wantErr   error
+	}{
+		{
+			name:      "Short image tag fits the limit and is not truncated",
+			imageTag:  "docker.io/nginx:latest",
+			imageHash: "f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac8c",
+			maxLen:    128,
+			expected:  "docker.io-nginx-latest-a3ac8c",
+		},
+		{
+			name:      "Overflowing image tag gets truncated to the limit with the hash preserved",
+			imageTag:  strings.Repeat("a", 200),
+			imageHash: "f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac8c",
+			maxLen:    128,
+			expected:  strings.Repeat("a", 121) + "-a3ac8c",
+		},
+		{
+			name:      "Limit too small to fit the hash suffix returns an error",
+			imageTag:  "nginx",
+			imageHash: "f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac8c",
+			maxLen:    7,
+			expected:  "",
+			wantErr:   ErrNameTooLong,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ImageInfoToSlugWithOptions(tc.imageTag, tc.imageHash, WithMaxLen(tc.maxLen))
+
+			assert.Equal(t, tc.expected, got)
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.LessOrEqual(t, len(got), tc.maxLen)
+		})
+	}
+}
+
+func TestImageInfoToSlugWithHideDefaultRegistry(t *testing.T) {
+	tt := []struct {
+		name     string
+		imageTag string
+		opts     []Option
+		expected string
+	}{
+		{
+			name:     "Default registry is hidden",
+			imageTag: "docker.io/nginx:latest",
+			opts:     []Option{WithHideDefaultRegistry()},
+			expected: "nginx-latest-a3ac8c",
+		},
+		{
+			name:     "Other registries are kept",
+			imageTag: "gcr.io/etcd-development/etcd:latest",
+			opts:     []Option{WithHideDefaultRegistry()},
+			expected: "gcr.io-etcd-development-etcd-latest-a3ac8c",
+		},
+		{
+			name:     "Configured default registry is hidden",
+			imageTag: "gcr.io/etcd-development/etcd:latest",
+			opts:     []Option{WithHideDefaultRegistry(), WithDefaultRegistry("gcr.io")},
+			expected: "etcd-development-etcd-latest-a3ac8c",
+		},
+		{
+			name:     "Default registry is kept unless asked otherwise",
+			imageTag: "docker.io/nginx:latest",
+			expected: "docker.io-nginx-latest-a3ac8c",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ImageInfoToSlugWithOptions(tc.imageTag, "f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac8c", tc.opts...)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestWillTruncate(t *testing.T) {
+	imageHash := "f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac8c"
+
+	overBy, err := WillTruncate("docker.io/nginx:latest", imageHash)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, overBy)
+
+	overBy, err = WillTruncate(strings.Repeat("a", 246), imageHash)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, overBy)
+
+	overBy, err = WillTruncate(strings.Repeat("a", 260)+"bc", imageHash)
+	assert.NoError(t, err)
+	assert.Equal(t, 16, overBy)
+
+	// a digest-pinned image without a hash is identified by its digest, like ImageInfoToSlug does
+	overBy, err = WillTruncate("nginx@sha256:"+imageHash, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, overBy)
+
+	overBy, err = WillTruncate(strings.Repeat("a", 260)+"bc@sha256:"+imageHash, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 16, overBy)
+
+	_, err = WillTruncate("nginx", "3ac8c")
+	assert.ErrorIs(t, err, ErrInvalidSlug)
+}
+
+func TestImageInfoToSlugDetailed(t *testing.T) {
+	tt := []struct {
+		name               string
+		imageTag           string
+		imageHash          string
+		expectedRegistry   string
+		expectedRepository string
+		expectedTag        string
+	}{
+		{
+			name:               "Short image tag is normalized",
+			imageTag:           "nginx",
+			imageHash:          "f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac8c",
+			expectedRegistry:   "docker.io",
+			expectedRepository: "library/nginx",
+		},
+		{
+			name:               "Full image tag",
+			imageTag:           "docker.io/nginx:latest",
+			imageHash:          "f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac8c",
+			expectedRegistry:   "docker.io",
+			expectedRepository: "library/nginx",
+			expectedTag:        "latest",
+		},
+		{
+			name:               "Registry with a port",
+			imageTag:           "localhost:5000/team/app:v1.2",
+			imageHash:          "f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac8c",
+			expectedRegistry:   "localhost:5000",
+			expectedRepository: "team/app",
+			expectedTag:        "v1.2",
+		},
+		{
+			name:               "Image ID format",
+			imageTag:           "docker-pullable://gcr.io/etcd-development/etcd",
+			imageHash:          "f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac8c",
+			expectedRegistry:   "gcr.io",
+			expectedRepository: "etcd-development/etcd",
+		},
+		{
+			name:               "Digest is not part of the tag",
+			imageTag:           "docker.io/kindest/local-path-provisioner:v0.0.23-kind.0@sha256:f2d0a02831ff3a03cf51343226670d5060623b43a4cfc4808bd0875b2c4b9501",
+			imageHash:          "docker.io/kindest/local-path-provisioner:v0.0.23-kind.0@sha256:f2d0a02831ff3a03cf51343226670d5060623b43a4cfc4808bd0875b2c4b9501",
+			expectedRegistry:   "docker.io",
+			expectedRepository: "kindest/local-path-provisioner",
+			expectedTag:        "v0.0.23-kind.0",
+		},
+		{
+			name:               "Digest is the hash when none is given",
+			imageTag:           "docker.io/library/nginx@sha256:f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac8c",
+			imageHash:          "",
+			expectedRegistry:   "docker.io",
+			expectedRepository: "library/nginx",
+		},
+		{
+			name:               "Mixed-case repository is lowercased like in the slug",
+			imageTag:           "docker.io/myorg/MyApp:latest",
+			imageHash:          "f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac8c",
+			expectedRegistry:   "docker.io",
+			expectedRepository: "myorg/myapp",
+			expectedTag:        "latest",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			slug, registry, repository, tag, hashSuffix, err := ImageInfoToSlugDetailed(tc.imageTag, tc.imageHash)
+			assert.NoError(t, err)
+
+			expectedSlug, err := ImageInfoToSlug(tc.imageTag, tc.imageHash)
+			assert.NoError(t, err)
+			assert.Equal(t, expectedSlug, slug)
+			assert.True(t, strings.HasSuffix(slug, "-"+hashSuffix))
+
+			assert.Equal(t, tc.expectedRegistry, registry)
+			assert.Equal(t, tc.expectedRepository, repository)
+			assert.Equal(t, tc.expectedTag, tag)
+		})
+	}
+
+	_, _, _, _, _, err := ImageInfoToSlugDetailed("", "f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac8c")
+	assert.ErrorIs(t, err, ErrInvalidSlug)
+
+	// an image that makes a slug but is not a valid reference
+	_, _, _, _, _, err = ImageInfoToSlugDetailed("nginx:"+strings.Repeat("a", 129), "f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac8c")
+	assert.ErrorIs(t, err, ErrInvalidImageReference)
+}
+
+func TestImageInfoToSlugParsedReferenceCompatibility(t *testing.T) {
+	imageHash := "f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac8c"
+	digest := "sha256:" + imageHash
+	// the slugs generated before the digest was taken from ParseImageReference
+	tt := []struct {
+		image     string
+		imageHash string
+		want      string
+	}{
+		{image: "nginx", imageHash: imageHash, want: "nginx-a3ac8c"},
+		{image: "docker.io/nginx:latest", imageHash: imageHash, want: "docker.io-nginx-latest-a3ac8c"},
+		{image: "localhost:5000/team/app:v1.2", imageHash: imageHash, want: "localhost-5000-team-app-v1.2-a3ac8c"},
+		{image: "docker-pullable://gcr.io/etcd-development/etcd", imageHash: imageHash, want: "docker-pullable-gcr.io-etcd-development-etcd-a3ac8c"},
+		{image: "docker.io/myorg/MyApp:latest", imageHash: imageHash, want: "docker.io-myorg-myapp-latest-a3ac8c"},
+		{image: "docker.io/library/nginx@" + digest, want: "docker.io-library-nginx-a3ac8c"},
+		{image: "nginx:1.25@" + digest, want: "nginx-1.25-a3ac8c"},
+		{image: "docker-pullable://nginx@" + digest, want: "docker-pullable-nginx-a3ac8c"},
+		{image: `docker.io\library\nginx@` + digest, want: "docker.io-library-nginx-a3ac8c"},
+		{image: "nginx@" + digest, imageHash: "0000000000000000000000000000000000000000000000000000000000aaaaaa", want: "nginx-sha256-" + imageHash + "-aaaaaa"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.image, func(t *testing.T) {
+			got, err := ImageInfoToSlug(tc.image, tc.imageHash)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestImageInfoToSlugWithSeparatorAndHashSuffixLen(t *testing.T) {
+	imageHash := "f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac8c"
+	tt := []struct {
+		name       string
+		image      string
+		opts       []Option
+		want       string
+		wantErr    error
+		wantReason InvalidInputReason
+	}{
+		{
+			name:  "underscore separator separates the segments",
+			image: "quay.io/kubescape/kube-vuln:v0.2.108",
+			opts:  []Option{WithSeparator("_")},
+			want:  "quay.io_kubescape_kube-vuln_v0.2.108_a3ac8c",
+		},
+		{
+			name:  "shorter hash suffix",
+			image: "docker.io/nginx:latest",
+			opts:  []Option{WithHashSuffixLen(4)},
+			want:  "docker.io-nginx-latest-ac8c",
+		},
+		{
+			name:  "label-safe output",
+			image: "docker.io/" + strings.Repeat("a", 100) + ":latest",
+			opts:  []Option{WithMaxLen(63), WithHashSuffixLen(8), WithSeparator("-")},
+			want:  "docker.io-" + strings.Repeat("a", 44) + "-1ea3ac8c",
+		},
+		{
+			name:    "alphanumeric separator produces matching error",
+			image:   "docker.io/nginx:latest",
+			opts:    []Option{WithSeparator("x")},
+			wantErr: ErrInvalidSlug,
+		},
+		{
+			name:    "hash suffix longer than the hash produces matching error",
+			image:   "docker.io/nginx:latest",
+			opts:    []Option{WithHashSuffixLen(65)},
+			wantErr: ErrInvalidSlug,
+		},
+		{
+			name:  "hash suffix func replaces the hash suffix",
+			image: "docker.io/nginx:latest",
+			opts:  []Option{WithHashSuffixFunc(hashPrefix12)},
+			want:  "docker.io-nginx-latest-f4e3b6489888",
+		},
+		{
+			name:  "full hash suffix keeps the hash intact on truncation",
+			image: "docker.io/" + strings.Repeat("a", 100) + ":latest",
+			opts:  []Option{WithMaxLen(100), WithHashSuffixFunc(func(hash string) (string, error) { return hash, nil })},
+			want:  "docker.io-" + strings.Repeat("a", 25) + "-" + imageHash,
+		},
+		{
+			name:    "failing hash suffix func produces matching error",
+			image:   "docker.io/nginx:latest",
+			opts:    []Option{WithHashSuffixFunc(func(string) (string, error) { return "", errNoHash })},
+			wantErr: ErrInvalidHash,
+		},
+		{
+			name:       "invalid hash suffix produces matching error",
+			image:      "docker.io/nginx:latest",
+			opts:       []Option{WithHashSuffixFunc(func(string) (string, error) { return "a_b", nil })},
+			wantErr:    ErrInvalidHash,
+			wantReason: ReasonInvalidCharacters,
+		},
+		{
+			name:       "empty hash suffix produces matching error",
+			image:      "docker.io/nginx:latest",
+			opts:       []Option{WithHashSuffixFunc(func(string) (string, error) { return "", nil })},
+			wantErr:    ErrInvalidHash,
+			wantReason: ReasonEmpty,
+		},
+		{
+			name:       "too long hash suffix produces matching error",
+			image:      "docker.io/nginx:latest",
+			opts:       []Option{WithHashSuffixFunc(func(hash string) (string, error) { return strings.Repeat(hash, 4), nil })},
+			wantErr:    ErrInvalidHash,
+			wantReason: ReasonTooLong,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ImageInfoToSlugWithOptions(tc.image, imageHash, tc.opts...)
 
 			assert.Equal(t, tc.want, got)
 			assert.ErrorIs(t, err, tc.wantErr)
+			if errors.Is(err, ErrInvalidHash) && tc.wantReason == "" {
+				// the error of a failing hash suffix func is kept
+				assert.ErrorIs(t, err, errNoHash)
+			}
+			if tc.wantReason != "" {
+				var inputErr *InvalidInputError
+				if assert.True(t, errors.As(err, &inputErr)) {
+					assert.Equal(t, "hashSuffix", inputErr.Field)
+					assert.Equal(t, tc.wantReason, inputErr.Reason)
+				}
+			}
 		})
 	}
 }
 
+func TestInstanceIDToSlugWithOptions(t *testing.T) {
+	hashedID := "1ba506b28f9ee9c7e8a0c98840fe5a1fe21142d225ecc526fbb535d0d6344aaf"
+	tt := []struct {
+		name      string
+		inputName string
+		container string
+		opts      []Option
+		want      string
+		wantErr   error
+	}{
+		{
+			name:      "no options match InstanceIDToSlug",
+			inputName: "reverse-proxy",
+			container: "nginx",
+			want:      "pod-reverse-proxy-nginx-1ba5-4aaf",
+		},
+		{
+			name:      "underscore separator separates the segments",
+			inputName: "reverse-proxy",
+			container: "nginx",
+			opts:      []Option{WithSeparator("_")},
+			want:      "pod_reverse-proxy_nginx_1ba5_4aaf",
+		},
+		{
+			name:      "shorter hash segments",
+			inputName: "reverse-proxy",
+			container: "nginx",
+			opts:      []Option{WithHashSuffixLen(2)},
+			want:      "pod-reverse-proxy-nginx-1b-af",
+		},
+		{
+			name:      "label-safe output",
+			inputName: strings.Repeat("a", 100),
+			opts:      []Option{WithMaxLen(63)},
+			want:      "pod-" + strings.Repeat("a", 49) + "-1ba5-4aaf",
+		},
+		{
+			name:      "length that cannot fit the hashes produces matching error",
+			inputName: "reverse-proxy",
+			opts:      []Option{WithMaxLen(10)},
+			wantErr:   ErrNameTooLong,
+		},
+		{
+			name:      "hash suffix func replaces both hash segments",
+			inputName: "reverse-proxy",
+			container: "nginx",
+			opts:      []Option{WithHashSuffixFunc(hashPrefix12)},
+			want:      "pod-reverse-proxy-nginx-1ba506b28f9e",
+		},
+		{
+			name:      "hash suffix func output is kept on truncation",
+			inputName: strings.Repeat("a", 100),
+			opts:      []Option{WithMaxLen(63), WithHashSuffixFunc(hashPrefix12)},
+			want:      "pod-" + strings.Repeat("a", 46) + "-1ba506b28f9e",
+		},
+		{
+			name:      "failing hash suffix func produces matching error",
+			inputName: "reverse-proxy",
+			opts:      []Option{WithHashSuffixFunc(func(string) (string, error) { return "", errors.New("no hash") })},
+			wantErr:   ErrInvalidHash,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := InstanceIDToSlugWithOptions(tc.inputName, "Pod", tc.container, hashedID, tc.opts...)
+
+			assert.Equal(t, tc.want, got)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestImageInfoToSlugWithHashFromFront(t *testing.T) {
+	imageHash := "f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac8c"
+
+	fromEnd, err := ImageInfoToSlug("docker.io/nginx:latest", imageHash)
+	assert.NoError(t, err)
+	fromFront, err := ImageInfoToSlugWithOptions("docker.io/nginx:latest", imageHash, WithHashFromFront())
+	assert.NoError(t, err)
+
+	assert.Equal(t, "docker.io-nginx-latest-a3ac8c", fromEnd)
+	assert.Equal(t, "docker.io-nginx-latest-f4e3b6", fromFront)
+}
+
+func TestImageInfoToSlugLowercasesRepository(t *testing.T) {
+	imageHash := "f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac8c"
+
+	mixedCase, err := ImageInfoToSlug("docker.io/myorg/MyApp:latest", imageHash)
+	assert.NoError(t, err)
+	assert.Equal(t, "docker.io-myorg-myapp-latest-a3ac8c", mixedCase)
+	assert.True(t, IsValidDNSSubdomainName(mixedCase))
+
+	// lowercasing is lossy, so repositories that only differ in case share a slug
+	lowerCase, err := ImageInfoToSlug("docker.io/myorg/myapp:latest", imageHash)
+	assert.NoError(t, err)
+	assert.Equal(t, lowerCase, mixedCase)
+}
+
+func TestImageInfoToSlugWithStrictLabels(t *testing.T) {
+	imageHash := "f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac8c"
+	longRepository := "docker.io/" + strings.Repeat("a", 70) + ":latest"
+
+	got, err := ImageInfoToSlugWithOptions(longRepository, imageHash)
+	assert.NoError(t, err)
+	assert.True(t, IsValidSlug(got))
+
+	got, err = ImageInfoToSlugWithOptions(longRepository, imageHash, WithStrictLabels())
+	assert.ErrorIs(t, err, ErrNameTooLong)
+	assert.Equal(t, "", got)
+
+	got, err = ImageInfoToSlugWithOptions("docker.io/nginx:latest", imageHash, WithStrictLabels())
+	assert.NoError(t, err)
+	assert.Equal(t, "docker.io-nginx-latest-a3ac8c", got)
+}
+
+func TestInstanceIDToSlugWithStrictLabels(t *testing.T) {
+	hashedID := "1ba506b28f9ee9c7e8a0c98840fe5a1fe21142d225ecc526fbb535d0d6344aaf"
+	longName := strings.Repeat("a", 70)
+
+	got, err := InstanceIDToSlugWithOptions(longName, "Pod", "nginx", hashedID)
+	assert.NoError(t, err)
+	assert.True(t, IsValidSlug(got))
+
+	got, err = InstanceIDToSlugWithOptions(longName, "Pod", "nginx", hashedID, WithStrictLabels())
+	assert.ErrorIs(t, err, ErrNameTooLong)
+	assert.Equal(t, "", got)
+
+	got, err = InstanceIDToSlugWithOptions("webapp.example", "Pod", "nginx", hashedID, WithStrictLabels())
+	assert.NoError(t, err)
+	assert.Equal(t, "pod-webapp.example-nginx-1ba5-4aaf", got)
+}
+
+func TestImageInfoToSlugWithPlatform(t *testing.T) {
+	imageHash := "f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac8c"
+	tt := []struct {
+		name      string
+		image     string
+		platform  Platform
+		opts      []Option
+		want      string
+		wantErr   error
+		wantField string
+	}{
+		{
+			name:     "platform is placed before the hash suffix",
+			image:    "nginx:latest",
+			platform: Platform{OS: "linux", Architecture: "arm64", Variant: "v8"},
+			want:     "nginx-latest-linux-arm64-v8-a3ac8c",
+		},
+		{
+			name:     "platform without a variant",
+			image:    "nginx:latest",
+			platform: Platform{OS: "linux", Architecture: "amd64"},
+			want:     "nginx-latest-linux-amd64-a3ac8c",
+		},
+		{
+			name:     "platform follows the options",
+			image:    "nginx:latest",
+			platform: Platform{OS: "Linux", Architecture: "amd64"},
+			opts:     []Option{WithSeparator("."), WithHashSuffixLen(4)},
+			want:     "nginx.latest.linux.amd64.ac8c",
+		},
+		{
+			name:     "long images are truncated to make room for the platform",
+			image:    strings.Repeat("a", 260),
+			platform: Platform{OS: "linux", Architecture: "amd64"},
+			want:     strings.Repeat("a", 234) + "-linux-amd64-a3ac8c",
+		},
+		{
+			name:  "empty platform matches ImageInfoToSlug",
+			image: "nginx:latest",
+			want:  "nginx-latest-a3ac8c",
+		},
+		{
+			name:      "invalid platform produces matching error",
+			image:     "nginx:latest",
+			platform:  Platform{OS: "linux", Architecture: "amd_64"},
+			want:      "",
+			wantErr:   ErrInvalidPlatform,
+			wantField: "architecture",
+		},
+		{
+			name:      "missing OS is named in the error",
+			image:     "nginx:latest",
+			platform:  Platform{Architecture: "amd64"},
+			want:      "",
+			wantErr:   ErrInvalidSlug,
+			wantField: "os",
+		},
+		{
+			name:      "invalid variant is named in the error",
+			image:     "nginx:latest",
+			platform:  Platform{OS: "linux", Architecture: "arm64", Variant: "v8!"},
+			want:      "",
+			wantErr:   ErrInvalidPlatform,
+			wantField: "variant",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ImageInfoToSlugWithPlatform(tc.image, imageHash, tc.platform, tc.opts...)
+
+			assert.Equal(t, tc.want, got)
+			assert.ErrorIs(t, err, tc.wantErr)
+			if tc.wantField != "" {
+				var inputErr *InvalidInputError
+				if assert.True(t, errors.As(err, &inputErr)) {
+					assert.Equal(t, tc.wantField, inputErr.Field)
+				}
+			}
+		})
+	}
+}
+
+func TestImageInfoToSlugWithCluster(t *testing.T) {
+	imageHash := "f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac8c"
+
+	first, err := ImageInfoToSlugWithCluster("prod-eu", "docker.io/nginx:latest", imageHash)
+	assert.NoError(t, err)
+	assert.Equal(t, "prod-eu-docker.io-nginx-latest-a3ac8c", first)
+
+	second, err := ImageInfoToSlugWithCluster("arn:aws:eks:us-east-1:123456789012:cluster/Staging", "docker.io/nginx:latest", imageHash)
+	assert.NoError(t, err)
+	assert.Equal(t, "arn-aws-eks-us-east-1-123456789012-cluster-staging-docker.io-nginx-latest-a3ac8c", second)
+	assert.NotEqual(t, first, second)
+
+	long, err := ImageInfoToSlugWithCluster(strings.Repeat("c", 100), strings.Repeat("a", 300), imageHash)
+	assert.NoError(t, err)
+	assert.Len(t, long, maxDNSSubdomainLength)
+	assert.True(t, strings.HasSuffix(long, "-a3ac8c"))
+
+	_, err = ImageInfoToSlugWithCluster("///", "docker.io/nginx:latest", imageHash)
+	assert.ErrorIs(t, err, ErrInvalidSlug)
+}
+
+func TestInstanceIDToFriendlyName(t *testing.T) {
+	tt := []struct {
+		name           string
+		inputName      string
+		inputNamespace string
+		inputContainer string
+		inputKind      string
+		inputHashedID  string
+		want           string
+		wantErr        error
+	}{
+		{
+			name:           "valid instanceID produces matching display name",
+			inputNamespace: "default",
+			inputKind:      "Pod",
+			inputName:      "reverse-proxy",
+			inputHashedID:  "1ba506b28f9ee9c7e8a0c98840fe5a1fe21142d225ecc526fbb535d0d6344aaf",
+			want:           "pod-reverse-proxy",
+			wantErr:        nil,
+		},
+		{
+			name:           "valid instanceID produces matching display name",
+			inputNamespace: "default",
+			inputKind:      "Service",
+			inputName:      "webapp",
+			inputHashedID:  "1ba506b28f9ee9c7e8a0c98840fe5a1fe21142d225ecc526fbb535d0d6344aaf",
+			want:           "service-webapp",
+			wantErr:        nil,
+		},
+		{
+			name:           "valid instanceID with container name produces matching display name",
+			inputNamespace: "default",
+			inputContainer: "webapp",
+			inputKind:      "Service",
+			inputName:      "webapp",
+			inputHashedID:  "1ba506b28f9ee9c7e8a0c98840fe5a1fe21142d225ecc526fbb535d0d6344aaf",
+			want:           "service-webapp-webapp-1ba5-4aaf",
+			wantErr:        nil,
+		},
+		{
+			name:           "valid instanceID with different namespace name produces different hash",
+			inputNamespace: "blabla",
+			inputContainer: "webapp",
+			inputKind:      "Service",
+			inputName:      "webapp",
+			inputHashedID:  "000006b28f9ee9c7e8a0c98840fe5a1fe21142d225ecc526fbb535d0d6340000",
+			want:           "service-webapp-webapp-0000-0000",
+			wantErr:        nil,
+		},
+		{
+			name:           "instanceID that produces overflowing slugs gets truncated to limit",
+			inputNamespace: "default",
+			inputKind:      "Service",
+			inputName:      "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaab",
+			inputHashedID:  "1ba506b28f9ee9c7e8a0c98840fe5a1fe21142d225ecc526fbb535d0d6344aaf",
+			want:           "service-aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa-1ba5-4aaf",
+			wantErr:        nil,
+		},
+		{
+			name:           "invalid instanceID produces matching error",
+			inputNamespace: "default",
+			inputKind:      "Service",
+			inputName:      "web/app",
+			inputHashedID:  "1ba506b28f9ee9c7e8a0c98840fe5a1fe21142d225ecc526fbb535d0d6344aaf",
+			want:           "",
+			wantErr:        ErrInvalidSlug,
+		},
+		{
+			name:           "hashed ID too short for the hash suffix produces matching error",
+			inputNamespace: "default",
+			inputContainer: "webapp",
+			inputKind:      "Service",
+			inputName:      "webapp",
+			inputHashedID:  "1ba",
+			want:           "",
+			wantErr:        ErrInvalidSlug,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := InstanceIDToSlug(tc.inputName, tc.inputKind, tc.inputContainer, tc.inputHashedID)
+
+			assert.Equal(t, tc.want, got)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestInstanceIDToSlugWithSubresource(t *testing.T) {
+	hashedID := "1ba506b28f9ee9c7e8a0c98840fe5a1fe21142d225ecc526fbb535d0d6344aaf"
+	tt := []struct {
+		name           string
+		inputName      string
+		inputKind      string
+		inputContainer string
+		subresource    string
+		want           string
+		wantErr        error
+	}{
+		{
+			name:        "subresource is inserted before the hash",
+			inputName:   "reverse-proxy",
+			inputKind:   "Pod",
+			subresource: "status",
+			want:        "pod-reverse-proxy-status-1ba5-4aaf",
+		},
+		{
+			name:           "subresource follows the container name",
+			inputName:      "webapp",
+			inputKind:      "Pod",
+			inputContainer: "nginx",
+			subresource:    "logs",
+			want:           "pod-webapp-nginx-logs-1ba5-4aaf",
+		},
+		{
+			name:      "empty subresource matches InstanceIDToSlug",
+			inputName: "reverse-proxy",
+			inputKind: "Pod",
+			want:      "pod-reverse-proxy",
+		},
+		{
+			name:        "invalid subresource produces matching error",
+			inputName:   "reverse-proxy",
+			inputKind:   "Pod",
+			subresource: "Status/Scale",
+			want:        "",
+			wantErr:     ErrInvalidSlug,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := InstanceIDToSlugWithSubresource(tc.inputName, tc.inputKind, tc.inputContainer, tc.subresource, hashedID)
+
+			assert.Equal(t, tc.want, got)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestInstanceIDToSlugWithAPIVersion(t *testing.T) {
+	hashedID := "1ba506b28f9ee9c7e8a0c98840fe5a1fe21142d225ecc526fbb535d0d6344aaf"
+	tt := []struct {
+		name           string
+		apiVersion     string
+		inputName      string
+		inputKind      string
+		inputContainer string
+		want           string
+		wantErr        error
+	}{
+		{
+			name:           "API version is placed before the kind",
+			apiVersion:     "apps/v1",
+			inputName:      "webapp",
+			inputKind:      "Deployment",
+			inputContainer: "nginx",
+			want:           "apps-v1-deployment-webapp-nginx-1ba5-4aaf",
+		},
+		{
+			name:           "core API version",
+			apiVersion:     "v1",
+			inputName:      "webapp",
+			inputKind:      "Pod",
+			inputContainer: "nginx",
+			want:           "v1-pod-webapp-nginx-1ba5-4aaf",
+		},
+		{
+			name:           "empty API version matches InstanceIDToSlug",
+			inputName:      "webapp",
+			inputKind:      "Pod",
+			inputContainer: "nginx",
+			want:           "pod-webapp-nginx-1ba5-4aaf",
+		},
+		{
+			name:           "long names are truncated before the hash",
+			apiVersion:     "apps/v1",
+			inputName:      strings.Repeat("a", 260),
+			inputKind:      "Deployment",
+			inputContainer: "nginx",
+			want:           "apps-v1-deployment-" + strings.Repeat("a", 224) + "-1ba5-4aaf",
+		},
+		{
+			name:       "API version without alphanumeric characters produces matching error",
+			apiVersion: "///",
+			inputName:  "webapp",
+			inputKind:  "Pod",
+			want:       "",
+			wantErr:    ErrInvalidSlug,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := InstanceIDToSlugWithAPIVersion(tc.apiVersion, tc.inputName, tc.inputKind, tc.inputContainer, hashedID)
+
+			assert.Equal(t, tc.want, got)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestInstanceIDToSlugWithHashParts(t *testing.T) {
+	tt := []struct {
+		name      string
+		container string
+		hashedID  string
+		prefixLen int
+		suffixLen int
+		want      string
+		wantErr   error
+	}{
+		{
+			name:      "default parts match InstanceIDToSlug",
+			container: "nginx",
+			hashedID:  "1ba506b28f9ee9c7e8a0c98840fe5a1fe21142d225ecc526fbb535d0d6344aaf",
+			prefixLen: 4,
+			suffixLen: 4,
+			want:      "pod-webapp-nginx-1ba5-4aaf",
+		},
+		{
+			name:      "custom parts are taken from both ends",
+			container: "nginx",
+			hashedID:  "1ba506b28f9ee9c7e8a0c98840fe5a1fe21142d225ecc526fbb535d0d6344aaf",
+			prefixLen: 6,
+			suffixLen: 2,
+			want:      "pod-webapp-nginx-1ba506-af",
+		},
+		{
+			name:      "overlapping parts produce matching error",
+			container: "nginx",
+			hashedID:  "1ba506",
+			prefixLen: 4,
+			suffixLen: 4,
+			want:      "",
+			wantErr:   ErrInvalidHash,
+		},
+		{
+			name:      "non-positive parts produce matching error",
+			container: "nginx",
+			hashedID:  "1ba506",
+			prefixLen: 0,
+			suffixLen: 4,
+			want:      "",
+			wantErr:   ErrInvalidHash,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := InstanceIDToSlugWithHashParts("webapp", "Pod", tc.container, tc.hashedID, tc.prefixLen, tc.suffixLen)
+
+			assert.Equal(t, tc.want, got)
+			assert.ErrorIs(t, err, tc.wantErr)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, ErrInvalidSlug)
+			}
+		})
+	}
+}
+
+func TestEscapeForLabelSelector(t *testing.T) {
+	tt := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "slug is unchanged",
+			input: "pod-reverse-proxy-nginx-1ba5-4aaf",
+			want:  "pod-reverse-proxy-nginx-1ba5-4aaf",
+		},
+		{
+			name:  "valid mixed-case value is unchanged",
+			input: "Release_1.2",
+			want:  "Release_1.2",
+		},
+		{
+			name:  "selector operators are replaced",
+			input: "app=nginx,tier!=db",
+			want:  "app-nginx-tier--db",
+		},
+		{
+			name:  "whitespace and parentheses are replaced",
+			input: "in (a b)",
+			want:  "in--a-b",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got := EscapeForLabelSelector(tc.input)
+
+			assert.Equal(t, tc.want, got)
+			assert.True(t, IsValidLabelValue(got))
+		})
+	}
+}
+
+func TestImageInfoToSlugWithChecksum(t *testing.T) {
+	imageHash := "f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac8c"
+
+	got, err := ImageInfoToSlugWithChecksum("docker.io/nginx:latest", imageHash)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(got, "docker.io-nginx-latest-a3ac8c-"), got)
+	assert.Len(t, got, len("docker.io-nginx-latest-a3ac8c-")+4)
+	assert.True(t, IsValidSlug(got))
+	assert.True(t, VerifyChecksum(got))
+
+	long, err := ImageInfoToSlugWithChecksum(strings.Repeat("a", 300), imageHash)
+	assert.NoError(t, err)
+	assert.Len(t, long, 253)
+	assert.True(t, VerifyChecksum(long))
+
+	_, err = ImageInfoToSlugWithChecksum("", imageHash)
+	assert.ErrorIs(t, err, ErrInvalidSlug)
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	imageHash := "f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac8c"
+	slug, err := ImageInfoToSlugWithChecksum("docker.io/nginx:latest", imageHash)
+	assert.NoError(t, err)
+
+	tampered := strings.Replace(slug, "nginx", "nginy", 1)
+
+	assert.True(t, VerifyChecksum(slug))
+	assert.False(t, VerifyChecksum(tampered))
+	assert.False(t, VerifyChecksum("docker.io-nginx-latest-a3ac8c"))
+	assert.False(t, VerifyChecksum(""))
+}
+
+func TestImageInfoToFullIdentifier(t *testing.T) {
+	imageHash := "f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac8c"
+	tt := []struct {
+		name       string
+		image      string
+		imageHash  string
+		want       string
+		wantErr    error
+		wantReason InvalidInputReason
+	}{
+		{
+			name:      "full digest is appended to the image",
+			image:     "docker.io/nginx:latest",
+			imageHash: imageHash,
+			want:      "docker.io/nginx:latest@sha256:" + imageHash,
+		},
+		{
+			name:      "prefixed digest is kept once",
+			image:     "docker.io/nginx:latest",
+			imageHash: "sha256:" + imageHash,
+			want:      "docker.io/nginx:latest@sha256:" + imageHash,
+		},
+		{
+			name:      "digest in the image is replaced",
+			image:     "docker.io/nginx@sha256:0000000000000000000000000000000000000000000000000000000000000000",
+			imageHash: imageHash,
+			want:      "docker.io/nginx@sha256:" + imageHash,
+		},
+		{
+			name:       "truncated digest produces matching error",
+			image:      "docker.io/nginx:latest",
+			imageHash:  imageHash[:12],
+			wantErr:    ErrInvalidHash,
+			wantReason: ReasonTooShort,
+		},
+		{
+			name:       "too long digest produces matching error",
+			image:      "docker.io/nginx:latest",
+			imageHash:  imageHash + "00",
+			wantErr:    ErrInvalidHash,
+			wantReason: ReasonTooLong,
+		},
+		{
+			name:       "non-hex digest produces matching error",
+			image:      "docker.io/nginx:latest",
+			imageHash:  imageHash[:63] + "z",
+			wantErr:    ErrInvalidHash,
+			wantReason: ReasonInvalidCharacters,
+		},
+		{
+			name:       "digest of another algorithm produces matching error",
+			image:      "docker.io/nginx:latest",
+			imageHash:  "sha512:" + imageHash,
+			wantErr:    ErrInvalidHash,
+			wantReason: ReasonInvalidCharacters,
+		},
+		{
+			name:       "empty digest produces matching error",
+			image:      "docker.io/nginx:latest",
+			imageHash:  "",
+			wantErr:    ErrInvalidHash,
+			wantReason: ReasonEmpty,
+		},
+		{
+			name:      "empty image produces matching error",
+			image:     "",
+			imageHash: imageHash,
+			wantErr:   ErrInvalidSlug,
+		},
+		{
+			name:      "unreasonably long image produces matching error",
+			image:     strings.Repeat("a", 1000),
+			imageHash: imageHash,
+			wantErr:   ErrNameTooLong,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ImageInfoToFullIdentifier(tc.image, tc.imageHash)
+
+			assert.Equal(t, tc.want, got)
+			assert.ErrorIs(t, err, tc.wantErr)
+			if tc.wantReason != "" {
+				var inputErr *InvalidInputError
+				if assert.True(t, errors.As(err, &inputErr)) {
+					assert.Equal(t, "imageHash", inputErr.Field)
+					assert.Equal(t, tc.wantReason, inputErr.Reason)
+				}
+			}
+		})
+	}
+}
+
+func TestImageInfoFromShortHashToSlug(t *testing.T) {
+	tt := []struct {
+		name       string
+		image      string
+		shortHash  string
+		want       string
+		wantErr    error
+		wantReason InvalidInputReason
+	}{
+		{
+			name:      "short hash is used as the suffix",
+			image:     "nginx:latest",
+			shortHash: "a3ac8c",
+			want:      "nginx-latest-a3ac8c",
+		},
+		{
+			name:      "uppercase short hash is lowercased",
+			image:     "quay.io/kubescape/kubevuln:v0.2.108",
+			shortHash: "0A9B8C7D",
+			want:      "quay.io-kubescape-kubevuln-v0.2.108-0a9b8c7d",
+		},
+		{
+			name:       "too long short hash produces matching error",
+			image:      "nginx:latest",
+			shortHash:  "0123456789abc",
+			wantErr:    ErrInvalidHash,
+			wantReason: ReasonTooLong,
+		},
+		{
+			name:       "too short short hash produces matching error",
+			image:      "nginx:latest",
+			shortHash:  "a3a",
+			wantErr:    ErrInvalidHash,
+			wantReason: ReasonTooShort,
+		},
+		{
+			name:       "non-hex short hash produces matching error",
+			image:      "nginx:latest",
+			shortHash:  "a3ac8z",
+			wantErr:    ErrInvalidHash,
+			wantReason: ReasonInvalidCharacters,
+		},
+		{
+			name:      "empty image produces matching error",
+			image:     "",
+			shortHash: "a3ac8c",
+			wantErr:   ErrInvalidSlug,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ImageInfoFromShortHashToSlug(tc.image, tc.shortHash)
+
+			assert.Equal(t, tc.want, got)
+			assert.ErrorIs(t, err, tc.wantErr)
+			if tc.wantReason != "" {
+				var inputErr *InvalidInputError
+				if assert.True(t, errors.As(err, &inputErr)) {
+					assert.Equal(t, "shortHash", inputErr.Field)
+					assert.Equal(t, tc.wantReason, inputErr.Reason)
+				}
+			}
+		})
+	}
+}
+
+func TestVerifyImageSlug(t *testing.T) {
+	imageHash := "f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac8c"
+	longImage := "docker.io/" + strings.Repeat("a", 300) + ":latest"
+	truncated, err := ImageInfoToSlug(longImage, imageHash)
+	assert.NoError(t, err)
+	shortened, err := ImageInfoToSlugWithOptions("quay.io/kubescape/kubevuln:v0.2.108", imageHash, WithMaxLen(24))
+	assert.NoError(t, err)
+	withMaxLen := []Option{WithMaxLen(24)}
+
+	tt := []struct {
+		name  string
+		slug  string
+		image string
+		opts  []Option
+		want  bool
+	}{
+		{
+			name:  "slug of the image matches",
+			slug:  "docker.io-nginx-latest-a3ac8c",
+			image: "docker.io/nginx:latest",
+			want:  true,
+		},
+		{
+			name:  "truncated slug of a long image matches",
+			slug:  truncated,
+			image: longImage,
+			want:  true,
+		},
+		{
+			name:  "slug truncated to a shorter length matches with the same max length",
+			slug:  shortened,
+			image: "quay.io/kubescape/kubevuln:v0.2.108",
+			opts:  withMaxLen,
+			want:  true,
+		},
+		{
+			name:  "slug truncated to a shorter length does not match at the default length",
+			slug:  shortened,
+			image: "quay.io/kubescape/kubevuln:v0.2.108",
+			want:  false,
+		},
+		{
+			name:  "arbitrarily shortened prefix of the image does not match",
+			slug:  "ngi-a3ac8c",
+			image: "nginx:latest",
+			want:  false,
+		},
+		{
+			name:  "arbitrarily shortened prefix of a full image does not match",
+			slug:  "doc-a3ac8c",
+			image: "docker.io/library/nginx:1.2",
+			want:  false,
+		},
+		{
+			name:  "slug of a different image does not match",
+			slug:  "docker.io-nginx-latest-a3ac8c",
+			image: "docker.io/nginx:1.25",
+			want:  false,
+		},
+		{
+			name:  "slug with a different hash does not match",
+			slug:  "docker.io-nginx-latest-000000",
+			image: "docker.io/nginx:latest",
+			want:  false,
+		},
+		{
+			name:  "hash alone does not match",
+			slug:  "a3ac8c",
+			image: "docker.io/nginx:latest",
+			want:  false,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, VerifyImageSlug(tc.slug, tc.image, imageHash, tc.opts...))
+		})
+	}
+}
+
+func TestLongestLabelLength(t *testing.T) {
+	tt := []struct {
+		name       string
+		input      string
+		wantLength int
+		wantLabel  string
+	}{
+		{
+			name:       "longest label in the middle is found",
+			input:      "registry.k8s.io-" + strings.Repeat("a", 70) + ".example.com",
+			wantLength: 73,
+			wantLabel:  "io-" + strings.Repeat("a", 70),
+		},
+		{
+			name:       "first of equally long labels is found",
+			input:      "abc.def.gh",
+			wantLength: 3,
+			wantLabel:  "abc",
+		},
+		{
+			name:       "name without periods is a single label",
+			input:      "nginx-latest-a3ac8c",
+			wantLength: 19,
+			wantLabel:  "nginx-latest-a3ac8c",
+		},
+		{
+			name:       "empty name has no label",
+			input:      "",
+			wantLength: 0,
+			wantLabel:  "",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			length, label := LongestLabelLength(tc.input)
+
+			assert.Equal(t, tc.wantLength, length)
+			assert.Equal(t, tc.wantLabel, label)
+		})
+	}
+}
+
+func TestRedactSlug(t *testing.T) {
+	tt := []struct {
+		name string
+		slug string
+		want string
+	}{
+		{
+			name: "multi-segment repository is redacted",
+			slug: "quay.io-kubescape-kubevuln-v0.2.108-a3ac8c",
+			want: "quay.io-***-v0.2.108-a3ac8c",
+		},
+		{
+			name: "single-segment repository with a registry is redacted",
+			slug: "docker.io-nginx-latest-a3ac8c",
+			want: "docker.io-***-latest-a3ac8c",
+		},
+		{
+			name: "local registry is recognized",
+			slug: "localhost-myorg-myapp-dev-a3ac8c",
+			want: "localhost-***-dev-a3ac8c",
+		},
+		{
+			name: "single-segment image without a registry is unchanged",
+			slug: "nginx-latest-a3ac8c",
+			want: "nginx-latest-a3ac8c",
+		},
+		{
+			name: "image without a tag is unchanged",
+			slug: "docker.io-nginx-a3ac8c",
+			want: "docker.io-nginx-a3ac8c",
+		},
+		{
+			name: "instance ID slug is unchanged",
+			slug: "pod-reverse-proxy-1ba5-4aaf",
+			want: "pod-reverse-proxy-1ba5-4aaf",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, RedactSlug(tc.slug))
+		})
+	}
+}
+
+func TestCountSegments(t *testing.T) {
+	assert.Equal(t, 2, CountSegments("nginx-a3ac8c"))
+	assert.Equal(t, 5, CountSegments("pod-reverse-proxy-1ba5-4aaf"))
+	assert.Equal(t, 1, CountSegments("nginx"))
+	assert.Equal(t, 0, CountSegments(""))
+}
+
+func TestClassifySlug(t *testing.T) {
+	tt := []struct {
+		name    string
+		slug    string
+		want    SlugKind
+		wantErr error
+	}{
+		{
+			name: "image slug",
+			slug: "nginx-a3ac8c",
+			want: ImageSlug,
+		},
+		{
+			name: "image slug with a registry",
+			slug: "docker.io-nginx-latest-a3ac8c",
+			want: ImageSlug,
+		},
+		{
+			name: "instance ID slug",
+			slug: "pod-reverse-proxy-nginx-1ba5-4aaf",
+			want: InstanceSlug,
+		},
+		{
+			name: "instance ID slug from an external source",
+			slug: "default-Pod-reverse-proxy-1ba5-4aaf",
+			want: InstanceSlug,
+		},
+		{
+			name:    "instance ID slug without hashes",
+			slug:    "pod-reverse-proxy",
+			wantErr: ErrUnparseableSlug,
+		},
+		{
+			name:    "hash alone",
+			slug:    "a3ac8c",
+			wantErr: ErrUnparseableSlug,
+		},
+		{
+			name:    "uppercase hash",
+			slug:    "nginx-A3AC8C",
+			wantErr: ErrUnparseableSlug,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ClassifySlug(tc.slug)
+
+			assert.Equal(t, tc.want, got)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestSlugToImageInfo(t *testing.T) {
+	tt := []struct {
+		name    string
+		slug    string
+		want    ImageSlugInfo
+		wantErr error
+	}{
+		{
+			name: "image slug is parsed",
+			slug: "docker.io-nginx-latest-a3ac8c",
+			want: ImageSlugInfo{Image: "docker.io-nginx-latest", HashSuffix: "a3ac8c"},
+		},
+		{
+			name:    "instance ID slug produces matching error",
+			slug:    "pod-reverse-proxy-nginx-1ba5-4aaf",
+			wantErr: ErrUnparseableSlug,
+		},
+		{
+			name:    "invalid slug produces matching error",
+			slug:    "Nginx-a3ac8c",
+			wantErr: ErrUnparseableSlug,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := SlugToImageInfo(tc.slug)
+
+			assert.Equal(t, tc.want, got)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestSlugToImageInfoRoundTrip(t *testing.T) {
+	imageHash := "f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac8c"
+	slug, err := ImageInfoToSlug("quay.io/kubescape/kubevuln:v0.2.108", imageHash)
+	assert.NoError(t, err)
+
+	got, err := SlugToImageInfo(slug)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "quay.io-kubescape-kubevuln-v0.2.108", got.Image)
+	assert.Equal(t, imageHash[len(imageHash)-6:], got.HashSuffix)
+}
+
+func TestSlugToInstanceID(t *testing.T) {
+	tt := []struct {
+		name    string
+		slug    string
+		want    InstanceIDSlugInfo
+		wantErr error
+	}{
+		{
+			name: "instance ID slug is parsed",
+			slug: "pod-reverse-proxy-nginx-1ba5-4aaf",
+			want: InstanceIDSlugInfo{InstanceID: "pod-reverse-proxy-nginx", LeadingHash: "1ba5", TrailingHash: "4aaf"},
+		},
+		{
+			name:    "instance ID slug without hashes produces matching error",
+			slug:    "pod-reverse-proxy",
+			wantErr: ErrUnparseableSlug,
+		},
+		{
+			name:    "image slug produces matching error",
+			slug:    "docker.io-nginx-latest-a3ac8c",
+			wantErr: ErrUnparseableSlug,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := SlugToInstanceID(tc.slug)
+
+			assert.Equal(t, tc.want, got)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestFindSlugCollisions(t *testing.T) {
+	slugs := []string{
+		"pod-nginx-1ba5-4aaf",
+		"pod-webapp-1ba5-4aaf",
+		"pod-nginx-1ba5-4aaf",
+		"pod-redis-0000-0000",
+		"pod-nginx-1ba5-4aaf",
+		"pod-webapp-1ba5-4aaf",
+	}
+
+	expected := map[string][]int{
+		"pod-nginx-1ba5-4aaf":  {0, 2, 4},
+		"pod-webapp-1ba5-4aaf": {1, 5},
+	}
+	assert.Equal(t, expected, FindSlugCollisions(slugs))
+	assert.Empty(t, FindSlugCollisions([]string{"pod-nginx", "pod-webapp"}))
+}
+
+func FuzzInstanceIDRoundTrip(f *testing.F) {
+	hashedID := "1ba506b28f9ee9c7e8a0c98840fe5a1fe21142d225ecc526fbb535d0d6344aaf"
+	f.Add("reverse-proxy", "Pod", "", hashedID)
+	f.Add("webapp", "Service", "webapp", hashedID)
+	f.Add("webapp", "Service", "webapp", "000006b28f9ee9c7e8a0c98840fe5a1fe21142d225ecc526fbb535d0d6340000")
+	f.Add(strings.Repeat("a", 300)+"b", "Service", "", hashedID)
+	f.Add(strings.Repeat("a", 238)+".bbbbbbbbbb", "Pod", "", hashedID)
+	f.Add("web/app", "Service", "", hashedID)
+	f.Add("webapp", "Service", "webapp", "1ba")
+
+	f.Fuzz(func(t *testing.T, name, kind, containerName, hashedID string) {
+		got, err := InstanceIDToSlug(name, kind, containerName, hashedID)
+		if err != nil {
+			return
+		}
+		if !IsValidSlug(got) {
+			t.Fatalf("InstanceIDToSlug(%q, %q, %q, %q) = %q, which is not a valid slug", name, kind, containerName, hashedID, got)
+		}
+
+		// the components must be recoverable from the slug unless it was truncated
+		hashless := strings.ToLower(fmt.Sprintf(instanceIDSlugHashlessFormat, kind, name))
+		hashSuffix := strings.ToLower(fmt.Sprintf(slugFormat, hashedID[:slugHashLength], hashedID[len(hashedID)-slugHashLength:]))
+		untruncated := hashless
+		if containerName != "" {
+			untruncated = fmt.Sprintf("%s-%s-%s", hashless, containerName, hashSuffix)
+		}
+		truncated := len(untruncated) >= maxHashlessStringLength
+		if containerName != "" || truncated {
+			if !strings.HasSuffix(got, slugSeparator+hashSuffix) {
+				t.Fatalf("InstanceIDToSlug(%q, %q, %q, %q) = %q, which does not end with the hash suffix %q", name, kind, containerName, hashedID, got, hashSuffix)
+			}
+			// the hash-based identifiers must survive parsing, as long as they look like hex
+			if info, err := SlugToInstanceID(got); err == nil && info.LeadingHash+slugSeparator+info.TrailingHash != hashSuffix {
+				t.Fatalf("SlugToInstanceID(%q) = %+v, which does not match the hash suffix %q", got, info, hashSuffix)
+			}
+		}
+		if !truncated && !strings.HasPrefix(got, hashless) {
+			t.Fatalf("InstanceIDToSlug(%q, %q, %q, %q) = %q, which does not start with %q", name, kind, containerName, hashedID, got, hashless)
+		}
+	})
+}
+
 func TestIsValidSubdomainName(t *testing.T) {
 	tt := []struct {
 		name      string
@@ -263,16 +1779,145 @@ func TestIsValidSubdomainName(t *testing.T) {
 			inputName: "nGinx",
 			want:      false,
 		},
+		{
+			name:      "Empty labels should be invalid",
+			inputName: "docker..io",
+			want:      false,
+		},
+		{
+			name:      "Labels ending with a hyphen should be invalid",
+			inputName: "docker-.io",
+			want:      false,
+		},
+		{
+			name:      "Single-character name should be valid",
+			inputName: "a",
+			want:      true,
+		},
+		{
+			name:      "Single-character labels should be valid",
+			inputName: "a.b",
+			want:      true,
+		},
+		{
+			name:      "Several single-character labels should be valid",
+			inputName: "a.b.c",
+			want:      true,
+		},
+		{
+			name:      "Single hyphen should be invalid",
+			inputName: "-",
+			want:      false,
+		},
 	}
 
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
 			got := IsValidDNSSubdomainName(tc.inputName)
 			assert.Equal(t, tc.want, got)
+			assert.Equal(t, tc.want, IsValidDNSSubdomainBytes([]byte(tc.inputName)))
 		})
 	}
 }
 
+func TestIsValidDNSNameMax(t *testing.T) {
+	subdomain := "docker.io-library-nginx-latest-a3ac8c"
+	label := "docker-io-library-nginx-latest-a3ac8c"
+
+	assert.False(t, IsValidDNSSubdomainNameMax(subdomain, 20))
+	assert.True(t, IsValidDNSSubdomainNameMax(subdomain, 200))
+	assert.True(t, IsValidDNSSubdomainNameMax(subdomain, len(subdomain)))
+	assert.False(t, IsValidDNSSubdomainNameMax("Docker.io", 200))
+
+	assert.False(t, IsValidDNSLabelNameMax(label, 20))
+	assert.True(t, IsValidDNSLabelNameMax(label, 200))
+	assert.False(t, IsValidDNSLabelNameMax(subdomain, 200))
+}
+
+func TestDNSSubdomainRegexp(t *testing.T) {
+	inputs := []string{
+		"nginx",
+		"n:ginx",
+		"n/ginx",
+		"",
+		"docker.io",
+		"web-app",
+		"-webapp",
+		"webapp-",
+		"nGinx",
+		"a",
+		"a.b.c",
+		"-",
+		"a..b",
+		"a.-b",
+		"a-.b",
+		".a",
+		strings.Repeat("a", 63),
+		strings.Repeat("a", 64),
+		strings.Repeat("a", 63) + ".io",
+		strings.Repeat("a", 64) + ".io",
+		strings.Repeat(strings.Repeat("a", 63)+".", 3) + strings.Repeat("a", 61),
+	}
+
+	for _, input := range inputs {
+		assert.Equal(t, IsValidDNSSubdomainName(input), DNSSubdomainRegexp().MatchString(input), input)
+	}
+
+	t.Run("the length limit is left to callers", func(t *testing.T) {
+		name := strings.Repeat(strings.Repeat("a", 63)+".", 3) + strings.Repeat("a", 62)
+
+		assert.False(t, IsValidDNSSubdomainName(name))
+		assert.True(t, DNSSubdomainRegexp().MatchString(name))
+	})
+
+	t.Run("every call returns a copy", func(t *testing.T) {
+		assert.NotSame(t, DNSSubdomainRegexp(), DNSSubdomainRegexp())
+	})
+}
+
+func TestIsValidDNSSubdomainNameRules(t *testing.T) {
+	t.Run("overall name must be non-empty", func(t *testing.T) {
+		assert.False(t, IsValidDNSSubdomainName(""))
+		assert.True(t, IsValidDNSSubdomainName("a"))
+	})
+
+	t.Run("every label must be non-empty and start and end with an alphanumeric character", func(t *testing.T) {
+		for _, name := range []string{"a..b", ".a", "a.", "a.-b", "a-.b", "a.b-", "-a.b"} {
+			assert.False(t, IsValidDNSSubdomainName(name), name)
+		}
+		for _, name := range []string{"a.b", "a-b.c-d", "1.2.3.4"} {
+			assert.True(t, IsValidDNSSubdomainName(name), name)
+		}
+	})
+
+	t.Run("every label must be at most 63 characters", func(t *testing.T) {
+		for _, name := range []string{strings.Repeat("a", 64), strings.Repeat("a", 64) + ".io", "docker.io." + strings.Repeat("a", 64), "a." + strings.Repeat("a", 64) + ".b"} {
+			assert.False(t, IsValidDNSSubdomainName(name), name)
+			assert.False(t, IsValidDNSSubdomainBytes([]byte(name)), name)
+		}
+		for _, name := range []string{strings.Repeat("a", 63), strings.Repeat("a", 63) + ".io", "docker.io." + strings.Repeat("a", 63)} {
+			assert.True(t, IsValidDNSSubdomainName(name), name)
+			assert.True(t, IsValidDNSSubdomainBytes([]byte(name)), name)
+		}
+	})
+
+	t.Run("slugs, like Kubernetes object names, do not limit labels on their own", func(t *testing.T) {
+		name := strings.Repeat("a", 64) + ".io"
+
+		assert.False(t, IsValidDNSSubdomainName(name))
+		assert.True(t, IsValidSlug(name))
+	})
+
+	t.Run("overall name must be at most 253 characters", func(t *testing.T) {
+		label := strings.Repeat("a", 63)
+		assert.True(t, IsValidDNSSubdomainName(strings.Join([]string{label, label, label, strings.Repeat("a", 61)}, ".")))
+		assert.False(t, IsValidDNSSubdomainName(strings.Join([]string{label, label, label, strings.Repeat("a", 62)}, ".")))
+		assert.False(t, IsValidDNSSubdomainName(strings.Repeat("a.", 126)+"aa"))
+		assert.True(t, IsValidSlug(strings.Repeat("a", 253)))
+		assert.False(t, IsValidSlug(strings.Repeat("a", 254)))
+	})
+}
+
 func TestIsValidDSNLabelName(t *testing.T) {
 	tt := []struct {
 		name      string
@@ -339,12 +1984,18 @@ func TestIsValidDSNLabelName(t *testing.T) {
 			inputName: "nginX",
 			want:      false,
 		},
+		{
+			name:      "Single-character name should be valid",
+			inputName: "a",
+			want:      true,
+		},
 	}
 
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
 			got := IsValidDNSLabelName(tc.inputName)
 			assert.Equal(t, tc.want, got)
+			assert.Equal(t, tc.want, IsValidDNSLabelBytes([]byte(tc.inputName)))
 		})
 	}
 }
@@ -383,6 +2034,8 @@ func TestToValidLabelValue(t *testing.T) {
 		{"valid_value", "valid_value"},
 		{"1Valid.value", "1Valid.value"},
 		{"1number", "1number"},
+		{"", ""},
+		{"///", ""},
 		{"$special:char", "special-char"},
 		{"-very_long_value_that:is_more@than_63_characters_long_and_should_fail_validation", "very_long_value_that-is_more-than_63_characters_long_and_should"},
 	}
@@ -463,6 +2116,16 @@ func TestToValidDNSSubdomainName(t *testing.T) {
 			inputName: "nGinx",
 			want:      "nginx",
 		},
+		{
+			name:      "Empty labels are dropped",
+			inputName: "docker..io",
+			want:      "docker.io",
+		},
+		{
+			name:      "Labels are trimmed to alphanumeric boundaries",
+			inputName: "docker_.-io",
+			want:      "docker.io",
+		},
 	}
 
 	for _, tc := range tt {
@@ -478,6 +2141,308 @@ func TestToValidDNSSubdomainName(t *testing.T) {
 	}
 }
 
+func TestAppendSuffix(t *testing.T) {
+	tt := []struct {
+		name      string
+		inputName string
+		suffix    string
+		want      string
+		wantErr   error
+	}{
+		{
+			name:      "suffix is appended to a short name",
+			inputName: "nginx-latest-a3ac8c",
+			suffix:    "-ro",
+			want:      "nginx-latest-a3ac8c-ro",
+		},
+		{
+			name:      "suffix without a separator is appended",
+			inputName: "nginx-latest-a3ac8c",
+			suffix:    "ro",
+			want:      "nginx-latest-a3ac8c-ro",
+		},
+		{
+			name:      "near-limit name is truncated to fit the suffix",
+			inputName: strings.Repeat("a", 252),
+			suffix:    "-ro",
+			want:      strings.Repeat("a", 250) + "-ro",
+		},
+		{
+			name:      "truncation does not leave a boundary before the suffix",
+			inputName: strings.Repeat("a", 249) + ".bbbb",
+			suffix:    "-ro",
+			want:      strings.Repeat("a", 249) + "-ro",
+		},
+		{
+			name:      "invalid suffix produces matching error",
+			inputName: "nginx-latest-a3ac8c",
+			suffix:    "-r.o",
+			wantErr:   ErrInvalidSlug,
+		},
+		{
+			name:      "invalid name produces matching error",
+			inputName: "Nginx",
+			suffix:    "-ro",
+			wantErr:   ErrInvalidSlug,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := AppendSuffix(tc.inputName, tc.suffix)
+
+			assert.Equal(t, tc.want, got)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestTrimSlug(t *testing.T) {
+	tt := []struct {
+		name      string
+		inputName string
+		want      string
+	}{
+		{
+			name:      "Clean name is returned as is",
+			inputName: "nginx-latest",
+			want:      "nginx-latest",
+		},
+		{
+			name:      "Stray and repeated separators are removed",
+			inputName: "-nginx--latest-",
+			want:      "nginx-latest",
+		},
+		{
+			name:      "Only separators produce an empty name",
+			inputName: "---",
+			want:      "",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got := TrimSlug(tc.inputName)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestToValidDNSLabelName(t *testing.T) {
+	tt := []struct {
+		name          string
+		inputName     string
+		want          string
+		expectedError bool
+	}{
+		{
+			name:      "Valid label is returned as is",
+			inputName: "nginx",
+			want:      "nginx",
+		},
+		{
+			name:      "Periods and uppercase characters are transformed",
+			inputName: "Docker.io",
+			want:      "docker-io",
+		},
+		{
+			name:      "Names over 63 characters are truncated to limit",
+			inputName: strings.Repeat("a", 70),
+			want:      strings.Repeat("a", 63),
+		},
+		{
+			name:          "Input without alphanumeric characters returns an error",
+			inputName:     "///",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ToValidDNSLabelName(tc.inputName)
+			if tc.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestIsValidDNSLabelNameRFC1035(t *testing.T) {
+	tt := []struct {
+		name      string
+		inputName string
+		want      bool
+	}{
+		{
+			name:      "Name starting with a letter is valid",
+			inputName: "nginx-1",
+			want:      true,
+		},
+		{
+			name:      "Name starting with a digit is invalid",
+			inputName: "1-nginx",
+			want:      false,
+		},
+		{
+			name:      "Name that is not a DNS label is invalid",
+			inputName: "nginx.svc",
+			want:      false,
+		},
+		{
+			name:      "Empty name is invalid",
+			inputName: "",
+			want:      false,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, IsValidDNSLabelNameRFC1035(tc.inputName))
+		})
+	}
+}
+
+func TestToValidDNSLabelNameRFC1035(t *testing.T) {
+	tt := []struct {
+		name          string
+		inputName     string
+		opts          []Option
+		want          string
+		expectedError bool
+	}{
+		{
+			name:      "Valid label is returned as is",
+			inputName: "nginx",
+			want:      "nginx",
+		},
+		{
+			name:      "Leading digits and hyphens are trimmed",
+			inputName: "1-Nginx.svc",
+			want:      "nginx-svc",
+		},
+		{
+			name:          "Input without letters returns an error",
+			inputName:     "1234",
+			expectedError: true,
+		},
+		{
+			name:      "Input without letters returns the placeholder if set",
+			inputName: "1234",
+			opts:      []Option{WithEmptyPlaceholder(DefaultEmptyPlaceholder)},
+			want:      "unknown",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ToValidDNSLabelNameRFC1035(tc.inputName, tc.opts...)
+			if tc.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.want, got)
+				assert.True(t, IsValidDNSLabelNameRFC1035(got))
+			}
+		})
+	}
+}
+
+func TestToValidDNSLabelNameWithEmptyPlaceholder(t *testing.T) {
+	tt := []struct {
+		name        string
+		inputName   string
+		placeholder string
+		want        string
+		wantErr     bool
+	}{
+		{
+			name:        "input that sanitizes to nothing returns the placeholder",
+			inputName:   "///",
+			placeholder: DefaultEmptyPlaceholder,
+			want:        "unknown",
+		},
+		{
+			name:        "empty placeholder returns the default placeholder",
+			inputName:   "///",
+			placeholder: "",
+			want:        "unknown",
+		},
+		{
+			name:        "custom placeholder is returned",
+			inputName:   "///",
+			placeholder: "none",
+			want:        "none",
+		},
+		{
+			name:        "regular input is unaffected",
+			inputName:   "Web/App",
+			placeholder: DefaultEmptyPlaceholder,
+			want:        "web-app",
+		},
+		{
+			name:        "invalid placeholder still returns an error",
+			inputName:   "///",
+			placeholder: "not valid",
+			want:        "",
+			wantErr:     true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ToValidDNSLabelName(tc.inputName, WithEmptyPlaceholder(tc.placeholder))
+
+			assert.Equal(t, tc.want, got)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNeedsSanitization(t *testing.T) {
+	tt := []struct {
+		name             string
+		inputName        string
+		wantForSubdomain bool
+		wantForLabel     bool
+	}{
+		{
+			name:      "Clean name needs no sanitization",
+			inputName: "nginx",
+		},
+		{
+			name:             "Periods need sanitization only for labels",
+			inputName:        "docker.io",
+			wantForSubdomain: false,
+			wantForLabel:     true,
+		},
+		{
+			name:             "Dirty name needs sanitization",
+			inputName:        "Docker.io/nginx:latest",
+			wantForSubdomain: true,
+			wantForLabel:     true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.wantForSubdomain, NeedsSanitizationDNSSubdomain(tc.inputName))
+			assert.Equal(t, tc.wantForLabel, NeedsSanitizationDNSLabel(tc.inputName))
+
+			sanitizedSubdomain, _ := ToValidDNSSubdomainName(tc.inputName)
+			assert.Equal(t, tc.wantForSubdomain, sanitizedSubdomain != tc.inputName)
+			sanitizedLabel, _ := ToValidDNSLabelName(tc.inputName)
+			assert.Equal(t, tc.wantForLabel, sanitizedLabel != tc.inputName)
+		})
+	}
+}
+
 func TestStringToSlug(t *testing.T) {
 	testCases := []struct {
 		name     string
@@ -637,3 +2602,63 @@ func TestRoleBindingResourceToSlug(t *testing.T) {
 		})
 	}
 }
+
+func BenchmarkIsValidDNSSubdomainBytes(b *testing.B) {
+	name := []byte("docker.io-nginx-latest-a3ac8c")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		IsValidDNSSubdomainBytes(name)
+	}
+}
+
+// registryHostnames are the hostnames of common registries. Validating one takes a few nanoseconds,
+// less than a cache lookup would, so that repeated validations are not worth caching
+var registryHostnames = []string{"docker.io", "quay.io", "ghcr.io", "registry.k8s.io", "123456789012.dkr.ecr.us-east-1.amazonaws.com", "myregistry.azurecr.io"}
+
+func BenchmarkIsValidDNSSubdomainNameRegistryHostnames(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		IsValidDNSSubdomainName(registryHostnames[i%len(registryHostnames)])
+	}
+}
+
+func BenchmarkIsValidDNSLabelBytes(b *testing.B) {
+	name := []byte("service-webapp-webapp-1ba5-4aaf")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		IsValidDNSLabelBytes(name)
+	}
+}
+
+func BenchmarkImageInfoToSlug(b *testing.B) {
+	imageHash := "f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac8c"
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = ImageInfoToSlug("Quay.io/kubescape/kubevuln:v0.2.108", imageHash)
+	}
+}
+
+func BenchmarkInstanceIDToSlug(b *testing.B) {
+	hashedID := "1ba506b28f9ee9c7e8a0c98840fe5a1fe21142d225ecc526fbb535d0d6344aaf"
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = InstanceIDToSlug("reverse-proxy", "Pod", "nginx", hashedID)
+	}
+}
+
+func TestSlugGenerationAllocations(t *testing.T) {
+	imageHash := "f4e3b6489888647ce1834b601c6c06b9f8c03dee6e097e13ed3e28c01ea3ac8c"
+
+	imageAllocs := testing.AllocsPerRun(100, func() {
+		_, _ = ImageInfoToSlug("Quay.io/kubescape/kubevuln:v0.2.108", imageHash)
+	})
+	instanceAllocs := testing.AllocsPerRun(100, func() {
+		_, _ = InstanceIDToSlug("reverse-proxy", "Pod", "nginx", imageHash)
+	})
+
+	// generating a slug only allocates the slug itself
+	assert.Equal(t, float64(1), imageAllocs)
+	assert.Equal(t, float64(1), instanceAllocs)
+}
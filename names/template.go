@@ -0,0 +1,134 @@
+package names
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// NameFields are the fields of a resource that a NameTemplate lays out into a name
+type NameFields struct {
+	Namespace     string
+	Kind          string
+	Name          string
+	ContainerName string
+	// Hash is a hex-encoded hash identifying the resource, e.g. a hashed instance ID
+	Hash string
+}
+
+// nameTemplateData is the data a NameTemplate layout is executed with
+type nameTemplateData struct {
+	Namespace     string
+	Kind          string
+	Name          string
+	ContainerName string
+	Hash          string
+	HashPrefix    string
+	HashSuffix    string
+}
+
+// NameTemplate generates names laid out by a text/template layout, for backends with their own naming conventions
+//
+// The layout can refer to the fields .Namespace, .Kind, .Name, .ContainerName and .Hash, as well as .HashPrefix and .HashSuffix,
+// the first and last characters of the hash, as many as the hash length the template was created with (4 by default).
+// Every field is sanitized into a DNS Subdomain name before it is laid out, and empty fields stay empty,
+// so optional fields can be guarded, e.g. "{{with .Namespace}}{{.}}-{{end}}{{.Kind}}-{{.Name}}-{{.HashPrefix}}"
+type NameTemplate struct {
+	template     *template.Template
+	maxLength    int
+	hashLength   int
+	strictLabels bool
+}
+
+// NewNameTemplate returns a NameTemplate with a given layout, e.g. "{{.Namespace}}-{{.Kind}}-{{.Name}}-{{.HashPrefix}}"
+//
+// The template honours the maximum length, hash suffix length and strict labels of the given options.
+// If the layout cannot be parsed or refers to unknown fields, it returns an error wrapping ErrInvalidNameTemplate
+func NewNameTemplate(layout string, opts ...Option) (*NameTemplate, error) {
+	tmpl, err := template.New("name").Option("missingkey=error").Parse(layout)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidNameTemplate, err)
+	}
+	// a dry run catches references to unknown fields before any name is generated
+	if err := tmpl.Execute(&strings.Builder{}, nameTemplateData{}); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidNameTemplate, err)
+	}
+
+	options := resolveOptions(opts)
+	return &NameTemplate{
+		template:     tmpl,
+		maxLength:    options.MaxLength,
+		hashLength:   options.instanceHashLength(),
+		strictLabels: options.StrictLabels,
+	}, nil
+}
+
+// Execute returns the name that the given fields are laid out into
+//
+// If the name is too long, the name field is truncated until it fits, keeping every other field intact.
+// If a field sanitizes to nothing, the hash is too short for the hash prefix and suffix or the result is not a valid DNS Subdomain name,
+// it returns an error wrapping ErrInvalidSlug. With strict labels, a label longer than 63 characters returns ErrNameTooLong
+func (t *NameTemplate) Execute(fields NameFields) (string, error) {
+	data := nameTemplateData{}
+	for _, field := range []struct {
+		name  string
+		value string
+		into  *string
+	}{
+		{name: "namespace", value: fields.Namespace, into: &data.Namespace},
+		{name: "kind", value: fields.Kind, into: &data.Kind},
+		{name: "name", value: fields.Name, into: &data.Name},
+		{name: "container", value: fields.ContainerName, into: &data.ContainerName},
+		{name: "hash", value: fields.Hash, into: &data.Hash},
+	} {
+		if field.value == "" {
+			continue
+		}
+		value, err := ToValidDNSSubdomainName(field.value)
+		if err != nil {
+			return "", fmt.Errorf("%w: invalid %s field %q", ErrInvalidSlug, field.name, field.value)
+		}
+		*field.into = value
+	}
+
+	if data.Hash != "" {
+		if len(data.Hash) < t.hashLength {
+			return "", ErrInvalidHash
+		}
+		data.HashPrefix, data.HashSuffix = data.Hash[:t.hashLength], data.Hash[len(data.Hash)-t.hashLength:]
+	}
+
+	name, err := t.execute(data)
+	if err != nil {
+		return "", err
+	}
+	// shorten the name field by the overflow until the result fits, the name may be laid out more than once
+	for len(name) > t.maxLength {
+		if data.Name == "" {
+			return "", ErrNameTooLong
+		}
+		data.Name = strings.TrimFunc(data.Name[:max(len(data.Name)-(len(name)-t.maxLength), 0)], isNonAlphanumeric)
+		if data.Name == "" {
+			return "", ErrNameTooLong
+		}
+		if name, err = t.execute(data); err != nil {
+			return "", err
+		}
+	}
+
+	if !isValidObjectName(name, t.maxLength) {
+		return "", fmt.Errorf("%w: %q is not a valid DNS Subdomain name", ErrInvalidSlug, name)
+	}
+	if err := (Options{StrictLabels: t.strictLabels}).checkLabels(name, "name"); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+func (t *NameTemplate) execute(data nameTemplateData) (string, error) {
+	var b strings.Builder
+	if err := t.template.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidNameTemplate, err)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,133 @@
+package names
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewNameTemplate(t *testing.T) {
+	tt := []struct {
+		name    string
+		layout  string
+		wantErr bool
+	}{
+		{
+			name:   "Layout with known fields",
+			layout: "{{.Namespace}}-{{.Kind}}-{{.Name}}-{{.HashPrefix}}",
+		},
+		{
+			name:    "Unparseable layout",
+			layout:  "{{.Namespace}-{{.Name}}",
+			wantErr: true,
+		},
+		{
+			name:    "Unknown field",
+			layout:  "{{.Cluster}}-{{.Name}}",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewNameTemplate(tc.layout)
+			if tc.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidNameTemplate)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestNameTemplateExecute(t *testing.T) {
+	hashedID := "1ba506b28f9ee9c7e8a0c98840fe5a1fe21142d225ecc526fbb535d0d6344aaf"
+	tt := []struct {
+		name    string
+		layout  string
+		opts    []Option
+		fields  NameFields
+		want    string
+		wantErr error
+	}{
+		{
+			name:   "Fields are laid out and sanitized",
+			layout: "{{.Namespace}}-{{.Kind}}-{{.Name}}-{{.HashPrefix}}",
+			fields: NameFields{Namespace: "default", Kind: "Deployment", Name: "Web_App", Hash: hashedID},
+			want:   "default-deployment-web-app-1ba5",
+		},
+		{
+			name:   "Hash-first layout",
+			layout: "{{.HashPrefix}}-{{.HashSuffix}}-{{.Name}}",
+			fields: NameFields{Name: "nginx", Hash: hashedID},
+			want:   "1ba5-4aaf-nginx",
+		},
+		{
+			name:   "Hash length follows the options",
+			layout: "{{.Name}}-{{.HashSuffix}}",
+			opts:   []Option{WithHashSuffixLen(8)},
+			fields: NameFields{Name: "nginx", Hash: hashedID},
+			want:   "nginx-d6344aaf",
+		},
+		{
+			name:   "Optional fields can be guarded",
+			layout: "{{with .Namespace}}{{.}}-{{end}}{{.Kind}}-{{.Name}}",
+			fields: NameFields{Kind: "ClusterRole", Name: "admin"},
+			want:   "clusterrole-admin",
+		},
+		{
+			name:   "Long names are truncated to fit, keeping the hash",
+			layout: "{{.Kind}}-{{.Name}}-{{.HashPrefix}}",
+			opts:   []Option{WithMaxLen(30)},
+			fields: NameFields{Kind: "Pod", Name: strings.Repeat("a", 40), Hash: hashedID},
+			want:   "pod-" + strings.Repeat("a", 21) + "-1ba5",
+		},
+		{
+			name:    "Layout without room for the name",
+			layout:  "{{.Kind}}-{{.Name}}-{{.Hash}}",
+			fields:  NameFields{Kind: "Pod", Name: "nginx", Hash: strings.Repeat("a", 250)},
+			wantErr: ErrNameTooLong,
+		},
+		{
+			name:    "Hash too short for the prefix",
+			layout:  "{{.Name}}-{{.HashPrefix}}",
+			fields:  NameFields{Name: "nginx", Hash: "abc"},
+			wantErr: ErrInvalidHash,
+		},
+		{
+			name:    "Field that sanitizes to nothing",
+			layout:  "{{.Kind}}-{{.Name}}",
+			fields:  NameFields{Kind: "Pod", Name: "___"},
+			wantErr: ErrInvalidSlug,
+		},
+		{
+			name:    "Layout that produces an invalid name",
+			layout:  "{{.Namespace}}-{{.Name}}",
+			fields:  NameFields{Name: "nginx"},
+			wantErr: ErrInvalidSlug,
+		},
+		{
+			name:    "Strict labels reject a long label",
+			layout:  "{{.Kind}}-{{.Name}}",
+			opts:    []Option{WithStrictLabels()},
+			fields:  NameFields{Kind: "Pod", Name: strings.Repeat("a", 70)},
+			wantErr: ErrNameTooLong,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpl, err := NewNameTemplate(tc.layout, tc.opts...)
+			assert.NoError(t, err)
+
+			got, err := tmpl.Execute(tc.fields)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
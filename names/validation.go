@@ -0,0 +1,105 @@
+package names
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// maxQualifiedNameLength is the maximum length of the name part of a label key
+	maxQualifiedNameLength = 63
+	// jsonPathSpecialChars are the characters that need escaping or quoting in a JSONPath segment
+	jsonPathSpecialChars = `.[]'"\`
+	// maxAnnotationsSize is the maximum total size in bytes of the annotations of a Kubernetes object
+	maxAnnotationsSize = 256 * (1 << 10)
+)
+
+var qualifiedNameRegexp = regexp.MustCompile(`^([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]$`)
+
+// ValidateObjectNames validates the names that make up the metadata of a Kubernetes object
+//
+// The name and generateName are validated as DNS Subdomain names, the namespace as a DNS label name and the labels as label keys and values.
+// Empty fields are skipped, except that either a name or a generateName is required.
+// It returns all validation errors joined together, each of them naming the offending field and wrapping ErrInvalidName
+func ValidateObjectNames(name, generateName, namespace string, labels map[string]string) error {
+	var errs []error
+
+	switch {
+	case name == "" && generateName == "":
+		errs = append(errs, fmt.Errorf("%w: metadata.name: name or generateName is required", ErrInvalidName))
+	case name != "" && !isValidObjectName(name, maxDNSSubdomainLength):
+		errs = append(errs, fmt.Errorf("%w: metadata.name: %q is not a valid DNS Subdomain name", ErrInvalidName, name))
+	}
+
+	// generateName is a prefix, so it may end with a hyphen
+	if generateName != "" && !isValidObjectName(maskTrailingDash(generateName), maxDNSSubdomainLength) {
+		errs = append(errs, fmt.Errorf("%w: metadata.generateName: %q is not a valid DNS Subdomain name prefix", ErrInvalidName, generateName))
+	}
+
+	if namespace != "" && !IsValidDNSLabelName(namespace) {
+		errs = append(errs, fmt.Errorf("%w: metadata.namespace: %q is not a valid DNS label name", ErrInvalidName, namespace))
+	}
+
+	// sort the keys for a deterministic error
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !isValidLabelKey(key) {
+			errs = append(errs, fmt.Errorf("%w: metadata.labels: %q is not a valid label key", ErrInvalidName, key))
+		}
+		if !IsValidLabelValue(labels[key]) {
+			errs = append(errs, fmt.Errorf("%w: metadata.labels[%s]: %q is not a valid label value", ErrInvalidName, key, labels[key]))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ValidateAnnotationsSize validates that the total size of a given set of annotations fits the Kubernetes limit of 256KiB
+//
+// The size is the sum of the bytes of every key and value, like Kubernetes computes it.
+// It returns an error with the total size, wrapping ErrAnnotationsTooLarge, when the limit is exceeded
+func ValidateAnnotationsSize(annotations map[string]string) error {
+	var totalSize int
+	for key, value := range annotations {
+		totalSize += len(key) + len(value)
+	}
+	if totalSize > maxAnnotationsSize {
+		return fmt.Errorf("%w: metadata.annotations: %d bytes, must have at most %d bytes", ErrAnnotationsTooLarge, totalSize, maxAnnotationsSize)
+	}
+	return nil
+}
+
+// IsValidJSONPathSegmentName returns true if a given name can be used as an unescaped JSONPath segment
+//
+// Names with dots, brackets, quotes or backslashes, such as image slugs with a registry like "docker.io-nginx-latest-a3ac8c",
+// have to be quoted or sanitized before they are used in a JSONPath expression
+func IsValidJSONPathSegmentName(name string) bool {
+	return name != "" && !strings.ContainsAny(name, jsonPathSpecialChars)
+}
+
+// isValidLabelKey returns true if a given string is a valid label key: an optional DNS Subdomain prefix and a name separated by a slash
+func isValidLabelKey(key string) bool {
+	name := key
+	if prefix, suffix, found := strings.Cut(key, "/"); found {
+		if !isValidObjectName(prefix, maxDNSSubdomainLength) {
+			return false
+		}
+		name = suffix
+	}
+	return len(name) <= maxQualifiedNameLength && qualifiedNameRegexp.MatchString(name)
+}
+
+// maskTrailingDash replaces a trailing hyphen with an alphanumeric character, so a name prefix can be validated as a name
+func maskTrailingDash(name string) string {
+	if len(name) > 1 && strings.HasSuffix(name, "-") {
+		return name[:len(name)-1] + "a"
+	}
+	return name
+}
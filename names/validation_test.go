@@ -0,0 +1,165 @@
+package names
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateObjectNames(t *testing.T) {
+	tt := []struct {
+		name          string
+		inputName     string
+		generateName  string
+		namespace     string
+		labels        map[string]string
+		wantErr       bool
+		errorContains []string
+	}{
+		{
+			name:         "Fully valid set of names",
+			inputName:    "nginx-a3ac8c",
+			generateName: "nginx-",
+			namespace:    "default",
+			labels: map[string]string{
+				"app":                    "nginx",
+				"kubescape.io/image-tag": "nginx_latest",
+			},
+		},
+		{
+			name:         "Generated names only need a valid prefix",
+			generateName: "nginx-",
+			namespace:    "default",
+		},
+		{
+			name:          "Missing name and generateName",
+			namespace:     "default",
+			wantErr:       true,
+			errorContains: []string{"metadata.name"},
+		},
+		{
+			name:      "Bad label value is reported with its key",
+			inputName: "nginx-a3ac8c",
+			namespace: "default",
+			labels: map[string]string{
+				"app": "docker.io/nginx:latest",
+			},
+			wantErr:       true,
+			errorContains: []string{"metadata.labels[app]"},
+		},
+		{
+			name:      "All invalid fields are reported",
+			inputName: "Nginx",
+			namespace: "my.namespace",
+			labels: map[string]string{
+				"-app": "nginx",
+			},
+			wantErr:       true,
+			errorContains: []string{"metadata.name", "metadata.namespace", `metadata.labels: "-app"`},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateObjectNames(tc.inputName, tc.generateName, tc.namespace, tc.labels)
+			if !tc.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, ErrInvalidName)
+			for _, contained := range tc.errorContains {
+				assert.ErrorContains(t, err, contained)
+			}
+		})
+	}
+}
+
+func TestIsValidJSONPathSegmentName(t *testing.T) {
+	tt := []struct {
+		name      string
+		inputName string
+		want      bool
+	}{
+		{
+			name:      "Image slug without a registry is valid",
+			inputName: "nginx-a3ac8c",
+			want:      true,
+		},
+		{
+			name:      "Instance ID slug is valid",
+			inputName: "service-webapp-webapp-1ba5-4aaf",
+			want:      true,
+		},
+		{
+			name:      "Image slug with a registry is flagged",
+			inputName: "docker.io-nginx-latest-a3ac8c",
+			want:      false,
+		},
+		{
+			name:      "Brackets are flagged",
+			inputName: "items[0]",
+			want:      false,
+		},
+		{
+			name:      "Quotes are flagged",
+			inputName: `it's`,
+			want:      false,
+		},
+		{
+			name:      "Empty name is flagged",
+			inputName: "",
+			want:      false,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, IsValidJSONPathSegmentName(tc.inputName))
+		})
+	}
+}
+
+func TestValidateAnnotationsSize(t *testing.T) {
+	key := "kubescape.io/image-slug"
+	tt := []struct {
+		name        string
+		annotations map[string]string
+		wantErr     bool
+	}{
+		{
+			name:        "No annotations",
+			annotations: nil,
+		},
+		{
+			name:        "Annotations exactly at the limit",
+			annotations: map[string]string{key: strings.Repeat("a", 256*1024-len(key))},
+		},
+		{
+			name:        "Annotations just over the limit",
+			annotations: map[string]string{key: strings.Repeat("a", 256*1024-len(key)+1)},
+			wantErr:     true,
+		},
+		{
+			name: "Size is summed across annotations",
+			annotations: map[string]string{
+				"a": strings.Repeat("a", 128*1024-1),
+				"b": strings.Repeat("b", 128*1024),
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateAnnotationsSize(tc.annotations)
+			if !tc.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorIs(t, err, ErrAnnotationsTooLarge)
+			assert.Contains(t, err.Error(), "262145 bytes")
+		})
+	}
+}